@@ -0,0 +1,187 @@
+package curve
+
+import (
+	"fmt"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Bezier is the Bézier curve Σ B_i,n(t) * P[i] for t in [0, 1], where B_i,n are the
+// Bernstein polynomials of degree n = len(P) - 1
+type Bezier struct {
+	P []rn.Vec
+}
+
+func (o Bezier) String() (str string) {
+	str += fmt.Sprintf("Bézier %v", o.P)
+	return
+}
+
+// MakeBezier returns a Bézier curve with the given control points
+//
+// Parameters:
+//
+//	P ...rn.Vec - The control points, at least one, all of the same dimension
+//
+// Returns:
+//
+//	bez Bezier - The curve from P[0] to P[len(P)-1]
+func MakeBezier(P ...rn.Vec) (bez Bezier) {
+	checkPoints(P)
+	bez.P = P
+	return
+}
+
+// checkPoints panics if Ps is empty or its points differ in dimension
+func checkPoints(Ps []rn.Vec) {
+	if len(Ps) == 0 {
+		panic(errors.ErrZeroLengthVec)
+	}
+	for _, P := range Ps {
+		if P.N != Ps[0].N {
+			panic(errors.ErrShape)
+		}
+	}
+}
+
+// Degree returns the degree of the curve
+//
+// Parameters:
+//
+//	o *Bezier - The curve
+//
+// Returns:
+//
+//	n int - The number of control points minus one
+func (o *Bezier) Degree() (n int) {
+	n = len(o.P) - 1
+	return
+}
+
+// Domain returns the parameter interval of the curve
+//
+// Parameters:
+//
+//	o *Bezier - The curve
+//
+// Returns:
+//
+//	t0 float64 - 0
+//	t1 float64 - 1
+func (o *Bezier) Domain() (t0, t1 float64) {
+	t0, t1 = 0, 1
+	return
+}
+
+// deCasteljau returns all intermediate points of de Casteljau's algorithm, level k has len(P) - k points
+func (o *Bezier) deCasteljau(t float64) (levels [][]rn.Vec) {
+	levels = [][]rn.Vec{o.P}
+	for k := 1; k < len(o.P); k++ {
+		prev := levels[k-1]
+		level := make([]rn.Vec, len(prev)-1)
+		for i := range level {
+			a, b := prev[i].Scale(1-t), prev[i+1].Scale(t)
+			level[i] = a.Add(b)
+		}
+		levels = append(levels, level)
+	}
+	return
+}
+
+// At returns the point of the curve at parameter t using de Casteljau's algorithm
+//
+// Parameters:
+//
+//	t float64 - The parameter, values outside [0, 1] extrapolate
+//
+// Returns:
+//
+//	P1 rn.Vec - The point
+func (o *Bezier) At(t float64) (P1 rn.Vec) {
+	levels := o.deCasteljau(t)
+	P1 = levels[len(levels)-1][0]
+	return
+}
+
+// Subdivide splits the curve at parameter t
+//
+// Parameters:
+//
+//	t float64 - The parameter of the split point
+//
+// Returns:
+//
+//	left Bezier - The curve that traces o on [0, t]
+//	right Bezier - The curve that traces o on [t, 1]
+func (o *Bezier) Subdivide(t float64) (left, right Bezier) {
+	levels := o.deCasteljau(t)
+	n := len(o.P)
+	left.P, right.P = make([]rn.Vec, n), make([]rn.Vec, n)
+	for k, level := range levels {
+		left.P[k] = level[0]
+		right.P[n-1-k] = level[len(level)-1]
+	}
+	return
+}
+
+// Derivative returns the hodograph of the curve
+//
+// Parameters:
+//
+//	o *Bezier - The curve
+//
+// Returns:
+//
+//	der Bezier - The curve of degree n - 1 with control points n * (P[i+1] - P[i]), the
+//	constant zero curve if o has degree 0
+func (o *Bezier) Derivative() (der Bezier) {
+	n := len(o.P) - 1
+	if n == 0 {
+		der.P = []rn.Vec{rn.MakeVec(o.P[0].N, 0)}
+		return
+	}
+	der.P = make([]rn.Vec, n)
+	for i := range der.P {
+		d := o.P[i+1].Sub(o.P[i])
+		der.P[i] = d.Scale(float64(n))
+	}
+	return
+}
+
+// Deriv returns the first derivative of the curve at parameter t
+//
+// Parameters:
+//
+//	t float64 - The parameter
+//
+// Returns:
+//
+//	D1 rn.Vec - The tangent vector dC/dt
+func (o *Bezier) Deriv(t float64) (D1 rn.Vec) {
+	der := o.Derivative()
+	D1 = der.At(t)
+	return
+}
+
+// Elevate returns the same curve with its degree raised by one
+//
+// Parameters:
+//
+//	o *Bezier - The curve
+//
+// Returns:
+//
+//	bez Bezier - The curve of degree n + 1 with control points
+//	i / (n + 1) * P[i-1] + (1 - i / (n + 1)) * P[i]
+func (o *Bezier) Elevate() (bez Bezier) {
+	n := len(o.P)
+	bez.P = make([]rn.Vec, n+1)
+	bez.P[0], bez.P[n] = o.P[0], o.P[n-1]
+	for i := 1; i < n; i++ {
+		a := float64(i) / float64(n)
+		prev, cur := o.P[i-1].Scale(a), o.P[i].Scale(1-a)
+		bez.P[i] = prev.Add(cur)
+	}
+	return
+}
@@ -0,0 +1,65 @@
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func v2(x, y float64) rn.Vec {
+	return rn.Vec{N: 2, X: []float64{x, y}}
+}
+
+func vecClose(a, b rn.Vec, tol float64) bool {
+	if a.N != b.N {
+		return false
+	}
+	for i := range a.X {
+		if math.Abs(a.X[i]-b.X[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBezier(t *testing.T) {
+	quad := MakeBezier(v2(0, 0), v2(1, 2), v2(2, 0))
+	if got := quad.At(0.5); !vecClose(got, v2(1, 1), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v2(1, 1))
+	}
+	if got := quad.Deriv(0); !vecClose(got, v2(2, 4), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v2(2, 4))
+	}
+
+	cubic := MakeBezier(v2(0, 0), v2(1, 3), v2(3, -1), v2(4, 2))
+	left, right := cubic.Subdivide(0.3)
+	elevated := cubic.Elevate()
+	der := cubic.Derivative()
+	if elevated.Degree() != 4 || der.Degree() != 2 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", elevated.Degree(), der.Degree(), 4, 2)
+	}
+	for _, s := range []float64{0, 0.2, 0.5, 0.9, 1} {
+		if got, want := left.At(s), cubic.At(0.3*s); !vecClose(got, want, 1e-14) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+		if got, want := right.At(s), cubic.At(0.3+0.7*s); !vecClose(got, want, 1e-14) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+		if got, want := elevated.At(s), cubic.At(s); !vecClose(got, want, 1e-14) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+		// central differences
+		h := 1e-6
+		a, b := cubic.At(s+h), cubic.At(s-h)
+		diff := a.Sub(b)
+		if got, want := cubic.Deriv(s), diff.Scale(1/(2*h)); !vecClose(got, want, 1e-8) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+	}
+
+	point := MakeBezier(v2(1, 2))
+	if got := point.Deriv(0.5); !vecClose(got, v2(0, 0), 0) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v2(0, 0))
+	}
+}
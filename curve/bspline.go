@@ -0,0 +1,302 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// BSpline is the B-spline curve Σ N_i,p(t) * W[i] * P[i] / Σ N_i,p(t) * W[i] of degree p
+// over a knot vector
+//
+// The basis functions N_i,p are defined by the Cox-de Boor recursion on
+// Knots, which has len(P) + p + 1 non-decreasing entries. The curve is a
+// NURBS curve if W holds a positive weight for every control point and a
+// polynomial B-spline if W is nil.
+type BSpline struct {
+	Degree int
+	Knots  []float64
+	P      []rn.Vec
+	W      []float64
+}
+
+func (o BSpline) String() (str string) {
+	if o.W != nil {
+		str += fmt.Sprintf("NURBS degree %d, knots %v, points %v, weights %v", o.Degree, o.Knots, o.P, o.W)
+		return
+	}
+	str += fmt.Sprintf("B-spline degree %d, knots %v, points %v", o.Degree, o.Knots, o.P)
+	return
+}
+
+// MakeBSpline returns a polynomial B-spline curve
+//
+// Parameters:
+//
+//	degree int - The degree p, at least 1
+//	knots []float64 - The len(P) + p + 1 non-decreasing knots, with knots[p] < knots[len(P)]
+//	P []rn.Vec - The control points, at least p + 1, all of the same dimension
+//
+// Returns:
+//
+//	spl BSpline - The curve over the domain [knots[p], knots[len(P)]]
+func MakeBSpline(degree int, knots []float64, P []rn.Vec) (spl BSpline) {
+	checkPoints(P)
+	if degree < 1 || len(P) <= degree {
+		panic(errors.ErrOrder)
+	}
+	if len(knots) != len(P)+degree+1 {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	for i := 1; i < len(knots); i++ {
+		if knots[i] < knots[i-1] {
+			panic(errors.ErrOrder)
+		}
+	}
+	if knots[degree] >= knots[len(P)] {
+		panic(errors.ErrOrder)
+	}
+	spl = BSpline{Degree: degree, Knots: knots, P: P}
+	return
+}
+
+// MakeNURBS returns a rational B-spline curve
+//
+// Parameters:
+//
+//	degree int - The degree p, at least 1
+//	knots []float64 - The len(P) + p + 1 non-decreasing knots, with knots[p] < knots[len(P)]
+//	P []rn.Vec - The control points, at least p + 1, all of the same dimension
+//	W []float64 - The positive weights of the control points
+//
+// Returns:
+//
+//	spl BSpline - The curve over the domain [knots[p], knots[len(P)]]
+func MakeNURBS(degree int, knots []float64, P []rn.Vec, W []float64) (spl BSpline) {
+	spl = MakeBSpline(degree, knots, P)
+	if len(W) != len(P) {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	for _, w := range W {
+		if !(w > 0) {
+			panic(errors.ErrDegenerate)
+		}
+	}
+	spl.W = W
+	return
+}
+
+// ClampedKnots returns a uniform knot vector whose curve interpolates the first and last control point
+//
+// Parameters:
+//
+//	n int - The number of control points
+//	degree int - The degree p
+//
+// Returns:
+//
+//	knots []float64 - p + 1 zeros, n - p - 1 equally spaced interior knots and p + 1 ones
+func ClampedKnots(n, degree int) (knots []float64) {
+	if degree < 1 || n <= degree {
+		panic(errors.ErrOrder)
+	}
+	knots = make([]float64, n+degree+1)
+	spans := n - degree
+	for i := degree + 1; i < n; i++ {
+		knots[i] = float64(i-degree) / float64(spans)
+	}
+	for i := n; i < len(knots); i++ {
+		knots[i] = 1
+	}
+	return
+}
+
+// Domain returns the parameter interval of the curve
+//
+// Parameters:
+//
+//	o *BSpline - The curve
+//
+// Returns:
+//
+//	t0 float64 - knots[p]
+//	t1 float64 - knots[len(P)]
+func (o *BSpline) Domain() (t0, t1 float64) {
+	t0, t1 = o.Knots[o.Degree], o.Knots[len(o.P)]
+	return
+}
+
+// span returns the index k with knots[k] <= t < knots[k+1] inside the domain, the last
+// non-empty span for the end of the domain
+func span(knots []float64, p, n int, t float64) (k int) {
+	if t >= knots[n] {
+		k = n - 1
+		for knots[k] == knots[k+1] {
+			k--
+		}
+		return
+	}
+	lo, hi := p, n
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if t < knots[mid] {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	k = lo
+	return
+}
+
+// deBoor evaluates the polynomial B-spline with the given control vectors at t using de Boor's algorithm
+func deBoor(knots []float64, p int, ctrl []rn.Vec, t float64) (P1 rn.Vec) {
+	k := span(knots, p, len(ctrl), t)
+	d := make([]rn.Vec, p+1)
+	copy(d, ctrl[k-p:k+1])
+	for r := 1; r <= p; r++ {
+		for j := p; j >= r; j-- {
+			i := j + k - p
+			alpha := (t - knots[i]) / (knots[i+p+1-r] - knots[i])
+			a, b := d[j-1].Scale(1-alpha), d[j].Scale(alpha)
+			d[j] = a.Add(b)
+		}
+	}
+	P1 = d[p]
+	return
+}
+
+// derivCtrl returns the knots and control vectors of the derivative of a B-spline of degree p
+func derivCtrl(knots []float64, p int, ctrl []rn.Vec) (dKnots []float64, dCtrl []rn.Vec) {
+	dKnots = knots[1 : len(knots)-1]
+	dCtrl = make([]rn.Vec, len(ctrl)-1)
+	for i := range dCtrl {
+		dCtrl[i] = rn.MakeVec(ctrl[i].N, 0)
+		if du := knots[i+p+1] - knots[i+1]; du > 0 {
+			d := ctrl[i+1].Sub(ctrl[i])
+			dCtrl[i] = d.Scale(float64(p) / du)
+		}
+	}
+	return
+}
+
+// homogeneous returns the control points as (W[i] * P[i], W[i])
+func (o *BSpline) homogeneous() (ctrl []rn.Vec) {
+	ctrl = make([]rn.Vec, len(o.P))
+	for i, P := range o.P {
+		H := P.Scale(o.W[i])
+		ctrl[i] = rn.Vec{N: P.N + 1, X: append(H.X, o.W[i])}
+	}
+	return
+}
+
+// clampParam returns t clamped to the domain of o
+func (o *BSpline) clampParam(t float64) float64 {
+	t0, t1 := o.Domain()
+	return math.Max(t0, math.Min(t1, t))
+}
+
+// At returns the point of the curve at parameter t using de Boor's algorithm
+//
+// Parameters:
+//
+//	t float64 - The parameter, clamped to the domain
+//
+// Returns:
+//
+//	P1 rn.Vec - The point
+func (o *BSpline) At(t float64) (P1 rn.Vec) {
+	t = o.clampParam(t)
+	if o.W == nil {
+		P1 = deBoor(o.Knots, o.Degree, o.P, t)
+		return
+	}
+	H := deBoor(o.Knots, o.Degree, o.homogeneous(), t)
+	n := H.N - 1
+	P1 = rn.Vec{N: n, X: H.X[:n]}
+	P1 = P1.Scale(1 / H.X[n])
+	return
+}
+
+// Deriv returns the first derivative of the curve at parameter t
+//
+// Parameters:
+//
+//	t float64 - The parameter, clamped to the domain
+//
+// Returns:
+//
+//	D1 rn.Vec - The tangent vector dC/dt, one-sided at knots where the curve is not
+//	differentiable
+func (o *BSpline) Deriv(t float64) (D1 rn.Vec) {
+	t = o.clampParam(t)
+	if o.W == nil {
+		knots, ctrl := derivCtrl(o.Knots, o.Degree, o.P)
+		D1 = deBoor(knots, o.Degree-1, ctrl, t)
+		return
+	}
+	// C = A / w, so C' = (A' - w' * C) / w
+	ctrl := o.homogeneous()
+	H := deBoor(o.Knots, o.Degree, ctrl, t)
+	knots, dCtrl := derivCtrl(o.Knots, o.Degree, ctrl)
+	dH := deBoor(knots, o.Degree-1, dCtrl, t)
+	n := H.N - 1
+	w, dw := H.X[n], dH.X[n]
+	D1 = rn.MakeVec(n, 0)
+	for i := range D1.X {
+		D1.X[i] = (dH.X[i] - dw*H.X[i]/w) / w
+	}
+	return
+}
+
+// InsertKnot returns the same curve with the knot t inserted once (Boehm's algorithm)
+//
+// Parameters:
+//
+//	t float64 - The new knot, strictly inside the domain
+//
+// Returns:
+//
+//	spl BSpline - The curve with one more knot and one more control point
+func (o *BSpline) InsertKnot(t float64) (spl BSpline) {
+	t0, t1 := o.Domain()
+	if t <= t0 || t >= t1 {
+		panic(errors.ErrIndexOutOfRange)
+	}
+	ctrl := o.P
+	if o.W != nil {
+		ctrl = o.homogeneous()
+	}
+	p, n := o.Degree, len(ctrl)
+	k := span(o.Knots, p, n, t)
+	knots := make([]float64, 0, len(o.Knots)+1)
+	knots = append(knots, o.Knots[:k+1]...)
+	knots = append(knots, t)
+	knots = append(knots, o.Knots[k+1:]...)
+	Q := make([]rn.Vec, n+1)
+	for i := 0; i <= n; i++ {
+		switch {
+		case i <= k-p:
+			Q[i] = ctrl[i]
+		case i > k:
+			Q[i] = ctrl[i-1]
+		default:
+			alpha := (t - o.Knots[i]) / (o.Knots[i+p] - o.Knots[i])
+			a, b := ctrl[i-1].Scale(1-alpha), ctrl[i].Scale(alpha)
+			Q[i] = a.Add(b)
+		}
+	}
+	spl = BSpline{Degree: p, Knots: knots, P: Q}
+	if o.W != nil {
+		spl.P, spl.W = make([]rn.Vec, n+1), make([]float64, n+1)
+		for i, H := range Q {
+			m := H.N - 1
+			spl.W[i] = H.X[m]
+			P := rn.Vec{N: m, X: H.X[:m]}
+			spl.P[i] = P.Scale(1 / H.X[m])
+		}
+	}
+	return
+}
@@ -0,0 +1,69 @@
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+// unitCircle returns the full unit circle as a quadratic NURBS with four quarter arcs
+func unitCircle() BSpline {
+	w := math.Sqrt(2) / 2
+	P := []rn.Vec{v2(1, 0), v2(1, 1), v2(0, 1), v2(-1, 1), v2(-1, 0), v2(-1, -1), v2(0, -1), v2(1, -1), v2(1, 0)}
+	W := []float64{1, w, 1, w, 1, w, 1, w, 1}
+	knots := []float64{0, 0, 0, 0.25, 0.25, 0.5, 0.5, 0.75, 0.75, 1, 1, 1}
+	return MakeNURBS(2, knots, P, W)
+}
+
+func TestBSpline(t *testing.T) {
+	if got, want := ClampedKnots(6, 3), []float64{0, 0, 0, 0, 1.0 / 3, 2.0 / 3, 1, 1, 1, 1}; !vecClose(rn.Vec{N: 10, X: got}, rn.Vec{N: 10, X: want}, 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+
+	// a single cubic span with clamped knots is a Bézier curve
+	P := []rn.Vec{v2(0, 0), v2(1, 3), v2(3, -1), v2(4, 2)}
+	bez := MakeBezier(P...)
+	spl := MakeBSpline(3, ClampedKnots(4, 3), P)
+	for _, s := range []float64{0, 0.25, 0.6, 1} {
+		if got, want := spl.At(s), bez.At(s); !vecClose(got, want, 1e-14) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+		if got, want := spl.Deriv(s), bez.Deriv(s); !vecClose(got, want, 1e-13) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+	}
+
+	circle := unitCircle()
+	long := MakeBSpline(3, []float64{0, 0, 0, 0, 1, 2, 4, 4, 4, 4}, []rn.Vec{v2(0, 0), v2(1, 2), v2(2, -1), v2(4, 0), v2(5, 3), v2(7, 1)})
+	for _, spl := range []BSpline{circle, long} {
+		t0, t1 := spl.Domain()
+		refined := spl.InsertKnot(t0 + (t1-t0)*0.4)
+		refined = refined.InsertKnot(t0 + (t1-t0)*0.4)
+		for k := 0; k <= 20; k++ {
+			s := t0 + (t1-t0)*float64(k)/20
+			if got, want := refined.At(s), spl.At(s); !vecClose(got, want, 1e-13) {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+			}
+			// central differences away from the knots, one-sided at the ends
+			h := 1e-6
+			a, b := spl.At(math.Min(s+h, t1)), spl.At(math.Max(s-h, t0))
+			diff := a.Sub(b)
+			want := diff.Scale(1 / (math.Min(s+h, t1) - math.Max(s-h, t0)))
+			if got := spl.Deriv(s); !vecClose(got, want, 1e-4*math.Max(1, want.Norm())) {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+			}
+		}
+	}
+
+	// the NURBS lies on the circle and passes the control points of weight 1
+	for k := 0; k <= 40; k++ {
+		P := circle.At(float64(k) / 40)
+		if r := P.Norm(); math.Abs(r-1) > 1e-15 {
+			t.Errorf("error:\ngot=%v\nwant=%v", r, 1)
+		}
+	}
+	if got := circle.At(0.5); !vecClose(got, v2(-1, 0), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v2(-1, 0))
+	}
+}
@@ -0,0 +1,283 @@
+package curve
+
+import (
+	"math"
+	"sort"
+
+	"github.com/add1609/lin/rn"
+)
+
+// Curve is a parametric curve with a continuous first derivative on each piece of its domain
+type Curve interface {
+	At(t float64) rn.Vec
+	Deriv(t float64) rn.Vec
+	Domain() (t0, t1 float64)
+}
+
+// tol is the relative accuracy of the numerical integration and the closest point search
+const tol = 1e-12
+
+// gaussNodes and gaussWeights are the 5-point Gauss-Legendre rule on [-1, 1]
+var (
+	gaussNodes   = [...]float64{-0.9061798459386640, -0.5384693101056831, 0, 0.5384693101056831, 0.9061798459386640}
+	gaussWeights = [...]float64{0.2369268850561891, 0.4786286704993665, 0.5688888888888889, 0.4786286704993665, 0.2369268850561891}
+)
+
+// breaks returns the parameters that split the domain of c into polynomial or rational pieces
+func breaks(c Curve) (ts []float64) {
+	t0, t1 := c.Domain()
+	switch c := c.(type) {
+	case *BSpline:
+		ts = []float64{t0}
+		for _, u := range c.Knots {
+			if u > ts[len(ts)-1] && u <= t1 {
+				ts = append(ts, u)
+			}
+		}
+	case *Hermite:
+		ts = c.T
+	default:
+		ts = []float64{t0, t1}
+	}
+	return
+}
+
+// gauss returns the 5-point Gauss-Legendre estimate of the integral of f over [a, b]
+func gauss(f func(t float64) float64, a, b float64) (sum float64) {
+	h, m := (b-a)/2, (a+b)/2
+	for i, x := range gaussNodes {
+		sum += gaussWeights[i] * f(m+h*x)
+	}
+	sum *= h
+	return
+}
+
+// integrate returns the integral of f over [a, b] by adaptive bisection of the Gauss-Legendre rule
+func integrate(f func(t float64) float64, a, b, whole float64, depth int) float64 {
+	m := (a + b) / 2
+	left, right := gauss(f, a, m), gauss(f, m, b)
+	if depth == 0 || math.Abs(left+right-whole) <= tol*math.Max(math.Abs(left+right), tol) {
+		return left + right
+	}
+	return integrate(f, a, m, left, depth-1) + integrate(f, m, b, right, depth-1)
+}
+
+// speed returns |dC/dt| as a function of t
+func speed(c Curve) func(t float64) float64 {
+	return func(t float64) float64 {
+		d := c.Deriv(t)
+		return d.Norm()
+	}
+}
+
+// Length returns the arc length of a curve over its domain
+//
+// Parameters:
+//
+//	c Curve - The curve
+//
+// Returns:
+//
+//	l float64 - The integral of |dC/dt| by adaptive Gauss-Legendre quadrature on every piece
+func Length(c Curve) (l float64) {
+	ts := breaks(c)
+	f := speed(c)
+	for i := 1; i < len(ts); i++ {
+		l += integrate(f, ts[i-1], ts[i], gauss(f, ts[i-1], ts[i]), 20)
+	}
+	return
+}
+
+// ArcLength is a table that maps arc length to the parameter of a curve
+type ArcLength struct {
+	Curve Curve
+	T     []float64 // the breaks of the curve
+	S     []float64 // the arc length from the start of the domain to T[i]
+}
+
+// MakeArcLength returns the arc length table of a curve
+//
+// Parameters:
+//
+//	c Curve - The curve
+//
+// Returns:
+//
+//	al ArcLength - The table with the cumulative length at every break of the curve
+func MakeArcLength(c Curve) (al ArcLength) {
+	al.Curve = c
+	al.T = breaks(c)
+	al.S = make([]float64, len(al.T))
+	f := speed(c)
+	for i := 1; i < len(al.T); i++ {
+		a, b := al.T[i-1], al.T[i]
+		al.S[i] = al.S[i-1] + integrate(f, a, b, gauss(f, a, b), 20)
+	}
+	return
+}
+
+// Length returns the total arc length of the curve
+//
+// Parameters:
+//
+//	o *ArcLength - The table
+//
+// Returns:
+//
+//	l float64 - The length of the curve over its domain
+func (o *ArcLength) Length() (l float64) {
+	l = o.S[len(o.S)-1]
+	return
+}
+
+// Param returns the parameter at which the curve has traveled a given arc length
+//
+// Parameters:
+//
+//	s float64 - The arc length, clamped to [0, Length]
+//
+// Returns:
+//
+//	t float64 - The parameter with length s from the start of the domain, found by Newton's
+//	method safeguarded by bisection
+func (o *ArcLength) Param(s float64) (t float64) {
+	n := len(o.T) - 1
+	switch {
+	case s <= 0:
+		return o.T[0]
+	case s >= o.S[n]:
+		return o.T[n]
+	}
+	i := sort.SearchFloat64s(o.S, s)
+	if i == 0 {
+		i = 1
+	}
+	lo, hi := o.T[i-1], o.T[i]
+	base := o.S[i-1]
+	f := speed(o.Curve)
+	// start from linear interpolation within the piece
+	t = lo + (hi-lo)*(s-base)/(o.S[i]-base)
+	for k := 0; k < 100; k++ {
+		g := base + integrate(f, lo, t, gauss(f, lo, t), 20) - s
+		if math.Abs(g) <= tol*math.Max(o.S[n], 1) {
+			return
+		}
+		if g > 0 {
+			hi = t
+		} else {
+			lo, base = t, g+s
+		}
+		next := t - g/f(t)
+		if !(next > lo && next < hi) {
+			next = (lo + hi) / 2
+		}
+		t = next
+	}
+	return
+}
+
+// At returns the point of the curve at a given arc length
+//
+// Parameters:
+//
+//	s float64 - The arc length from the start of the curve
+//
+// Returns:
+//
+//	P1 rn.Vec - The point at parameter Param(s)
+func (o *ArcLength) At(s float64) (P1 rn.Vec) {
+	P1 = o.Curve.At(o.Param(s))
+	return
+}
+
+// ClosestPoint returns the point of a curve closest to P
+//
+// The domain is sampled densely on every piece and the best sample is
+// refined by golden section search between its neighbors and the secant
+// method, so the global minimum is found unless the curve has features
+// finer than the sampling.
+//
+// Parameters:
+//
+//	c Curve - The curve
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	t float64 - The parameter of the closest point
+//	P1 rn.Vec - The closest point
+func ClosestPoint(c Curve, P rn.Vec) (t float64, P1 rn.Vec) {
+	const perPiece = 32
+	ts := breaks(c)
+	dist := func(t float64) float64 {
+		Q := c.At(t)
+		return Q.Dist(P)
+	}
+	var samples []float64
+	for i := 1; i < len(ts); i++ {
+		for k := 0; k < perPiece; k++ {
+			samples = append(samples, ts[i-1]+(ts[i]-ts[i-1])*float64(k)/perPiece)
+		}
+	}
+	samples = append(samples, ts[len(ts)-1])
+	best, bestDist := 0, math.Inf(1)
+	for i, s := range samples {
+		if d := dist(s); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	lo, hi := samples[best], samples[best]
+	if best > 0 {
+		lo = samples[best-1]
+	}
+	if best < len(samples)-1 {
+		hi = samples[best+1]
+	}
+	t = goldenSection(dist, lo, hi)
+	// the distance is flat at its minimum, so polish with the secant method on
+	// the root of (C(t) - P) · C'(t)
+	g := func(t float64) float64 {
+		Q, D := c.At(t), c.Deriv(t)
+		w := Q.Sub(P)
+		return w.Dot(D)
+	}
+	a, b := t, t+(hi-lo)*1e-3
+	if b > hi {
+		b = t - (hi-lo)*1e-3
+	}
+	ga, gb := g(a), g(b)
+	for k := 0; k < 20 && gb != ga && math.Abs(b-a) > tol*(hi-lo); k++ {
+		next := b - gb*(b-a)/(gb-ga)
+		if !(next >= lo && next <= hi) {
+			break
+		}
+		a, ga, b, gb = b, gb, next, g(next)
+	}
+	if math.Abs(gb) < math.Abs(g(t)) && dist(b) <= dist(t)+tol*math.Max(1, dist(t)) {
+		t = b
+	}
+	if d := dist(samples[best]); d < dist(t) {
+		t = samples[best]
+	}
+	P1 = c.At(t)
+	return
+}
+
+// goldenSection returns the minimizer of a unimodal f on [a, b]
+func goldenSection(f func(t float64) float64, a, b float64) float64 {
+	r := (math.Sqrt(5) - 1) / 2
+	x1, x2 := b-r*(b-a), a+r*(b-a)
+	f1, f2 := f(x1), f(x2)
+	for b-a > tol*math.Max(1, math.Abs(a)+math.Abs(b)) {
+		if f1 < f2 {
+			b, x2, f2 = x2, x1, f1
+			x1 = b - r*(b-a)
+			f1 = f(x1)
+		} else {
+			a, x1, f1 = x1, x2, f2
+			x2 = a + r*(b-a)
+			f2 = f(x2)
+		}
+	}
+	return (a + b) / 2
+}
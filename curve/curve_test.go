@@ -0,0 +1,73 @@
+package curve
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	circle := unitCircle()
+	// a straight line whose speed varies along the curve
+	line := MakeBezier(v2(0, 0), v2(0, 0), v2(3, 0))
+	tests := []struct {
+		c    Curve
+		want float64
+	}{
+		{&circle, 2 * math.Pi},
+		{&line, 3},
+	}
+	for _, test := range tests {
+		if got := Length(test.c); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.want)
+		}
+		al := MakeArcLength(test.c)
+		if got := al.Length(); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.want)
+		}
+	}
+
+	al := MakeArcLength(&circle)
+	for _, s := range []float64{0, 0.3, 1.5, math.Pi, 5, 2 * math.Pi} {
+		if got, want := al.At(s), v2(math.Cos(s), math.Sin(s)); !vecClose(got, want, 1e-10) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+	}
+	al = MakeArcLength(&line)
+	for _, s := range []float64{0, 0.1, 1, 2.5, 3} {
+		if got := al.At(s); !vecClose(got, v2(s, 0), 1e-10) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, v2(s, 0))
+		}
+	}
+}
+
+func TestClosestPoint(t *testing.T) {
+	circle := unitCircle()
+	s := math.Sqrt(0.5)
+	if _, P1 := ClosestPoint(&circle, v2(2, 2)); !vecClose(P1, v2(s, s), 1e-10) {
+		t.Errorf("error:\ngot=%v\nwant=%v", P1, v2(s, s))
+	}
+	// the closest point of an open curve may be an end point
+	cubic := MakeBezier(v2(0, 0), v2(1, 3), v2(3, -1), v2(4, 2))
+	if tc, P1 := ClosestPoint(&cubic, v2(5, 4)); tc != 1 || !vecClose(P1, v2(4, 2), 0) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", tc, P1, v2(4, 2))
+	}
+	for _, P := range []float64{-1, 0.5, 1.7, 3} {
+		Q := v2(P, 1)
+		tc, P1 := ClosestPoint(&cubic, Q)
+		// compare with dense sampling
+		best := math.Inf(1)
+		for k := 0; k <= 100000; k++ {
+			R := cubic.At(float64(k) / 100000)
+			best = math.Min(best, R.Dist(Q))
+		}
+		if d := P1.Dist(Q); d > best+1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", d, best)
+		}
+		if tc > 0 && tc < 1 {
+			w, D := P1.Sub(Q), cubic.Deriv(tc)
+			if g := w.Dot(D); math.Abs(g) > 1e-9 {
+				t.Errorf("error:\ngot=%v\nwant=%v", g, 0)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Hermite is the piecewise cubic curve that passes P[i] with derivative D[i] at parameter T[i]
+type Hermite struct {
+	T []float64
+	P []rn.Vec
+	D []rn.Vec
+}
+
+func (o Hermite) String() (str string) {
+	str += fmt.Sprintf("Hermite params %v, points %v, derivatives %v", o.T, o.P, o.D)
+	return
+}
+
+// MakeHermite returns a piecewise cubic Hermite curve
+//
+// Parameters:
+//
+//	T []float64 - The strictly increasing parameters of the points, at least two
+//	P []rn.Vec - The points, all of the same dimension
+//	D []rn.Vec - The derivatives dC/dt at the points
+//
+// Returns:
+//
+//	her Hermite - The C¹ curve over the domain [T[0], T[len(T)-1]]
+func MakeHermite(T []float64, P, D []rn.Vec) (her Hermite) {
+	checkParams(T, P)
+	if len(D) != len(P) {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	for _, d := range D {
+		if d.N != P[0].N {
+			panic(errors.ErrShape)
+		}
+	}
+	her = Hermite{T: T, P: P, D: D}
+	return
+}
+
+// checkParams panics unless T is strictly increasing and has one entry for each of at least two points
+func checkParams(T []float64, P []rn.Vec) {
+	checkPoints(P)
+	if len(T) != len(P) {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	if len(T) < 2 {
+		panic(errors.ErrOrder)
+	}
+	for i := 1; i < len(T); i++ {
+		if !(T[i] > T[i-1]) {
+			panic(errors.ErrOrder)
+		}
+	}
+}
+
+// Domain returns the parameter interval of the curve
+//
+// Parameters:
+//
+//	o *Hermite - The curve
+//
+// Returns:
+//
+//	t0 float64 - T[0]
+//	t1 float64 - T[len(T)-1]
+func (o *Hermite) Domain() (t0, t1 float64) {
+	t0, t1 = o.T[0], o.T[len(o.T)-1]
+	return
+}
+
+// piece returns the index i of the piece [T[i], T[i+1]] that contains t, clamped to the domain,
+// and the local parameter s in [0, 1]
+func (o *Hermite) piece(t float64) (i int, s float64) {
+	n := len(o.T) - 1
+	t = math.Max(o.T[0], math.Min(o.T[n], t))
+	i = sort.SearchFloat64s(o.T, t) - 1
+	if i < 0 {
+		i = 0
+	}
+	s = (t - o.T[i]) / (o.T[i+1] - o.T[i])
+	return
+}
+
+// At returns the point of the curve at parameter t
+//
+// Parameters:
+//
+//	t float64 - The parameter, clamped to the domain
+//
+// Returns:
+//
+//	P1 rn.Vec - The point
+func (o *Hermite) At(t float64) (P1 rn.Vec) {
+	i, s := o.piece(t)
+	h := o.T[i+1] - o.T[i]
+	s2, s3 := s*s, s*s*s
+	h00, h10, h01, h11 := 2*s3-3*s2+1, s3-2*s2+s, 3*s2-2*s3, s3-s2
+	P1 = rn.MakeVec(o.P[i].N, 0)
+	for k := range P1.X {
+		P1.X[k] = h00*o.P[i].X[k] + h10*h*o.D[i].X[k] + h01*o.P[i+1].X[k] + h11*h*o.D[i+1].X[k]
+	}
+	return
+}
+
+// Deriv returns the first derivative of the curve at parameter t
+//
+// Parameters:
+//
+//	t float64 - The parameter, clamped to the domain
+//
+// Returns:
+//
+//	D1 rn.Vec - The tangent vector dC/dt
+func (o *Hermite) Deriv(t float64) (D1 rn.Vec) {
+	i, s := o.piece(t)
+	h := o.T[i+1] - o.T[i]
+	s2 := s * s
+	d00, d10, d11 := 6*s2-6*s, 3*s2-4*s+1, 3*s2-2*s
+	D1 = rn.MakeVec(o.P[i].N, 0)
+	for k := range D1.X {
+		D1.X[k] = d00*(o.P[i].X[k]-o.P[i+1].X[k])/h + d10*o.D[i].X[k] + d11*o.D[i+1].X[k]
+	}
+	return
+}
+
+// Piece returns one cubic piece of the curve in Bézier form
+//
+// Parameters:
+//
+//	i int - The index of the piece [T[i], T[i+1]]
+//
+// Returns:
+//
+//	bez Bezier - The cubic that traces the piece with parameter (t - T[i]) / (T[i+1] - T[i])
+func (o *Hermite) Piece(i int) (bez Bezier) {
+	if i < 0 || i >= len(o.T)-1 {
+		panic(errors.ErrIndexOutOfRange)
+	}
+	h := o.T[i+1] - o.T[i]
+	a, b := o.D[i].Scale(h/3), o.D[i+1].Scale(h/3)
+	bez.P = []rn.Vec{o.P[i], o.P[i].Add(a), o.P[i+1].Sub(b), o.P[i+1]}
+	return
+}
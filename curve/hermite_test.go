@@ -0,0 +1,33 @@
+package curve
+
+import (
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func TestHermite(t *testing.T) {
+	her := MakeHermite([]float64{0, 1, 3}, []rn.Vec{v2(0, 0), v2(1, 1), v2(3, 0)}, []rn.Vec{v2(1, 2), v2(1, 0), v2(1, -1)})
+	for i, P := range her.P {
+		if got := her.At(her.T[i]); !vecClose(got, P, 1e-15) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, P)
+		}
+		if got := her.Deriv(her.T[i]); !vecClose(got, her.D[i], 1e-15) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, her.D[i])
+		}
+	}
+	for i := 0; i < 2; i++ {
+		bez := her.Piece(i)
+		h := her.T[i+1] - her.T[i]
+		for _, s := range []float64{0, 0.3, 0.8, 1} {
+			tc := her.T[i] + h*s
+			if got, want := bez.At(s), her.At(tc); !vecClose(got, want, 1e-14) {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+			}
+			D := bez.Deriv(s)
+			if got, want := D.Scale(1/h), her.Deriv(tc); !vecClose(got, want, 1e-14) {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+			}
+		}
+	}
+}
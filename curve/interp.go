@@ -0,0 +1,202 @@
+package curve
+
+import (
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// cubicSpline returns the C² cubic spline through P at T whose derivatives at the ends are
+// D0 and D1, or whose second derivatives vanish at the ends where D0 or D1 is nil
+func cubicSpline(T []float64, P []rn.Vec, D0, D1 *rn.Vec) (her Hermite) {
+	checkParams(T, P)
+	n := len(P)
+	a, b, c := make([]float64, n), make([]float64, n), make([]float64, n)
+	d := make([]rn.Vec, n)
+	// row i matches the second derivatives of the pieces that meet at T[i],
+	// scaled by the lengths of the pieces
+	for i := 0; i < n; i++ {
+		d[i] = rn.MakeVec(P[0].N, 0)
+		if i > 0 {
+			h := T[i] - T[i-1]
+			a[i], b[i] = 1/h, 2/h
+			e := P[i].Sub(P[i-1])
+			d[i] = e.Scale(3 / (h * h))
+		}
+		if i < n-1 {
+			h := T[i+1] - T[i]
+			b[i] += 2 / h
+			c[i] = 1 / h
+			e := P[i+1].Sub(P[i])
+			e = e.Scale(3 / (h * h))
+			d[i] = d[i].Add(e)
+		}
+	}
+	if D0 != nil {
+		b[0], c[0], d[0] = 1, 0, *D0
+	}
+	if D1 != nil {
+		a[n-1], b[n-1], d[n-1] = 0, 1, *D1
+	}
+	// the system is diagonally dominant, so the Thomas algorithm needs no pivoting
+	// and solves it for each coordinate in O(n)
+	A := rn.MakeTridiagonal(a[1:], b, c[:n-1])
+	D := make([]rn.Vec, n)
+	for i := range D {
+		D[i] = rn.MakeVec(P[0].N, 0)
+	}
+	rhs := rn.MakeVec(n, 0)
+	for k := 0; k < P[0].N; k++ {
+		for i := range d {
+			rhs.X[i] = d[i].X[k]
+		}
+		x, _ := A.Thomas(rhs)
+		for i := range D {
+			D[i].X[k] = x.X[i]
+		}
+	}
+	her = Hermite{T: T, P: P, D: D}
+	return
+}
+
+// NaturalSpline returns the natural cubic spline through a sequence of points
+//
+// Parameters:
+//
+//	T []float64 - The strictly increasing parameters of the points, at least two
+//	P []rn.Vec - The points, all of the same dimension
+//
+// Returns:
+//
+//	her Hermite - The C² piecewise cubic through P[i] at T[i] with zero second derivative at
+//	both ends
+func NaturalSpline(T []float64, P []rn.Vec) (her Hermite) {
+	her = cubicSpline(T, P, nil, nil)
+	return
+}
+
+// ClampedSpline returns the cubic spline through a sequence of points with given end derivatives
+//
+// Parameters:
+//
+//	T []float64 - The strictly increasing parameters of the points, at least two
+//	P []rn.Vec - The points, all of the same dimension
+//	D0 rn.Vec - The derivative at T[0]
+//	D1 rn.Vec - The derivative at T[len(T)-1]
+//
+// Returns:
+//
+//	her Hermite - The C² piecewise cubic through P[i] at T[i]
+func ClampedSpline(T []float64, P []rn.Vec, D0, D1 rn.Vec) (her Hermite) {
+	checkPoints(P)
+	if D0.N != P[0].N || D1.N != P[0].N {
+		panic(errors.ErrShape)
+	}
+	her = cubicSpline(T, P, &D0, &D1)
+	return
+}
+
+// ChordParams returns parameters for a sequence of points spaced by powers of their distances
+//
+// Parameters:
+//
+//	P []rn.Vec - The points, consecutive points must differ if alpha > 0
+//	alpha float64 - 0 for uniform, 0.5 for centripetal and 1 for chord length parameters
+//
+// Returns:
+//
+//	T []float64 - T[0] = 0 and T[i] = T[i-1] + |P[i] - P[i-1]|^alpha
+func ChordParams(P []rn.Vec, alpha float64) (T []float64) {
+	checkPoints(P)
+	T = make([]float64, len(P))
+	for i := 1; i < len(P); i++ {
+		d := P[i].Dist(P[i-1])
+		if d == 0 && alpha > 0 {
+			panic(errors.ErrDegenerate)
+		}
+		T[i] = T[i-1] + math.Pow(d, alpha)
+	}
+	return
+}
+
+// CatmullRom returns the Catmull-Rom spline through a sequence of points
+//
+// The derivative at each interior point is the derivative of the parabola
+// through it and its two neighbors, so the curve is only C¹ but every
+// point influences only the two pieces on each side of it. Centripetal
+// parameters (alpha = 0.5) avoid cusps and self-intersections within a piece.
+//
+// Parameters:
+//
+//	P []rn.Vec - The points, at least two
+//	alpha float64 - The exponent of the parameter spacing as in ChordParams
+//
+// Returns:
+//
+//	her Hermite - The piecewise cubic through P[i] at ChordParams(P, alpha)[i] with
+//	one-sided difference derivatives at the ends
+func CatmullRom(P []rn.Vec, alpha float64) (her Hermite) {
+	T := ChordParams(P, alpha)
+	checkParams(T, P)
+	n := len(P)
+	slope := func(i int) rn.Vec {
+		d := P[i+1].Sub(P[i])
+		return d.Scale(1 / (T[i+1] - T[i]))
+	}
+	D := make([]rn.Vec, n)
+	D[0], D[n-1] = slope(0), slope(n-2)
+	for i := 1; i < n-1; i++ {
+		// (P[i+1] - P[i-1]) / (T[i+1] - T[i-1]) corrected for unequal spacing
+		left, right := slope(i-1), slope(i)
+		whole := P[i+1].Sub(P[i-1])
+		whole = whole.Scale(1 / (T[i+1] - T[i-1]))
+		D[i] = left.Add(right)
+		D[i] = D[i].Sub(whole)
+	}
+	her = Hermite{T: T, P: P, D: D}
+	return
+}
+
+// MonotoneSpline returns a piecewise cubic through a sequence of points that preserves the
+// monotonicity of every coordinate
+//
+// The derivatives are the Fritsch-Butland weighted harmonic means of the
+// neighboring slopes and zero at local extrema of the data, so every
+// coordinate of the curve is monotone wherever the data is and the curve does
+// not overshoot.
+//
+// Parameters:
+//
+//	T []float64 - The strictly increasing parameters of the points, at least two
+//	P []rn.Vec - The points, all of the same dimension
+//
+// Returns:
+//
+//	her Hermite - The C¹ piecewise cubic through P[i] at T[i] with one-sided difference
+//	derivatives at the ends
+func MonotoneSpline(T []float64, P []rn.Vec) (her Hermite) {
+	checkParams(T, P)
+	n := len(P)
+	D := make([]rn.Vec, n)
+	for i := range D {
+		D[i] = rn.MakeVec(P[0].N, 0)
+	}
+	for k := 0; k < P[0].N; k++ {
+		delta := func(i int) float64 {
+			return (P[i+1].X[k] - P[i].X[k]) / (T[i+1] - T[i])
+		}
+		D[0].X[k], D[n-1].X[k] = delta(0), delta(n-2)
+		for i := 1; i < n-1; i++ {
+			d0, d1 := delta(i-1), delta(i)
+			if d0*d1 <= 0 {
+				continue
+			}
+			h0, h1 := T[i]-T[i-1], T[i+1]-T[i]
+			w0, w1 := 2*h1+h0, h1+2*h0
+			D[i].X[k] = (w0 + w1) / (w0/d0 + w1/d1)
+		}
+	}
+	her = Hermite{T: T, P: P, D: D}
+	return
+}
@@ -0,0 +1,109 @@
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+// secondDeriv returns the one-sided second differences of c on both sides of t
+func secondDeriv(c Curve, t float64) (left, right rn.Vec) {
+	h := 1e-5
+	a, b, m := c.Deriv(t-h), c.Deriv(t+h), c.Deriv(t)
+	left, right = m.Sub(a), b.Sub(m)
+	left, right = left.Scale(1/h), right.Scale(1/h)
+	return
+}
+
+func TestCubicSpline(t *testing.T) {
+	T := []float64{0, 0.5, 2, 3, 4.5}
+	P := []rn.Vec{v2(0, 0), v2(1, 2), v2(2, -1), v2(4, 0), v2(5, 3)}
+	natural := NaturalSpline(T, P)
+	for i := range P {
+		if got := natural.At(T[i]); !vecClose(got, P[i], 1e-14) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, P[i])
+		}
+	}
+	for _, tc := range T[1 : len(T)-1] {
+		if left, right := secondDeriv(&natural, tc); !vecClose(left, right, 1e-3) {
+			t.Errorf("error:\ngot=%v\nwant=%v", left, right)
+		}
+	}
+	for _, tc := range []float64{T[0] + 1e-5, T[len(T)-1]} {
+		if left, _ := secondDeriv(&natural, tc); !vecClose(left, v2(0, 0), 1e-3) {
+			t.Errorf("error:\ngot=%v\nwant=%v", left, v2(0, 0))
+		}
+	}
+
+	// a clamped spline reproduces a cubic
+	cubic := func(x float64) rn.Vec { return v2(x, x*x*x-2*x) }
+	T = []float64{-1, 0, 0.5, 2, 3}
+	P = make([]rn.Vec, len(T))
+	for i, x := range T {
+		P[i] = cubic(x)
+	}
+	clamped := ClampedSpline(T, P, v2(1, 1), v2(1, 25))
+	for _, x := range []float64{-1, -0.4, 0.25, 1, 2.7, 3} {
+		if got := clamped.At(x); !vecClose(got, cubic(x), 1e-13) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, cubic(x))
+		}
+	}
+
+	// two points give the straight line
+	line := NaturalSpline([]float64{0, 2}, []rn.Vec{v2(1, 1), v2(3, 5)})
+	if got := line.At(0.5); !vecClose(got, v2(1.5, 2), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v2(1.5, 2))
+	}
+}
+
+func TestCatmullRom(t *testing.T) {
+	P := []rn.Vec{v2(0, 0), v2(1, 1), v2(3, 1), v2(3, 4)}
+	uniform := CatmullRom(P, 0)
+	if got, want := uniform.T, []float64{0, 1, 2, 3}; !vecClose(rn.Vec{N: 4, X: got}, rn.Vec{N: 4, X: want}, 0) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	if got := uniform.Deriv(1); !vecClose(got, v2(1.5, 0.5), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v2(1.5, 0.5))
+	}
+	for _, alpha := range []float64{0, 0.5, 1} {
+		cr := CatmullRom(P, alpha)
+		for i := range P {
+			if got := cr.At(cr.T[i]); !vecClose(got, P[i], 1e-14) {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, P[i])
+			}
+		}
+	}
+	// points on a line with unequal spacing are traced at constant speed by chord length parameters
+	chord := CatmullRom([]rn.Vec{v2(0, 0), v2(1, 0), v2(4, 0), v2(5, 0)}, 1)
+	for _, s := range []float64{0.5, 2, 3.3, 4.9} {
+		if got := chord.At(s); !vecClose(got, v2(s, 0), 1e-14) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, v2(s, 0))
+		}
+	}
+}
+
+func TestMonotoneSpline(t *testing.T) {
+	T := []float64{0, 1, 2, 2.5, 4, 5}
+	P := []rn.Vec{v2(0, 0), v2(1, 0), v2(2, 1), v2(3, 1.1), v2(4, 3), v2(5, 3)}
+	mono := MonotoneSpline(T, P)
+	for i := range P {
+		if got := mono.At(T[i]); !vecClose(got, P[i], 1e-15) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, P[i])
+		}
+	}
+	prev := mono.At(0)
+	for k := 1; k <= 1000; k++ {
+		Q := mono.At(5 * float64(k) / 1000)
+		for i := range Q.X {
+			if Q.X[i] < prev.X[i]-1e-14 {
+				t.Errorf("error:\ngot=%v\nwant=%v", Q, prev)
+			}
+		}
+		prev = Q
+	}
+	// flat data stays flat
+	if got := mono.At(0.5); math.Abs(got.X[1]) > 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got.X[1], 0)
+	}
+}
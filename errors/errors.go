@@ -11,6 +11,7 @@ var (
 	ErrShape               = Error{"lin: dimension mismatch"}
 	ErrSquare              = Error{"lin: expect square matrix"}
 	ErrSingular            = Error{"lin: matrix is singular"}
+	ErrNotPosDef           = Error{"lin: matrix is not positive definite"}
 	ErrColLength           = Error{"lin: col length mismatch"}
 	ErrNormOrder           = Error{"lin: invalid norm order for matrix"}
 	ErrColAccess           = Error{"lin: column index out of range"}
@@ -22,4 +23,13 @@ var (
 	ErrIndexOutOfRange     = Error{"lin: index out of range"}
 	ErrNegativeDimension   = Error{"lin: negative dimension"}
 	ErrSliceLengthMismatch = Error{"lin: input slice length mismatch"}
+	ErrRotation            = Error{"lin: matrix is not a rotation"}
+	ErrParallel            = Error{"lin: parallel, no unique intersection"}
+	ErrNoIntersection      = Error{"lin: no intersection"}
+	ErrDegenerate          = Error{"lin: degenerate geometry"}
+	ErrConstraint          = Error{"lin: constrained edges cross"}
+	ErrFormat              = Error{"lin: malformed file"}
+	ErrNonManifold         = Error{"lin: mesh is not manifold"}
+	ErrTopology            = Error{"lin: operation would break the mesh topology"}
+	ErrInfinity            = Error{"lin: point at infinity"}
 )
@@ -0,0 +1,200 @@
+package ga
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// maxDim is the largest number of basis vectors of an Algebra, a multivector
+// of maxDim basis vectors has 65536 coefficients
+const maxDim = 16
+
+// Algebra is the geometric algebra G(P, Q, R) of a space with P basis vectors
+// that square to 1, Q that square to -1 and R that square to 0
+//
+// The null basis vectors come first, then the positive and then the negative
+// ones, so in G(3, 0, 1) e0 is the null vector and e1, e2, e3 are Euclidean,
+// and in G(4, 1, 0) e0, e1, e2 are Euclidean, e3 = e+ and e4 = e-.
+type Algebra struct {
+	P, Q, R int
+}
+
+var (
+	// G3 is the algebra G(3, 0, 0) of Euclidean 3-space
+	G3 = MakeAlgebra(3, 0, 0)
+	// PGA3 is the projective algebra G(3, 0, 1) of Euclidean 3-space
+	PGA3 = MakeAlgebra(3, 0, 1)
+	// CGA3 is the conformal algebra G(4, 1, 0) of Euclidean 3-space
+	CGA3 = MakeAlgebra(4, 1, 0)
+)
+
+func (o Algebra) String() (str string) {
+	str += fmt.Sprintf("G(%d, %d, %d)", o.P, o.Q, o.R)
+	return
+}
+
+// MakeAlgebra returns the geometric algebra with the given metric signature
+//
+// Parameters:
+//
+//	p int - The number of basis vectors that square to 1
+//	q int - The number of basis vectors that square to -1
+//	r int - The number of basis vectors that square to 0
+//
+// Returns:
+//
+//	alg Algebra - The algebra G(p, q, r)
+func MakeAlgebra(p, q, r int) (alg Algebra) {
+	if p < 0 || q < 0 || r < 0 {
+		panic(errors.ErrNegativeDimension)
+	}
+	if n := p + q + r; n < 1 || n > maxDim {
+		panic(errors.ErrOrder)
+	}
+	alg = Algebra{P: p, Q: q, R: r}
+	return
+}
+
+// Dim returns the number of basis vectors of the algebra
+//
+// Parameters:
+//
+//	o *Algebra - The algebra
+//
+// Returns:
+//
+//	n int - P + Q + R
+func (o *Algebra) Dim() (n int) {
+	n = o.P + o.Q + o.R
+	return
+}
+
+// Metric returns the square of a basis vector
+//
+// Parameters:
+//
+//	i int - The index of the basis vector
+//
+// Returns:
+//
+//	m float64 - 0, 1 or -1
+func (o *Algebra) Metric(i int) (m float64) {
+	switch {
+	case i < 0 || i >= o.Dim():
+		panic(errors.ErrIndexOutOfRange)
+	case i < o.R:
+		m = 0
+	case i < o.R+o.P:
+		m = 1
+	default:
+		m = -1
+	}
+	return
+}
+
+// Scalar returns a scalar as a multivector
+//
+// Parameters:
+//
+//	s float64 - The scalar
+//
+// Returns:
+//
+//	mv Multivector - The multivector with grade 0 part s
+func (o *Algebra) Scalar(s float64) (mv Multivector) {
+	mv = MakeMultivector(*o)
+	mv.X[0] = s
+	return
+}
+
+// Blade returns a multiple of the product of distinct basis vectors
+//
+// Parameters:
+//
+//	s float64 - The coefficient
+//	idx ...int - The indices of the basis vectors in any order
+//
+// Returns:
+//
+//	mv Multivector - The blade s * e_idx[0] * ... * e_idx[k-1], negated for an odd
+//	permutation of the indices
+func (o *Algebra) Blade(s float64, idx ...int) (mv Multivector) {
+	mv = MakeMultivector(*o)
+	var mask uint
+	for _, i := range idx {
+		if i < 0 || i >= o.Dim() {
+			panic(errors.ErrIndexOutOfRange)
+		}
+		if mask&(1<<uint(i)) != 0 {
+			panic(errors.ErrShape)
+		}
+		s *= reorderSign(mask, 1<<uint(i))
+		mask |= 1 << uint(i)
+	}
+	mv.X[mask] = s
+	return
+}
+
+// Vector returns a grade 1 multivector
+//
+// Parameters:
+//
+//	v rn.Vec - The coefficients of all Dim basis vectors
+//
+// Returns:
+//
+//	mv Multivector - The vector v[0] * e0 + ... + v[n-1] * e(n-1)
+func (o *Algebra) Vector(v rn.Vec) (mv Multivector) {
+	if v.N != o.Dim() {
+		panic(errors.ErrShape)
+	}
+	mv = MakeMultivector(*o)
+	for i, x := range v.X {
+		mv.X[1<<uint(i)] = x
+	}
+	return
+}
+
+// Pseudoscalar returns the unit blade of highest grade
+//
+// Parameters:
+//
+//	o *Algebra - The algebra
+//
+// Returns:
+//
+//	mv Multivector - The blade e0 * e1 * ... * e(n-1)
+func (o *Algebra) Pseudoscalar() (mv Multivector) {
+	mv = MakeMultivector(*o)
+	mv.X[len(mv.X)-1] = 1
+	return
+}
+
+// sign returns the coefficient of e_(a ^ b) in the geometric product of the basis blades e_a and e_b
+func (o *Algebra) sign(a, b uint) (s float64) {
+	s = reorderSign(a, b)
+	for common := a & b; common != 0; common &= common - 1 {
+		s *= o.Metric(bits.TrailingZeros(common))
+	}
+	return
+}
+
+// reorderSign returns the sign of the permutation that sorts the basis vectors of e_a * e_b
+func reorderSign(a, b uint) float64 {
+	swaps := 0
+	for a >>= 1; a != 0; a >>= 1 {
+		swaps += bits.OnesCount(a & b)
+	}
+	if swaps%2 == 1 {
+		return -1
+	}
+	return 1
+}
+
+// grade returns the grade of the basis blade e_a
+func grade(a uint) int {
+	return bits.OnesCount(a)
+}
@@ -0,0 +1,484 @@
+package ga
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+	"github.com/add1609/lin/scalar"
+)
+
+// Multivector is an element of a geometric algebra
+//
+// X holds the 2ⁿ coefficients of the basis blades, the coefficient of
+// e_i1 * ... * e_ik with i1 < ... < ik is stored at the index whose bits
+// i1, ..., ik are set. X[0] is the scalar part and X[2ⁿ - 1] the
+// pseudoscalar part.
+type Multivector struct {
+	Alg Algebra
+	X   []float64
+}
+
+func (o Multivector) String() (str string) {
+	for a, x := range o.X {
+		if x == 0 {
+			continue
+		}
+		if str != "" {
+			str += " + "
+		}
+		str += fmt.Sprintf("%v", scalar.RoundTo(x, 4))
+		if a != 0 {
+			str += "*e"
+			for i := 0; a>>uint(i) != 0; i++ {
+				if a&(1<<uint(i)) != 0 {
+					str += strconv.Itoa(i)
+				}
+			}
+		}
+	}
+	if str == "" {
+		str = "0"
+	}
+	return
+}
+
+// MakeMultivector returns the zero multivector of an algebra
+//
+// Parameters:
+//
+//	alg Algebra - The algebra
+//
+// Returns:
+//
+//	mv Multivector - The multivector with all 2ⁿ coefficients set to 0
+func MakeMultivector(alg Algebra) (mv Multivector) {
+	mv = Multivector{Alg: alg, X: make([]float64, 1<<uint(alg.Dim()))}
+	return
+}
+
+// check panics if o and q belong to different algebras
+func (o *Multivector) check(q Multivector) {
+	if o.Alg != q.Alg {
+		panic(errors.ErrShape)
+	}
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Multivector - multivector to compare to q
+//	q Multivector - multivector to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q belong to the same algebra and have the same coefficients
+func (o *Multivector) Equal(q Multivector) bool {
+	if o.Alg != q.Alg {
+		return false
+	}
+	for a, x := range o.X {
+		if x != q.X[a] {
+			return false
+		}
+	}
+	return true
+}
+
+// Grade returns the part of a given grade
+//
+// Parameters:
+//
+//	k int - The grade
+//
+// Returns:
+//
+//	mv Multivector - The sum of the terms of o whose blades have k basis vectors
+func (o *Multivector) Grade(k int) (mv Multivector) {
+	mv = MakeMultivector(o.Alg)
+	for a, x := range o.X {
+		if grade(uint(a)) == k {
+			mv.X[a] = x
+		}
+	}
+	return
+}
+
+// Vec returns the grade 1 part as a vector
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	v rn.Vec - The coefficients of the Dim basis vectors
+func (o *Multivector) Vec() (v rn.Vec) {
+	v = rn.MakeVec(o.Alg.Dim(), 0)
+	for i := range v.X {
+		v.X[i] = o.X[1<<uint(i)]
+	}
+	return
+}
+
+// Add returns the sum of o and q
+//
+// Parameters:
+//
+//	o *Multivector - The first summand
+//	q Multivector - The second summand
+//
+// Returns:
+//
+//	mv Multivector - o + q
+func (o *Multivector) Add(q Multivector) (mv Multivector) {
+	o.check(q)
+	mv = MakeMultivector(o.Alg)
+	for a := range mv.X {
+		mv.X[a] = o.X[a] + q.X[a]
+	}
+	return
+}
+
+// Sub returns the difference of o and q
+//
+// Parameters:
+//
+//	o *Multivector - The minuend
+//	q Multivector - The subtrahend
+//
+// Returns:
+//
+//	mv Multivector - o - q
+func (o *Multivector) Sub(q Multivector) (mv Multivector) {
+	o.check(q)
+	mv = MakeMultivector(o.Alg)
+	for a := range mv.X {
+		mv.X[a] = o.X[a] - q.X[a]
+	}
+	return
+}
+
+// Scale returns o multiplied by a scalar
+//
+// Parameters:
+//
+//	s float64 - The scalar
+//
+// Returns:
+//
+//	mv Multivector - s * o
+func (o *Multivector) Scale(s float64) (mv Multivector) {
+	mv = MakeMultivector(o.Alg)
+	for a, x := range o.X {
+		mv.X[a] = s * x
+	}
+	return
+}
+
+// product returns the sum of the geometric products of the basis blades of o and q accepted by keep
+func (o *Multivector) product(q Multivector, keep func(a, b uint) bool) (mv Multivector) {
+	o.check(q)
+	mv = MakeMultivector(o.Alg)
+	for a, x := range o.X {
+		if x == 0 {
+			continue
+		}
+		for b, y := range q.X {
+			if y == 0 || !keep(uint(a), uint(b)) {
+				continue
+			}
+			if s := o.Alg.sign(uint(a), uint(b)); s != 0 {
+				mv.X[a^b] += s * x * y
+			}
+		}
+	}
+	return
+}
+
+// Mul returns the geometric product of o and q
+//
+// Parameters:
+//
+//	o *Multivector - The left factor
+//	q Multivector - The right factor
+//
+// Returns:
+//
+//	mv Multivector - o * q
+func (o *Multivector) Mul(q Multivector) (mv Multivector) {
+	mv = o.product(q, func(a, b uint) bool { return true })
+	return
+}
+
+// Wedge returns the outer product of o and q
+//
+// Parameters:
+//
+//	o *Multivector - The left factor
+//	q Multivector - The right factor
+//
+// Returns:
+//
+//	mv Multivector - o ∧ q, the terms of the geometric product whose grade is the sum of the
+//	grades of the factors
+func (o *Multivector) Wedge(q Multivector) (mv Multivector) {
+	mv = o.product(q, func(a, b uint) bool { return a&b == 0 })
+	return
+}
+
+// Inner returns the inner product of o and q as the left contraction
+//
+// Parameters:
+//
+//	o *Multivector - The left factor
+//	q Multivector - The right factor
+//
+// Returns:
+//
+//	mv Multivector - o ⌋ q, the terms of the geometric product of a blade of grade k and a
+//	blade of grade l whose grade is l - k, which vanish for k > l
+func (o *Multivector) Inner(q Multivector) (mv Multivector) {
+	mv = o.product(q, func(a, b uint) bool { return a&^b == 0 })
+	return
+}
+
+// ScalarProduct returns the scalar part of the geometric product of o and q
+//
+// Parameters:
+//
+//	o *Multivector - The left factor
+//	q Multivector - The right factor
+//
+// Returns:
+//
+//	s float64 - <o * q>₀
+func (o *Multivector) ScalarProduct(q Multivector) (s float64) {
+	o.check(q)
+	for a, x := range o.X {
+		s += o.Alg.sign(uint(a), uint(a)) * x * q.X[a]
+	}
+	return
+}
+
+// Regressive returns the regressive product of o and q
+//
+// The regressive product is the outer product of the complements and does
+// not depend on the metric, so it also joins points in degenerate algebras.
+//
+// Parameters:
+//
+//	o *Multivector - The left factor
+//	q Multivector - The right factor
+//
+// Returns:
+//
+//	mv Multivector - o ∨ q = Undual(Dual(o) ∧ Dual(q))
+func (o *Multivector) Regressive(q Multivector) (mv Multivector) {
+	a, b := o.Dual(), q.Dual()
+	ab := a.Wedge(b)
+	mv = ab.Undual()
+	return
+}
+
+// mapGrades returns o with every term of grade k multiplied by f(k)
+func (o *Multivector) mapGrades(f func(k int) float64) (mv Multivector) {
+	mv = MakeMultivector(o.Alg)
+	for a, x := range o.X {
+		mv.X[a] = f(grade(uint(a))) * x
+	}
+	return
+}
+
+// Reverse returns the reverse of o
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	mv Multivector - õ, the order of the basis vectors of every blade reversed, which
+//	negates the grades 2 and 3 modulo 4
+func (o *Multivector) Reverse() (mv Multivector) {
+	mv = o.mapGrades(func(k int) float64 {
+		if k%4 >= 2 {
+			return -1
+		}
+		return 1
+	})
+	return
+}
+
+// Involute returns the grade involution of o
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	mv Multivector - o with every basis vector negated, which negates the odd grades
+func (o *Multivector) Involute() (mv Multivector) {
+	mv = o.mapGrades(func(k int) float64 {
+		if k%2 == 1 {
+			return -1
+		}
+		return 1
+	})
+	return
+}
+
+// Conjugate returns the Clifford conjugate of o
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	mv Multivector - the reverse of the grade involution, which negates the grades 1 and 2
+//	modulo 4
+func (o *Multivector) Conjugate() (mv Multivector) {
+	mv = o.mapGrades(func(k int) float64 {
+		if k%4 == 1 || k%4 == 2 {
+			return -1
+		}
+		return 1
+	})
+	return
+}
+
+// Dual returns the right complement of o
+//
+// The complement does not use the metric, unlike multiplication by the
+// inverse pseudoscalar which does not exist in degenerate algebras.
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	mv Multivector - the multivector with e_A ∧ Dual(e_A) = Pseudoscalar() for every
+//	basis blade e_A
+func (o *Multivector) Dual() (mv Multivector) {
+	mv = MakeMultivector(o.Alg)
+	all := uint(len(o.X) - 1)
+	for a, x := range o.X {
+		c := all &^ uint(a)
+		mv.X[c] = reorderSign(uint(a), c) * x
+	}
+	return
+}
+
+// Undual returns the inverse of Dual
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	mv Multivector - the multivector with Dual(mv) = o
+func (o *Multivector) Undual() (mv Multivector) {
+	mv = MakeMultivector(o.Alg)
+	all := uint(len(o.X) - 1)
+	for c, x := range o.X {
+		a := all &^ uint(c)
+		mv.X[a] = reorderSign(a, uint(c)) * x
+	}
+	return
+}
+
+// Norm returns the norm of o
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	nrm float64 - sqrt(|<o * õ>₀|), which ignores the blades that contain a null vector
+func (o *Multivector) Norm() (nrm float64) {
+	rev := o.Reverse()
+	nrm = math.Sqrt(math.Abs(o.ScalarProduct(rev)))
+	return
+}
+
+// Normalize returns o scaled to unit norm
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	mv Multivector - o / Norm(o)
+//	err error - ErrDegenerate if the norm is 0
+func (o *Multivector) Normalize() (mv Multivector, err error) {
+	nrm := o.Norm()
+	if nrm == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	mv = o.Scale(1 / nrm)
+	return
+}
+
+// Inverse returns the inverse of o with respect to the geometric product
+//
+// Parameters:
+//
+//	o *Multivector - The multivector
+//
+// Returns:
+//
+//	inv Multivector - the multivector with o * inv = 1, found by solving the linear system of
+//	left multiplication by o
+//	err error - ErrSingular if o has no inverse
+func (o *Multivector) Inverse() (inv Multivector, err error) {
+	n := len(o.X)
+	L := rn.MakeMat(n, n, 0)
+	for b := 0; b < n; b++ {
+		col := MakeMultivector(o.Alg)
+		col.X[b] = 1
+		col = o.Mul(col)
+		L.SetCol(b, rn.Vec{N: n, X: col.X})
+	}
+	LInv, err := L.Inverse()
+	if err != nil {
+		err = errors.ErrSingular
+		return
+	}
+	inv = Multivector{Alg: o.Alg, X: LInv.GetCol(0).X}
+	// elimination only detects exactly singular systems, so check the residual
+	check := o.Mul(inv)
+	check.X[0]--
+	for a, x := range check.X {
+		if math.Abs(x) > 1e-9*(1+math.Abs(o.X[a])) {
+			err = errors.ErrSingular
+			return
+		}
+	}
+	return
+}
+
+// Sandwich returns the sandwich product of o applied to X
+//
+// Parameters:
+//
+//	o *Multivector - A versor, the product of invertible vectors, usually normalized
+//	X Multivector - The element to transform
+//
+// Returns:
+//
+//	mv Multivector - o * X * õ, the transformation of X by the unit versor o
+func (o *Multivector) Sandwich(X Multivector) (mv Multivector) {
+	mv = o.Mul(X)
+	mv = mv.Mul(o.Reverse())
+	return
+}
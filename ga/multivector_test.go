@@ -0,0 +1,156 @@
+package ga
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+func mvClose(a, b Multivector, tol float64) bool {
+	if a.Alg != b.Alg {
+		return false
+	}
+	for i := range a.X {
+		if math.Abs(a.X[i]-b.X[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func randomMultivector(alg Algebra, rng *rand.Rand) (mv Multivector) {
+	mv = MakeMultivector(alg)
+	for i := range mv.X {
+		mv.X[i] = 2*rng.Float64() - 1
+	}
+	return
+}
+
+func TestMultivectorProducts(t *testing.T) {
+	tests := []struct {
+		alg  Algebra
+		a, b []int
+		want Multivector
+	}{
+		{G3, []int{0}, []int{1}, G3.Blade(1, 0, 1)},
+		{G3, []int{1}, []int{0}, G3.Blade(-1, 0, 1)},
+		{G3, []int{2}, []int{2}, G3.Scalar(1)},
+		{G3, []int{0, 1}, []int{0, 1}, G3.Scalar(-1)},
+		{G3, []int{0, 1}, []int{1, 2}, G3.Blade(1, 0, 2)},
+		{PGA3, []int{0}, []int{0}, PGA3.Scalar(0)},
+		{PGA3, []int{0, 1}, []int{1}, PGA3.Blade(1, 0)},
+		{CGA3, []int{4}, []int{4}, CGA3.Scalar(-1)},
+		{CGA3, []int{3}, []int{3}, CGA3.Scalar(1)},
+	}
+	for _, test := range tests {
+		a, b := test.alg.Blade(1, test.a...), test.alg.Blade(1, test.b...)
+		if got := a.Mul(b); !got.Equal(test.want) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.want)
+		}
+	}
+
+	// for vectors the geometric product splits into inner and outer product
+	u := G3.Vector(rn.Vec{N: 3, X: []float64{1, 2, 3}})
+	v := G3.Vector(rn.Vec{N: 3, X: []float64{-2, 0, 5}})
+	inner, outer := u.Inner(v), u.Wedge(v)
+	sum := inner.Add(outer)
+	if got := u.Mul(v); !got.Equal(sum) || inner.X[0] != 13 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, sum)
+	}
+	// the left contraction of a vector onto a bivector lies in the bivector
+	e0, e01 := G3.Blade(1, 0), G3.Blade(1, 0, 1)
+	if got := e0.Inner(e01); !got.Equal(G3.Blade(1, 1)) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, G3.Blade(1, 1))
+	}
+	if got := e01.Inner(e0); !got.Equal(G3.Scalar(0)) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, G3.Scalar(0))
+	}
+	if got := G3.Blade(1, 2, 1, 0); !got.Equal(G3.Blade(-1, 0, 1, 2)) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, G3.Blade(-1, 0, 1, 2))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, alg := range []Algebra{G3, PGA3, CGA3, MakeAlgebra(1, 2, 0)} {
+		a, b, c := randomMultivector(alg, rng), randomMultivector(alg, rng), randomMultivector(alg, rng)
+		// associativity
+		ab, bc := a.Mul(b), b.Mul(c)
+		left, right := ab.Mul(c), a.Mul(bc)
+		if !mvClose(left, right, 1e-12) {
+			t.Errorf("error:\ngot=%v\nwant=%v", left, right)
+		}
+		// reversion is an anti-automorphism
+		abRev, aRev, bRev := ab.Reverse(), a.Reverse(), b.Reverse()
+		if got := bRev.Mul(aRev); !mvClose(got, abRev, 1e-12) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, abRev)
+		}
+		// the conjugate is the reverse of the involution
+		inv := a.Involute()
+		if got, want := a.Conjugate(), inv.Reverse(); !got.Equal(want) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+		dual := a.Dual()
+		if got := dual.Undual(); !got.Equal(a) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, a)
+		}
+		// the grades add up to the multivector
+		sum := MakeMultivector(alg)
+		for k := 0; k <= alg.Dim(); k++ {
+			sum = sum.Add(a.Grade(k))
+		}
+		if !sum.Equal(a) {
+			t.Errorf("error:\ngot=%v\nwant=%v", sum, a)
+		}
+	}
+}
+
+func TestMultivectorInverse(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, alg := range []Algebra{G3, CGA3} {
+		a := randomMultivector(alg, rng)
+		inv, err := a.Inverse()
+		if err != nil {
+			t.Fatalf("error:\n%v\n", err)
+		}
+		if got, want := a.Mul(inv), alg.Scalar(1); !mvClose(got, want, 1e-9) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+	}
+	// a vector is inverted by v / v²
+	v := CGA3.Vector(rn.Vec{N: 5, X: []float64{1, 2, 0, 0, 1}})
+	inv, err := v.Inverse()
+	if want := v.Scale(0.25); err != nil || !mvClose(inv, want, 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", inv, err, want)
+	}
+	// null vectors have no inverse
+	tests := []Multivector{PGA3.Blade(1, 0), CGA3.Vector(rn.Vec{N: 5, X: []float64{0, 0, 0, 1, 1}})}
+	for _, test := range tests {
+		if _, err := test.Inverse(); err != errors.ErrSingular {
+			t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrSingular)
+		}
+	}
+}
+
+func TestMultivectorRegressive(t *testing.T) {
+	P1, P2 := PGA3.Point(rn.Vec{N: 3, X: []float64{1, 0, 0}}), PGA3.Point(rn.Vec{N: 3, X: []float64{2, 1, 1}})
+	line := P1.Regressive(P2)
+	if got := line.Grade(2); !got.Equal(line) || line.Norm() == 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", line, "a bivector")
+	}
+	tests := []struct {
+		P     rn.Vec
+		plane bool
+	}{
+		{rn.Vec{N: 3, X: []float64{3, 2, 2}}, false},
+		{rn.Vec{N: 3, X: []float64{-1, -2, -2}}, false},
+		{rn.Vec{N: 3, X: []float64{0, 0, 0}}, true},
+	}
+	for _, test := range tests {
+		join := line.Regressive(PGA3.Point(test.P))
+		if got := join.Norm() > 1e-12; got != test.plane {
+			t.Errorf("error:\ngot=%v\nwant=%v", join, test.plane)
+		}
+	}
+}
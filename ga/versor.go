@@ -0,0 +1,263 @@
+package ga
+
+import (
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/gm"
+	"github.com/add1609/lin/rn"
+)
+
+// model is the way an algebra represents points of Euclidean space
+type model int
+
+const (
+	euclidean  model = iota // G(n, 0, 0), points are vectors from the origin
+	projective              // G(n, 0, 1), points are (n)-blades, the duals of e0 + x
+	conformal               // G(n + 1, 1, 0), points are null vectors x + ½x²e∞ + eₒ
+)
+
+// model returns the point model of the algebra, it panics for other signatures
+func (o *Algebra) model() model {
+	switch {
+	case o.Q == 0 && o.R == 0:
+		return euclidean
+	case o.Q == 0 && o.R == 1 && o.P > 0:
+		return projective
+	case o.Q == 1 && o.R == 0 && o.P > 1:
+		return conformal
+	}
+	panic(errors.ErrOrder)
+}
+
+// Space returns the dimension of the Euclidean space modelled by the algebra
+//
+// Parameters:
+//
+//	o *Algebra - A Euclidean, projective or conformal algebra
+//
+// Returns:
+//
+//	n int - P for G(n, 0, 0) and G(n, 0, 1), P - 1 for G(n + 1, 1, 0)
+func (o *Algebra) Space() (n int) {
+	n = o.P
+	if o.model() == conformal {
+		n--
+	}
+	return
+}
+
+// euclid returns the vector v of the Euclidean basis vectors e_R, ..., e_(R+n-1)
+func (o *Algebra) euclid(v rn.Vec) (mv Multivector) {
+	if v.N != o.Space() {
+		panic(errors.ErrShape)
+	}
+	mv = MakeMultivector(*o)
+	for i, x := range v.X {
+		mv.X[1<<uint(o.R+i)] = x
+	}
+	return
+}
+
+// Point returns the representation of a point of Euclidean space
+//
+// Parameters:
+//
+//	P rn.Vec - The point, of dimension Space
+//
+// Returns:
+//
+//	mv Multivector - P as a vector for G(n, 0, 0), Dual(e0 + P) for G(n, 0, 1) and
+//	P + ½|P|²e∞ + eₒ with e∞ = e+ + e- and eₒ = ½(e- - e+) for G(n + 1, 1, 0)
+func (o *Algebra) Point(P rn.Vec) (mv Multivector) {
+	mv = o.euclid(P)
+	switch o.model() {
+	case projective:
+		mv.X[1] = 1
+		mv = mv.Dual()
+	case conformal:
+		half := P.Dot(P) / 2
+		n := o.Space()
+		mv.X[1<<uint(n)] = half - 0.5
+		mv.X[1<<uint(n+1)] = half + 0.5
+	}
+	return
+}
+
+// ToPoint returns the point of Euclidean space that a multivector represents
+//
+// Parameters:
+//
+//	X Multivector - A point as returned by Point, up to a non-zero factor for the
+//	projective and conformal models
+//
+// Returns:
+//
+//	P rn.Vec - The point
+//	err error - ErrInfinity if X is an ideal point or the point at infinity
+func (o *Algebra) ToPoint(X Multivector) (P rn.Vec, err error) {
+	if X.Alg != *o {
+		panic(errors.ErrShape)
+	}
+	n := o.Space()
+	H := rn.MakeVec(n+1, 1)
+	switch o.model() {
+	case projective:
+		v := X.Undual()
+		X = v
+		H.X[n] = v.X[1]
+	}
+	for i := 0; i < n; i++ {
+		H.X[i] = X.X[1<<uint(o.R+i)]
+	}
+	if o.model() == conformal {
+		// the weight is -X · e∞ = e- - e+, which is 1 for the points returned by Point.
+		// A point is a null vector, so (e- - e+) * (e- + e+) = |x|², and far from the
+		// origin the quotient avoids the cancellation of the difference
+		minus, plus := X.X[1<<uint(n+1)], X.X[1<<uint(n)]
+		H.X[n] = minus - plus
+		if math.Abs(minus+plus) > math.Abs(H.X[n]) {
+			x := rn.Vec{N: n, X: H.X[:n]}
+			H.X[n] = x.Dot(x) / (minus + plus)
+		}
+	}
+	P, err = gm.FromHomogeneous(H)
+	return
+}
+
+// Rotor returns the rotor that turns the direction of a into the direction of b
+//
+// Parameters:
+//
+//	a rn.Vec - The direction to rotate from, of dimension Space
+//	b rn.Vec - The direction to rotate to, of dimension Space
+//
+// Returns:
+//
+//	R Multivector - The unit rotor (1 + b̂ * â) / |1 + b̂ * â| of the rotation about the origin
+//	in the plane of a and b by the angle between them
+//	err error - ErrDegenerate if a or b is zero or they point in opposite directions
+func (o *Algebra) Rotor(a, b rn.Vec) (R Multivector, err error) {
+	na, nb := a.Norm(), b.Norm()
+	if na == 0 || nb == 0 || a.Dot(b) <= -(1-1e-12)*na*nb {
+		err = errors.ErrDegenerate
+		return
+	}
+	A, B := o.euclid(a.Scale(1/na)), o.euclid(b.Scale(1/nb))
+	R = B.Mul(A)
+	R.X[0]++
+	R, err = R.Normalize()
+	return
+}
+
+// Translator returns the versor that translates by t
+//
+// Parameters:
+//
+//	t rn.Vec - The translation, of dimension Space
+//
+// Returns:
+//
+//	T Multivector - The unit versor 1 - ½e0 * t for G(n, 0, 1) and 1 - ½t * e∞ for
+//	G(n + 1, 1, 0), G(n, 0, 0) has no translations and panics
+func (o *Algebra) Translator(t rn.Vec) (T Multivector) {
+	d := o.euclid(t)
+	switch o.model() {
+	case euclidean:
+		panic(errors.ErrOrder)
+	case projective:
+		e0 := o.Blade(-0.5, 0)
+		T = e0.Mul(d)
+	case conformal:
+		n := o.Space()
+		inf := o.Blade(-0.5, n)
+		inf = inf.Add(o.Blade(-0.5, n+1))
+		T = d.Mul(inf)
+	}
+	T.X[0]++
+	return
+}
+
+// ApplyPoint returns the image of a point under a versor
+//
+// The conformal model applies V * T to the origin, where T translates by P,
+// so translations of far points are as accurate as in the projective model.
+// Other versors still meet the ½|P|² coefficients of the e+ and e- axes and
+// lose about |P|² times the machine epsilon in absolute accuracy.
+//
+// Parameters:
+//
+//	V Multivector - A unit versor such as a product of rotors and translators
+//	P rn.Vec - The point, of dimension Space
+//
+// Returns:
+//
+//	P1 rn.Vec - The point represented by V * Point(P) * Ṽ
+//	err error - ErrInfinity if V maps P to infinity
+func (o *Algebra) ApplyPoint(V Multivector, P rn.Vec) (P1 rn.Vec, err error) {
+	if o.model() == conformal {
+		T := o.Translator(P)
+		V = V.Mul(T)
+		P = rn.MakeVec(P.N, 0)
+	}
+	X := V.Sandwich(o.Point(P))
+	P1, err = o.ToPoint(X)
+	return
+}
+
+// applyFrame returns the images of a point and of the directions attached to it
+func (o *Algebra) applyFrame(V Multivector, P rn.Vec, D ...rn.Vec) (P1 rn.Vec, D1 []rn.Vec, err error) {
+	if P1, err = o.ApplyPoint(V, P); err != nil {
+		return
+	}
+	for _, d := range D {
+		var Q rn.Vec
+		if Q, err = o.ApplyPoint(V, P.Add(d)); err != nil {
+			return
+		}
+		D1 = append(D1, Q.Sub(P1))
+	}
+	return
+}
+
+// ApplyLine returns the image of a line under a versor
+//
+// Parameters:
+//
+//	V Multivector - A unit versor that maps lines to lines, such as a rigid motion
+//	line gm.Line - The line, of dimension Space
+//
+// Returns:
+//
+//	line1 gm.Line - The line through the image of line.V1 whose direction is the image of
+//	line.V2
+//	err error - ErrInfinity if V maps a point of the line to infinity
+func (o *Algebra) ApplyLine(V Multivector, line gm.Line) (line1 gm.Line, err error) {
+	P1, D1, err := o.applyFrame(V, line.V1, line.V2)
+	if err != nil {
+		return
+	}
+	line1 = gm.MakeLine(P1, D1[0])
+	return
+}
+
+// ApplyPlane returns the image of a plane under a versor
+//
+// Parameters:
+//
+//	V Multivector - A unit versor that maps planes to planes, such as a rigid motion
+//	plane gm.Plane - The plane, of dimension Space
+//
+// Returns:
+//
+//	plane1 gm.Plane - The plane through the image of plane.V1 whose directions are the
+//	images of plane.V2 and plane.V3
+//	err error - ErrInfinity if V maps a point of the plane to infinity
+func (o *Algebra) ApplyPlane(V Multivector, plane gm.Plane) (plane1 gm.Plane, err error) {
+	P1, D1, err := o.applyFrame(V, plane.V1, plane.V2, plane.V3)
+	if err != nil {
+		return
+	}
+	plane1 = gm.MakePlane(P1, D1[0], D1[1])
+	return
+}
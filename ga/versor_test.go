@@ -0,0 +1,153 @@
+package ga
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/gm"
+	"github.com/add1609/lin/rn"
+)
+
+func v3(x, y, z float64) rn.Vec {
+	return rn.Vec{N: 3, X: []float64{x, y, z}}
+}
+
+func vecClose(a, b rn.Vec, tol float64) bool {
+	if a.N != b.N {
+		return false
+	}
+	for i := range a.X {
+		if math.Abs(a.X[i]-b.X[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPoint(t *testing.T) {
+	P := v3(1, -2, 0.5)
+	for _, alg := range []Algebra{G3, PGA3, CGA3} {
+		X := alg.Point(P)
+		if alg != G3 {
+			// homogeneous representations are independent of scale
+			X = X.Scale(-3)
+		}
+		if got, err := alg.ToPoint(X); err != nil || !vecClose(got, P, 1e-12) {
+			t.Errorf("error:\n%v: got=%v, %v\nwant=%v", alg, got, err, P)
+		}
+	}
+	// conformal points are null vectors whose inner product is -½ the squared distance
+	Q := v3(4, 2, 0.5)
+	X, Y := CGA3.Point(P), CGA3.Point(Q)
+	if got := X.ScalarProduct(X); math.Abs(got) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 0)
+	}
+	if got := X.ScalarProduct(Y); math.Abs(got+12.5) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, -12.5)
+	}
+	// the direction e1 is an ideal point of PGA
+	ideal := PGA3.Blade(1, 1)
+	if _, err := PGA3.ToPoint(ideal.Dual()); err != errors.ErrInfinity {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+}
+
+func TestRotor(t *testing.T) {
+	a, b := v3(1, 0, 0), v3(0, 2, 0)
+	for _, alg := range []Algebra{G3, PGA3, CGA3} {
+		R, err := alg.Rotor(a, b)
+		if err != nil {
+			t.Fatalf("error:\n%v\n", err)
+		}
+		tests := [][2]rn.Vec{
+			{v3(3, 0, 0), v3(0, 3, 0)},
+			{v3(0, 1, 0), v3(-1, 0, 0)},
+			{v3(0, 0, 5), v3(0, 0, 5)},
+		}
+		for _, test := range tests {
+			if got, err := alg.ApplyPoint(R, test[0]); err != nil || !vecClose(got, test[1], 1e-12) {
+				t.Errorf("error:\n%v: got=%v, %v\nwant=%v", alg, got, err, test[1])
+			}
+		}
+	}
+
+	// the rotor agrees with the rotation matrix about the normal by the angle between the vectors
+	a, b = v3(1, 2, -1), v3(-0.5, 1, 3)
+	R, _ := G3.Rotor(a, b)
+	rot := gm.MakeRotationAxisAngle(a.Cross(b), math.Acos(a.Cos(b)))
+	P := v3(0.3, -4, 2)
+	if got, _ := G3.ApplyPoint(R, P); !vecClose(got, rot.MulVec(P), 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, rot.MulVec(P))
+	}
+
+	if _, err := G3.Rotor(a, a.Scale(-2)); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+	if _, err := G3.Rotor(a, v3(0, 0, 0)); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
+
+func TestMotor(t *testing.T) {
+	shift := v3(1, 2, 3)
+	a, b := v3(0, 0, 1), v3(1, 0, 0)
+	rot := gm.MakeRotationAxisAngle(a.Cross(b), math.Pi/2)
+	transform := func(P rn.Vec) rn.Vec {
+		Q := rot.MulVec(P)
+		return Q.Add(shift)
+	}
+	line := gm.MakeLine(v3(1, 1, 0), v3(0, 1, 1))
+	plane := gm.MakePlane(v3(0, 0, 2), v3(1, 0, 0), v3(0, 1, 1))
+	for _, alg := range []Algebra{PGA3, CGA3} {
+		T := alg.Translator(shift)
+		if got, err := alg.ApplyPoint(T, v3(0, 0, 0)); err != nil || !vecClose(got, shift, 1e-12) {
+			t.Errorf("error:\n%v: got=%v, %v\nwant=%v", alg, got, err, shift)
+		}
+		// far points keep their weight
+		if got, err := alg.ApplyPoint(T, v3(3e9, 1, 1)); err != nil || !vecClose(got, v3(3e9+1, 3, 4), 1e-6) {
+			t.Errorf("error:\n%v: got=%v, %v\nwant=%v", alg, got, err, v3(3e9+1, 3, 4))
+		}
+		R, _ := alg.Rotor(a, b)
+		// first rotate, then translate
+		M := T.Mul(R)
+		if got := M.Norm(); math.Abs(got-1) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, 1)
+		}
+
+		line1, err := alg.ApplyLine(M, line)
+		if err != nil {
+			t.Fatalf("error:\n%v\n", err)
+		}
+		if want := transform(line.V1); !vecClose(line1.V1, want, 1e-12) {
+			t.Errorf("error:\n%v: got=%v\nwant=%v", alg, line1.V1, want)
+		}
+		if want := rot.MulVec(line.V2); !vecClose(line1.V2, want, 1e-12) {
+			t.Errorf("error:\n%v: got=%v\nwant=%v", alg, line1.V2, want)
+		}
+
+		plane1, err := alg.ApplyPlane(M, plane)
+		if err != nil {
+			t.Fatalf("error:\n%v\n", err)
+		}
+		want := gm.MakePlane(transform(plane.V1), rot.MulVec(plane.V2), rot.MulVec(plane.V3))
+		if !vecClose(plane1.V1, want.V1, 1e-12) || !vecClose(plane1.V2, want.V2, 1e-12) || !vecClose(plane1.V3, want.V3, 1e-12) {
+			t.Errorf("error:\n%v: got=%v\nwant=%v", alg, plane1, want)
+		}
+	}
+
+	// PGA planes are vectors and transform by the same sandwich
+	P1, P2, P3 := plane.V1, plane.V1.Add(plane.V2), plane.V1.Add(plane.V3)
+	X1, X2, X3 := PGA3.Point(P1), PGA3.Point(P2), PGA3.Point(P3)
+	line12 := X1.Regressive(X2)
+	p := line12.Regressive(X3)
+	T := PGA3.Translator(shift)
+	p1 := T.Sandwich(p)
+	for _, P := range []rn.Vec{P1, P2, P3} {
+		X := PGA3.Point(P.Add(shift))
+		// a point lies on a plane if their outer product vanishes
+		if on := p1.Wedge(X); math.Abs(on.X[len(on.X)-1]) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", on, 0)
+		}
+	}
+}
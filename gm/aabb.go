@@ -0,0 +1,427 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// AABB is an axis-aligned bounding box with corners Min and Max
+type AABB struct {
+	Min, Max rn.Vec
+}
+
+func (o AABB) String() (str string) {
+	str += fmt.Sprintf("[%v, %v]", o.Min, o.Max)
+	return
+}
+
+// MakeAABB returns the axis-aligned box spanned by two opposite corners
+//
+// Parameters:
+//
+//	P1 rn.Vec - A corner
+//	P2 rn.Vec - The opposite corner
+//
+// Returns:
+//
+//	box AABB - The box with Min and Max set to the componentwise minimum and maximum of P1 and P2
+func MakeAABB(P1, P2 rn.Vec) (box AABB) {
+	if P1.N != P2.N {
+		panic(errors.ErrShape)
+	}
+	box.Min = rn.MakeVec(P1.N, 0)
+	box.Max = rn.MakeVec(P1.N, 0)
+	for i := 0; i < P1.N; i++ {
+		box.Min.X[i] = math.Min(P1.X[i], P2.X[i])
+		box.Max.X[i] = math.Max(P1.X[i], P2.X[i])
+	}
+	return
+}
+
+// MakeAABBFromPoints returns the smallest axis-aligned box that contains all points
+//
+// Parameters:
+//
+//	Ps []rn.Vec - The points, at least one
+//
+// Returns:
+//
+//	box AABB - The bounding box of Ps
+func MakeAABBFromPoints(Ps []rn.Vec) (box AABB) {
+	if len(Ps) == 0 {
+		panic(errors.ErrZeroLengthVec)
+	}
+	box = MakeAABB(Ps[0], Ps[0])
+	for _, P := range Ps[1:] {
+		box.Extend(P)
+	}
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *AABB - box to compare to q
+//	q AABB - box to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *AABB) Equal(q AABB) bool {
+	return o.Min.Equal(q.Min) && o.Max.Equal(q.Max)
+}
+
+// AABB returns a copy of the box, so that AABB satisfies the Primitive interface
+//
+// Parameters:
+//
+//	o *AABB - The box
+//
+// Returns:
+//
+//	box AABB - A copy of o
+func (o *AABB) AABB() (box AABB) {
+	box = MakeAABB(o.Min, o.Max)
+	return
+}
+
+// Extend grows the box in place so that it contains P
+//
+// Parameters:
+//
+//	P rn.Vec - The point to include
+//
+// Returns:
+//
+//	none
+func (o *AABB) Extend(P rn.Vec) {
+	if P.N != o.Min.N {
+		panic(errors.ErrShape)
+	}
+	for i := 0; i < P.N; i++ {
+		o.Min.X[i] = math.Min(o.Min.X[i], P.X[i])
+		o.Max.X[i] = math.Max(o.Max.X[i], P.X[i])
+	}
+}
+
+// Center returns the midpoint of the box
+//
+// Parameters:
+//
+//	o *AABB - The box
+//
+// Returns:
+//
+//	P1 rn.Vec - The center (Min + Max) / 2
+func (o *AABB) Center() (P1 rn.Vec) {
+	P1 = o.Min.Add(o.Max)
+	P1 = P1.Scale(0.5)
+	return
+}
+
+// Extents returns the half side lengths of the box
+//
+// Parameters:
+//
+//	o *AABB - The box
+//
+// Returns:
+//
+//	E rn.Vec - The half extents (Max - Min) / 2
+func (o *AABB) Extents() (E rn.Vec) {
+	E = o.Max.Sub(o.Min)
+	E = E.Scale(0.5)
+	return
+}
+
+// Volume returns the product of the side lengths
+//
+// Parameters:
+//
+//	o *AABB - The box
+//
+// Returns:
+//
+//	vol float64 - The volume (area in 2D)
+func (o *AABB) Volume() (vol float64) {
+	vol = 1
+	for i := 0; i < o.Min.N; i++ {
+		vol *= o.Max.X[i] - o.Min.X[i]
+	}
+	return
+}
+
+// SurfaceArea returns the total area of the faces of the box
+//
+// Parameters:
+//
+//	o *AABB - The box
+//
+// Returns:
+//
+//	area float64 - The surface area
+func (o *AABB) SurfaceArea() (area float64) {
+	for i := 0; i < o.Min.N; i++ {
+		face := 1.0
+		for j := 0; j < o.Min.N; j++ {
+			if j != i {
+				face *= o.Max.X[j] - o.Min.X[j]
+			}
+		}
+		area += 2 * face
+	}
+	return
+}
+
+// Union returns the smallest box that contains o and q
+//
+// Parameters:
+//
+//	o *AABB - The first box
+//	q AABB - The second box
+//
+// Returns:
+//
+//	box AABB - The union box
+func (o *AABB) Union(q AABB) (box AABB) {
+	box = MakeAABB(o.Min, o.Max)
+	box.Extend(q.Min)
+	box.Extend(q.Max)
+	return
+}
+
+// Intersection returns the box common to o and q
+//
+// Parameters:
+//
+//	o *AABB - The first box
+//	q AABB - The second box
+//
+// Returns:
+//
+//	box AABB - The intersection box, possibly flat if the boxes only touch
+//	err error - ErrNoIntersection if the boxes are disjoint
+func (o *AABB) Intersection(q AABB) (box AABB, err error) {
+	if o.Min.N != q.Min.N {
+		panic(errors.ErrShape)
+	}
+	if !o.Overlaps(q) {
+		err = errors.ErrNoIntersection
+		return
+	}
+	box.Min = rn.MakeVec(o.Min.N, 0)
+	box.Max = rn.MakeVec(o.Min.N, 0)
+	for i := 0; i < o.Min.N; i++ {
+		box.Min.X[i] = math.Max(o.Min.X[i], q.Min.X[i])
+		box.Max.X[i] = math.Min(o.Max.X[i], q.Max.X[i])
+	}
+	return
+}
+
+// Overlaps returns true if o and q share at least one point
+//
+// Parameters:
+//
+//	o *AABB - The first box
+//	q AABB - The second box
+//
+// Returns:
+//
+//	bool - true if the boxes overlap or touch
+func (o *AABB) Overlaps(q AABB) bool {
+	if o.Min.N != q.Min.N {
+		panic(errors.ErrShape)
+	}
+	for i := 0; i < o.Min.N; i++ {
+		if o.Max.X[i] < q.Min.X[i] || q.Max.X[i] < o.Min.X[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns true if P lies inside or on the box
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if Min <= P <= Max componentwise
+func (o *AABB) Contains(P rn.Vec) bool {
+	if P.N != o.Min.N {
+		panic(errors.ErrShape)
+	}
+	for i := 0; i < P.N; i++ {
+		if P.X[i] < o.Min.X[i] || o.Max.X[i] < P.X[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAABB returns true if q lies entirely inside o
+//
+// Parameters:
+//
+//	q AABB - The box to test
+//
+// Returns:
+//
+//	bool - true if both corners of q lie in o
+func (o *AABB) ContainsAABB(q AABB) bool {
+	return o.Contains(q.Min) && o.Contains(q.Max)
+}
+
+// ClosestPoint returns the point of the box closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	P1 rn.Vec - P clamped to the box
+func (o *AABB) ClosestPoint(P rn.Vec) (P1 rn.Vec) {
+	if P.N != o.Min.N {
+		panic(errors.ErrShape)
+	}
+	P1 = rn.MakeVec(P.N, 0)
+	for i := 0; i < P.N; i++ {
+		P1.X[i] = clamp(P.X[i], o.Min.X[i], o.Max.X[i])
+	}
+	return
+}
+
+// Dist returns the distance between P and the box
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance, 0 if P lies inside
+func (o *AABB) Dist(P rn.Vec) (dist float64) {
+	P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
+
+// slab clips p + t*d, t in [tMin, tMax], against the box and returns the remaining parameter interval
+func (o *AABB) slab(p, d rn.Vec, tMin, tMax float64) (x, P1 rn.Vec, err error) {
+	if p.N != o.Min.N || d.N != o.Min.N {
+		panic(errors.ErrShape)
+	}
+	for i := 0; i < p.N; i++ {
+		if d.X[i] == 0 {
+			if p.X[i] < o.Min.X[i] || o.Max.X[i] < p.X[i] {
+				err = errors.ErrNoIntersection
+				return
+			}
+			continue
+		}
+		inv := 1 / d.X[i]
+		t1, t2 := (o.Min.X[i]-p.X[i])*inv, (o.Max.X[i]-p.X[i])*inv
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin, tMax = math.Max(tMin, t1), math.Min(tMax, t2)
+		if tMin > tMax {
+			err = errors.ErrNoIntersection
+			return
+		}
+	}
+	x = rn.Vec{N: 2, X: []float64{tMin, tMax}}
+	P1 = p.Add(d.Scale(tMin))
+	return
+}
+
+// IntersectRay returns where a ray enters and leaves the box (slab test)
+//
+// Parameters:
+//
+//	o *AABB - The box
+//	q Ray - The ray
+//
+// Returns:
+//
+//	x rn.Vec - The ray parameters at which the ray enters and leaves the box,
+//	the entry parameter is 0 if the ray starts inside
+//	P1 rn.Vec - The entry point
+//	err error - ErrNoIntersection if the ray misses the box
+func (o *AABB) IntersectRay(q Ray) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.slab(q.V1, q.V2, 0, math.Inf(1))
+	return
+}
+
+// IntersectLine returns where a line enters and leaves the box (slab test)
+//
+// Parameters:
+//
+//	o *AABB - The box
+//	q Line - The line
+//
+// Returns:
+//
+//	x rn.Vec - The line parameters at which the line enters and leaves the box
+//	P1 rn.Vec - The entry point
+//	err error - ErrNoIntersection if the line misses the box
+func (o *AABB) IntersectLine(q Line) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.slab(q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectSegment returns where a segment enters and leaves the box (slab test)
+//
+// Parameters:
+//
+//	o *AABB - The box
+//	q Segment - The segment
+//
+// Returns:
+//
+//	x rn.Vec - The segment parameters in [0, 1] of the part inside the box
+//	P1 rn.Vec - The entry point
+//	err error - ErrNoIntersection if the segment misses the box
+func (o *AABB) IntersectSegment(q Segment) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.slab(q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// ClassifyPlane returns on which side of a plane the box lies
+//
+// Parameters:
+//
+//	plane Plane - The plane
+//
+// Returns:
+//
+//	side Side - Front or Back if the box lies entirely on one side, Straddle otherwise
+func (o *AABB) ClassifyPlane(plane Plane) (side Side) {
+	n := plane.Normal()
+	C, E := o.Center(), o.Extents()
+	var r float64
+	for i := 0; i < n.N; i++ {
+		r += E.X[i] * math.Abs(n.X[i])
+	}
+	side = classifyRadius(plane.SignedDist(C), r)
+	return
+}
+
+// classifyRadius classifies a shape whose center lies at signed distance d from a plane
+// and whose projection onto the plane normal has radius r
+func classifyRadius(d, r float64) Side {
+	switch {
+	case d > r:
+		return Front
+	case d < -r:
+		return Back
+	}
+	return Straddle
+}
@@ -0,0 +1,77 @@
+package gm
+
+import (
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func TestAABB(t *testing.T) {
+	box := MakeAABBFromPoints([]rn.Vec{v3(1, 0, 2), v3(-1, 3, 0), v3(0, 1, 1)})
+	if want := MakeAABB(v3(-1, 0, 0), v3(1, 3, 2)); !box.Equal(want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", box, want)
+	}
+	if got := box.Volume(); got != 12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 12)
+	}
+	if got := box.SurfaceArea(); got != 2*(6+4+6) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 32)
+	}
+	if !box.Contains(v3(0, 3, 1)) || box.Contains(v3(0, 3.1, 1)) {
+		t.Errorf("error:\nContains")
+	}
+
+	other := MakeAABB(v3(0, 2, 1), v3(5, 5, 5))
+	union := box.Union(other)
+	if want := MakeAABB(v3(-1, 0, 0), v3(5, 5, 5)); !union.Equal(want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", union, want)
+	}
+	inter, err := box.Intersection(other)
+	if want := MakeAABB(v3(0, 2, 1), v3(1, 3, 2)); err != nil || !inter.Equal(want) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", inter, err, want)
+	}
+	if _, err := box.Intersection(MakeAABB(v3(2, 0, 0), v3(3, 1, 1))); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+	if got := box.Dist(v3(4, 7, 1)); got != 5 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 5)
+	}
+}
+
+func TestAABBIntersectRay(t *testing.T) {
+	box := MakeAABB(v3(0, 0, 0), v3(1, 1, 1))
+	for _, test := range []struct {
+		ray  Ray
+		want rn.Vec
+		err  bool
+	}{
+		{MakeRay(v3(-1, 0.5, 0.5), v3(1, 0, 0)), rn.Vec{N: 2, X: []float64{1, 2}}, false},
+		{MakeRay(v3(0.5, 0.5, 0.5), v3(0, 0, 1)), rn.Vec{N: 2, X: []float64{0, 0.5}}, false},
+		{MakeRay(v3(-1, -1, -1), v3(1, 1, 1)), rn.Vec{N: 2, X: []float64{1, 2}}, false},
+		{MakeRay(v3(2, 0.5, 0.5), v3(1, 0, 0)), rn.Vec{}, true},
+		{MakeRay(v3(-1, 2, 0.5), v3(1, 0, 0)), rn.Vec{}, true},
+	} {
+		x, _, err := box.IntersectRay(test.ray)
+		if test.err != (err != nil) || !test.err && !x.Equal(test.want) {
+			t.Errorf("error:\n%v:\ngot=%v, %v\nwant=%v", test.ray, x, err, test.want)
+		}
+	}
+}
+
+func TestAABBClassifyPlane(t *testing.T) {
+	box := MakeAABB(v3(0, 0, 0), v3(1, 1, 1))
+	for _, test := range []struct {
+		plane Plane
+		want  Side
+	}{
+		{MakePlane(v3(0, 0, 2), v3(1, 0, 0), v3(0, 1, 0)), Back},
+		{MakePlane(v3(0, 0, -1), v3(1, 0, 0), v3(0, 1, 0)), Front},
+		{MakePlane(v3(0, 0, 0.5), v3(1, 0, 0), v3(0, 1, 0)), Straddle},
+		{MakePlaneByPoints(v3(2, 0, 0), v3(0, 2, 0), v3(0, 0, 2)), Straddle},
+		{MakePlaneByPoints(v3(4, 0, 0), v3(0, 4, 0), v3(0, 0, 4)), Back},
+	} {
+		if got := box.ClassifyPlane(test.plane); got != test.want {
+			t.Errorf("error:\n%v:\ngot=%v\nwant=%v", test.plane, got, test.want)
+		}
+	}
+}
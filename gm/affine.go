@@ -0,0 +1,365 @@
+package gm
+
+import (
+	"fmt"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Affine is the affine subspace P + span(D[0], ..., D[k-1]) of Rⁿ
+//
+// The directions need not be independent or orthogonal; a point has no
+// directions. Line and Plane are the affine subspaces with one and two
+// directions.
+type Affine struct {
+	P rn.Vec
+	D []rn.Vec
+}
+
+func (o Affine) String() (str string) {
+	str += fmt.Sprintf("x = %v", o.P)
+	for _, d := range o.D {
+		str += fmt.Sprintf(" + λ * %v", d)
+	}
+	return
+}
+
+// MakeAffine returns the affine subspace through P spanned by the directions D
+//
+// Parameters:
+//
+//	P rn.Vec - The point
+//	D ...rn.Vec - The directions, all of the dimension of P
+//
+// Returns:
+//
+//	aff Affine - The affine subspace
+func MakeAffine(P rn.Vec, D ...rn.Vec) (aff Affine) {
+	checkDims(D, P.N)
+	aff.P = P
+	aff.D = D
+	return
+}
+
+// MakeAffineByPoints returns the smallest affine subspace that contains the given points
+//
+// Parameters:
+//
+//	Ps ...rn.Vec - The points, at least one
+//
+// Returns:
+//
+//	aff Affine - The subspace through Ps[0] spanned by Ps[i] - Ps[0]
+func MakeAffineByPoints(Ps ...rn.Vec) (aff Affine) {
+	if len(Ps) == 0 {
+		panic(errors.ErrZeroLengthVec)
+	}
+	D := make([]rn.Vec, len(Ps)-1)
+	for i := range D {
+		D[i] = Ps[i+1].Sub(Ps[0])
+	}
+	aff = MakeAffine(Ps[0], D...)
+	return
+}
+
+// Affine returns the line as an affine subspace
+//
+// Parameters:
+//
+//	o *Line - The line
+//
+// Returns:
+//
+//	aff Affine - The subspace through V1 spanned by V2
+func (o *Line) Affine() (aff Affine) {
+	aff = MakeAffine(o.V1, o.V2)
+	return
+}
+
+// Affine returns the plane as an affine subspace
+//
+// Parameters:
+//
+//	o *Plane - The plane
+//
+// Returns:
+//
+//	aff Affine - The subspace through V1 spanned by V2 and V3
+func (o *Plane) Affine() (aff Affine) {
+	aff = MakeAffine(o.V1, o.V2, o.V3)
+	return
+}
+
+// Line returns a one-dimensional affine subspace as a line
+//
+// Parameters:
+//
+//	o *Affine - The subspace
+//
+// Returns:
+//
+//	line Line - The line through P along the unit direction of the subspace
+//	err error - ErrDegenerate if the subspace is not one-dimensional
+func (o *Affine) Line() (line Line, err error) {
+	basis := o.Basis()
+	if len(basis) != 1 {
+		err = errors.ErrDegenerate
+		return
+	}
+	line = MakeLine(o.P, basis[0])
+	return
+}
+
+// Plane returns a two-dimensional affine subspace as a plane
+//
+// Parameters:
+//
+//	o *Affine - The subspace
+//
+// Returns:
+//
+//	plane Plane - The plane through P along orthonormal directions of the subspace
+//	err error - ErrDegenerate if the subspace is not two-dimensional
+func (o *Affine) Plane() (plane Plane, err error) {
+	basis := o.Basis()
+	if len(basis) != 2 {
+		err = errors.ErrDegenerate
+		return
+	}
+	plane = MakePlane(o.P, basis[0], basis[1])
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Affine - subspace to compare to q
+//	q Affine - subspace to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q have the same point and directions
+func (o *Affine) Equal(q Affine) bool {
+	if !o.P.Equal(q.P) || len(o.D) != len(q.D) {
+		return false
+	}
+	for i := range o.D {
+		if !o.D[i].Equal(q.D[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Basis returns an orthonormal basis of the directions of the subspace
+//
+// Parameters:
+//
+//	o *Affine - The subspace
+//
+// Returns:
+//
+//	basis []rn.Vec - The principal directions, directions within a relative tolerance of
+//	the span of the others do not count
+func (o *Affine) Basis() (basis []rn.Vec) {
+	if len(o.D) == 0 {
+		return
+	}
+	A := matFromCols(o.D, o.P.N)
+	U, S, _ := A.SVD()
+	for i := 0; i < numericalRank(S); i++ {
+		basis = append(basis, U.GetCol(i))
+	}
+	return
+}
+
+// Dim returns the dimension of the subspace
+//
+// Parameters:
+//
+//	o *Affine - The subspace
+//
+// Returns:
+//
+//	k int - The number of independent directions
+func (o *Affine) Dim() (k int) {
+	k = len(o.Basis())
+	return
+}
+
+// Project returns the point of the subspace closest to X
+//
+// Parameters:
+//
+//	X rn.Vec - The query point
+//
+// Returns:
+//
+//	x rn.Vec - The coefficients of the directions at the closest point, the shortest
+//	such vector if the directions are dependent
+//	P1 rn.Vec - The closest point
+func (o *Affine) Project(X rn.Vec) (x, P1 rn.Vec) {
+	sol := affineSolve(*o, MakeAffine(X))
+	x = rn.Vec{N: len(o.D), X: sol.a}
+	P1 = sol.P1
+	return
+}
+
+// Dist returns the distance between X and the subspace
+//
+// Parameters:
+//
+//	X rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance from X to its projection
+func (o *Affine) Dist(X rn.Vec) (dist float64) {
+	_, P1 := o.Project(X)
+	dist = P1.Dist(X)
+	return
+}
+
+// Contains returns true if X lies in the subspace
+//
+// Parameters:
+//
+//	X rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if the distance is within a relative tolerance
+func (o *Affine) Contains(X rn.Vec) bool {
+	return o.Dist(X) <= tolerance(append([]rn.Vec{o.P, X}, o.D...)...)
+}
+
+// DistAffine returns the distance between two subspaces
+//
+// Parameters:
+//
+//	o *Affine - The first subspace
+//	q Affine - The second subspace
+//
+// Returns:
+//
+//	dist float64 - The smallest distance between a point of o and a point of q
+func (o *Affine) DistAffine(q Affine) (dist float64) {
+	sol := affineSolve(*o, q)
+	dist = sol.P1.Dist(sol.P2)
+	return
+}
+
+// Intersect returns the intersection of two subspaces (if it exists)
+//
+// Parameters:
+//
+//	o *Affine - The first subspace
+//	q Affine - The second subspace
+//
+// Returns:
+//
+//	aff Affine - The intersection, with an orthonormal basis of its directions
+//	err error - ErrNoIntersection if the subspaces are disjoint
+func (o *Affine) Intersect(q Affine) (aff Affine, err error) {
+	sol := affineSolve(*o, q)
+	if sol.P1.Dist(sol.P2) > tolerance(append(append([]rn.Vec{o.P, q.P}, o.D...), q.D...)...) {
+		err = errors.ErrNoIntersection
+		return
+	}
+	// every unit null vector (a, b) of [A, -B] gives a common direction A * a,
+	// which vanishes up to rounding if a only combines dependent directions of o
+	var scale float64
+	for _, D1 := range o.D {
+		scale += D1.Norm()
+	}
+	var D []rn.Vec
+	for _, z := range sol.null {
+		d := rn.MakeVec(o.P.N, 0)
+		for i, D1 := range o.D {
+			d = d.Add(D1.Scale(z[i]))
+		}
+		if d.Norm() > eps*scale {
+			D = append(D, d)
+		}
+	}
+	aff = MakeAffine(sol.P1, D...)
+	aff.D = aff.Basis()
+	return
+}
+
+// affineSolution is the least-squares solution of o.P + A * a = q.P + B * b
+type affineSolution struct {
+	a, b   []float64   // the shortest coefficients
+	P1, P2 rn.Vec      // o.P + A * a and q.P + B * b
+	null   [][]float64 // a basis of the null space of [A, -B]
+}
+
+// affineSolve returns the closest points of two subspaces using the SVD of [A, -B]
+func affineSolve(o, q Affine) (sol affineSolution) {
+	if q.P.N != o.P.N {
+		panic(errors.ErrShape)
+	}
+	n, k, l := o.P.N, len(o.D), len(q.D)
+	sol.a, sol.b = make([]float64, k), make([]float64, l)
+	sol.P1, sol.P2 = o.P, q.P
+	if k+l == 0 {
+		return
+	}
+	// zero rows make the SVD return the full null space
+	rows := n
+	if rows < k+l {
+		rows = k + l
+	}
+	cols := make([]rn.Vec, 0, k+l)
+	cols = append(cols, o.D...)
+	for _, d := range q.D {
+		cols = append(cols, d.Scale(-1))
+	}
+	M := matFromCols(cols, rows)
+	r := rn.MakeVec(rows, 0)
+	copy(r.X, q.P.X)
+	for i := range r.X[:n] {
+		r.X[i] -= o.P.X[i]
+	}
+	U, S, V := M.SVD()
+	rank := numericalRank(S)
+	z := rn.MakeVec(k+l, 0)
+	for i := 0; i < rank; i++ {
+		u, v := U.GetCol(i), V.GetCol(i)
+		z = z.Add(v.Scale(u.Dot(r) / S.X[i]))
+	}
+	copy(sol.a, z.X[:k])
+	copy(sol.b, z.X[k:])
+	for i, d := range o.D {
+		sol.P1 = sol.P1.Add(d.Scale(sol.a[i]))
+	}
+	for j, d := range q.D {
+		sol.P2 = sol.P2.Add(d.Scale(sol.b[j]))
+	}
+	for i := rank; i < k+l; i++ {
+		v := V.GetCol(i)
+		sol.null = append(sol.null, v.X)
+	}
+	return
+}
+
+// matFromCols returns the rows x len(cols) matrix with the given columns, padded with zero rows
+func matFromCols(cols []rn.Vec, rows int) (M rn.Mat) {
+	M = rn.MakeMat(rows, len(cols), 0)
+	for j, c := range cols {
+		copy(M.Data[j*rows:], c.X)
+	}
+	return
+}
+
+// numericalRank returns the number of singular values above a relative tolerance
+func numericalRank(S rn.Vec) (rank int) {
+	for _, s := range S.X {
+		if s > eps*S.X[0] {
+			rank++
+		}
+	}
+	return
+}
@@ -0,0 +1,127 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+func v4(x, y, z, w float64) rn.Vec {
+	return rn.Vec{N: 4, X: []float64{x, y, z, w}}
+}
+
+func TestAffine(t *testing.T) {
+	// the xy-plane of R⁴ shifted by w = 1, given with a redundant direction
+	xy := MakeAffine(v4(0, 0, 0, 1), v4(1, 0, 0, 0), v4(0, 2, 0, 0), v4(1, 1, 0, 0))
+	if got := xy.Dim(); got != 2 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 2)
+	}
+	x, P1 := xy.Project(v4(3, 4, 5, 6))
+	if !vecClose(P1, v4(3, 4, 0, 1), 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", P1, v4(3, 4, 0, 1))
+	}
+	P2 := xy.P
+	for i, d := range xy.D {
+		P2 = P2.Add(d.Scale(x.X[i]))
+	}
+	if !vecClose(P2, P1, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", P2, P1)
+	}
+	if got := xy.Dist(v4(3, 4, 5, 6)); math.Abs(got-math.Sqrt(50)) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, math.Sqrt(50))
+	}
+	if !xy.Contains(v4(-7, 2, 0, 1)) || xy.Contains(v4(0, 0, 0, 0)) {
+		t.Errorf("error:\ngot=%v\nwant=%v", false, true)
+	}
+
+	tests := []struct {
+		q    Affine
+		dim  int
+		P    rn.Vec
+		dist float64
+		err  error
+	}{
+		// the zw-plane through the origin meets xy in (0, 0, 0, 1)
+		{MakeAffine(v4(0, 0, 0, 0), v4(0, 0, 1, 0), v4(0, 0, 0, 1)), 0, v4(0, 0, 0, 1), 0, nil},
+		// the xz-plane at w = 1 shares the x-axis
+		{MakeAffine(v4(5, 0, 0, 1), v4(1, 0, 0, 0), v4(0, 0, 1, 0)), 1, rn.Vec{}, 0, nil},
+		// a parallel copy at w = 4
+		{MakeAffine(v4(0, 0, 0, 4), v4(0, 1, 0, 0), v4(1, 0, 0, 0)), 2, rn.Vec{}, 3, errors.ErrNoIntersection},
+		// a line that passes the plane at distance 2
+		{MakeAffineByPoints(v4(0, 0, 2, 1), v4(0, 0, 2, 5)), 0, rn.Vec{}, 2, errors.ErrNoIntersection},
+	}
+	for _, test := range tests {
+		if got := xy.DistAffine(test.q); math.Abs(got-test.dist) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.dist)
+		}
+		aff, err := xy.Intersect(test.q)
+		if err != test.err {
+			t.Errorf("error:\ngot=%v\nwant=%v", err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := aff.Dim(); got != test.dim || len(aff.D) != test.dim {
+			t.Errorf("error:\ngot=%v\nwant=%v", aff, test.dim)
+		}
+		if test.P.N > 0 && !vecClose(aff.P, test.P, 1e-12) {
+			t.Errorf("error:\ngot=%v\nwant=%v", aff.P, test.P)
+		}
+		// the intersection lies in both subspaces
+		Xs := []rn.Vec{aff.P}
+		for _, d := range aff.D {
+			Xs = append(Xs, aff.P.Add(d))
+		}
+		for _, X := range Xs {
+			if !xy.Contains(X) || !test.q.Contains(X) {
+				t.Errorf("error:\n%v is not in both subspaces", X)
+			}
+		}
+	}
+}
+
+func TestLineNDim(t *testing.T) {
+	// lines and planes also intersect in dimensions other than 3
+	a := MakeLine(v2(0, 0), v2(1, 1))
+	b := MakeLine(v2(2, 0), v2(0, 1))
+	if x, P1, err := a.IntersectLine(b); err != nil || !vecClose(P1, v2(2, 2), 1e-12) || !vecClose(x, v2(2, 2), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v, %v\nwant=%v", x, P1, err, v2(2, 2))
+	}
+	if _, _, err := a.IntersectLine(MakeLine(v2(1, 0), v2(-2, -2))); err != errors.ErrParallel {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrParallel)
+	}
+
+	plane := MakePlane(v4(0, 0, 0, 1), v4(1, 0, 0, 0), v4(0, 1, 0, 0))
+	tests := []struct {
+		line Line
+		x    rn.Vec
+		err  error
+	}{
+		{MakeLine(v4(1, 2, 3, 1), v4(0, 0, 1, 0)), v3(-3, 1, 2), nil},
+		{MakeLine(v4(1, 2, 3, 2), v4(0, 0, 1, 0)), rn.Vec{}, errors.ErrNoIntersection},
+		{MakeLine(v4(1, 2, 3, 2), v4(1, 1, 0, 0)), rn.Vec{}, errors.ErrParallel},
+	}
+	for _, test := range tests {
+		x, _, err := test.line.IntersectPlane(plane)
+		if err != test.err || err == nil && !vecClose(x, test.x, 1e-12) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", x, err, test.x, test.err)
+		}
+	}
+	// nearly parallel is not parallel
+	steep := MakeLine(v4(1, 2, 3, 1), v4(1, 0, 1e-10, 0))
+	if x, _, err := steep.IntersectPlane(plane); err != nil || !vecClose(x, v3(-3e10, 1-3e10, 2), 1e-4) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", x, err, v3(-3e10, 1-3e10, 2))
+	}
+
+	aff := plane.Affine()
+	back, err := aff.Plane()
+	if backAff := back.Affine(); err != nil || !backAff.Contains(v4(5, -3, 0, 1)) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", back, err, plane)
+	}
+	if _, err := aff.Line(); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
@@ -0,0 +1,453 @@
+package gm
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Primitive is a geometric object that can be stored in a BVH
+//
+// *Triangle, *AABB and *OBB implement Primitive.
+type Primitive interface {
+	AABB() AABB
+	IntersectRay(q Ray) (x, P1 rn.Vec, err error)
+	ClosestPoint(P rn.Vec) (P1 rn.Vec)
+}
+
+// Hit describes an intersection of a ray with a primitive of a BVH
+type Hit struct {
+	Idx   int     // index of the primitive in BVH.Prims
+	T     float64 // ray parameter of the intersection point
+	X, P1 rn.Vec  // the values returned by the primitive's IntersectRay
+}
+
+// BVH is a bounding volume hierarchy over a set of primitives
+type BVH struct {
+	Prims []Primitive
+	root  *bvhNode
+}
+
+type bvhNode struct {
+	box         AABB
+	left, right *bvhNode
+	idx         []int // primitive indices, leaves only
+}
+
+const (
+	bvhBins     = 16   // number of SAH bins per axis
+	bvhLeafSize = 4    // maximum number of primitives in a leaf
+	bvhParallel = 2048 // minimum subtree size that is built in its own goroutine
+)
+
+// MakeBVH builds a bounding volume hierarchy over prims using the surface area heuristic
+//
+// Parameters:
+//
+//	prims []Primitive - The primitives, all of the same dimension
+//
+// Returns:
+//
+//	bvh BVH - The hierarchy
+func MakeBVH(prims []Primitive) (bvh BVH) {
+	bvh = makeBVH(prims, false)
+	return
+}
+
+// MakeBVHConcurrent is like MakeBVH but builds large subtrees in parallel goroutines
+//
+// Parameters:
+//
+//	prims []Primitive - The primitives, all of the same dimension
+//
+// Returns:
+//
+//	bvh BVH - The hierarchy, identical to the one MakeBVH returns
+func MakeBVHConcurrent(prims []Primitive) (bvh BVH) {
+	bvh = makeBVH(prims, true)
+	return
+}
+
+type bvhBuilder struct {
+	boxes      []AABB
+	centroids  []rn.Vec
+	concurrent bool
+	wg         sync.WaitGroup
+}
+
+func makeBVH(prims []Primitive, concurrent bool) (bvh BVH) {
+	bvh.Prims = prims
+	if len(prims) == 0 {
+		return
+	}
+	b := &bvhBuilder{
+		boxes:      make([]AABB, len(prims)),
+		centroids:  make([]rn.Vec, len(prims)),
+		concurrent: concurrent,
+	}
+	idx := make([]int, len(prims))
+	for i, prim := range prims {
+		b.boxes[i] = prim.AABB()
+		b.centroids[i] = b.boxes[i].Center()
+		idx[i] = i
+	}
+	bvh.root = &bvhNode{}
+	b.build(bvh.root, idx)
+	b.wg.Wait()
+	return
+}
+
+// build fills node with the subtree over idx; idx is partitioned in place
+func (b *bvhBuilder) build(node *bvhNode, idx []int) {
+	node.box = b.boxes[idx[0]].AABB()
+	centroids := MakeAABB(b.centroids[idx[0]], b.centroids[idx[0]])
+	for _, i := range idx[1:] {
+		node.box.Extend(b.boxes[i].Min)
+		node.box.Extend(b.boxes[i].Max)
+		centroids.Extend(b.centroids[i])
+	}
+	if len(idx) <= bvhLeafSize {
+		node.idx = idx
+		return
+	}
+	axis, split, ok := b.split(node.box, centroids, idx)
+	if !ok {
+		node.idx = idx
+		return
+	}
+	lo, extent := centroids.Min.X[axis], centroids.Max.X[axis]-centroids.Min.X[axis]
+	mid := 0
+	for k, i := range idx {
+		if binOf(b.centroids[i].X[axis], lo, extent) < split {
+			idx[k], idx[mid] = idx[mid], idx[k]
+			mid++
+		}
+	}
+	if mid == 0 || mid == len(idx) {
+		mid = len(idx) / 2
+	}
+	node.left, node.right = &bvhNode{}, &bvhNode{}
+	if b.concurrent && len(idx) >= bvhParallel {
+		b.wg.Add(1)
+		go func(left []int) {
+			defer b.wg.Done()
+			b.build(node.left, left)
+		}(idx[:mid])
+	} else {
+		b.build(node.left, idx[:mid])
+	}
+	b.build(node.right, idx[mid:])
+}
+
+func binOf(c, lo, extent float64) int {
+	bin := int(bvhBins * (c - lo) / extent)
+	if bin >= bvhBins {
+		bin = bvhBins - 1
+	}
+	return bin
+}
+
+// split returns the axis and bin boundary of the cheapest binned SAH split,
+// ok is false if keeping idx as a leaf is cheaper
+func (b *bvhBuilder) split(box, centroids AABB, idx []int) (axis, split int, ok bool) {
+	bestCost := float64(len(idx))
+	if len(idx) > 4*bvhLeafSize {
+		// large nodes are always split, even if the heuristic disagrees
+		bestCost = math.Inf(1)
+	}
+	area := box.SurfaceArea()
+	for a := 0; a < centroids.Min.N; a++ {
+		lo, extent := centroids.Min.X[a], centroids.Max.X[a]-centroids.Min.X[a]
+		if extent <= 0 {
+			continue
+		}
+		var counts [bvhBins]int
+		var bins [bvhBins]*AABB
+		for _, i := range idx {
+			k := binOf(b.centroids[i].X[a], lo, extent)
+			counts[k]++
+			if bins[k] == nil {
+				bin := b.boxes[i].AABB()
+				bins[k] = &bin
+			} else {
+				bins[k].Extend(b.boxes[i].Min)
+				bins[k].Extend(b.boxes[i].Max)
+			}
+		}
+		// rightCost[k] is the cost of the bins k..bvhBins-1
+		var rightCost [bvhBins]float64
+		var acc *AABB
+		n := 0
+		for k := bvhBins - 1; k > 0; k-- {
+			acc, n = growBin(acc, bins[k]), n+counts[k]
+			if acc != nil {
+				rightCost[k] = acc.SurfaceArea() * float64(n)
+			}
+		}
+		acc, n = nil, 0
+		for k := 1; k < bvhBins; k++ {
+			acc, n = growBin(acc, bins[k-1]), n+counts[k-1]
+			if n == 0 || n == len(idx) {
+				continue
+			}
+			cost := 1 + (acc.SurfaceArea()*float64(n)+rightCost[k])/area
+			if cost < bestCost {
+				bestCost, axis, split, ok = cost, a, k, true
+			}
+		}
+	}
+	return
+}
+
+func growBin(acc, bin *AABB) *AABB {
+	if bin == nil {
+		return acc
+	}
+	if acc == nil {
+		box := bin.AABB()
+		return &box
+	}
+	box := acc.Union(*bin)
+	return &box
+}
+
+// rayEntry returns the parameter at which p + t*d enters the box for t in [0, tMax]
+func (o *AABB) rayEntry(p, invD rn.Vec, tMax float64) (tMin float64, ok bool) {
+	for i := 0; i < p.N; i++ {
+		t1, t2 := (o.Min.X[i]-p.X[i])*invD.X[i], (o.Max.X[i]-p.X[i])*invD.X[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		// NaN from 0 * Inf means the ray lies in the slab boundary; keep the bounds
+		if t1 > tMin {
+			tMin = t1
+		}
+		if t2 < tMax {
+			tMax = t2
+		}
+		if tMin > tMax {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+func invDir(d rn.Vec) (inv rn.Vec) {
+	inv = rn.MakeVec(d.N, 0)
+	for i, x := range d.X {
+		inv.X[i] = 1 / x
+	}
+	return
+}
+
+// IntersectRay returns the first primitive hit by a ray
+//
+// Parameters:
+//
+//	o *BVH - The hierarchy
+//	q Ray - The ray
+//
+// Returns:
+//
+//	hit Hit - The hit with the smallest ray parameter
+//	err error - ErrNoIntersection if the ray misses every primitive
+func (o *BVH) IntersectRay(q Ray) (hit Hit, err error) {
+	hit.Idx = -1
+	if o.root == nil {
+		err = errors.ErrNoIntersection
+		return
+	}
+	invD := invDir(q.V2)
+	best := math.Inf(1)
+	stack := []*bvhNode{o.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := node.box.rayEntry(q.V1, invD, best); !ok {
+			continue
+		}
+		if node.idx == nil {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+		for _, i := range node.idx {
+			x, P1, err := o.Prims[i].IntersectRay(q)
+			if err != nil || x.X[0] >= best {
+				continue
+			}
+			best = x.X[0]
+			hit = Hit{Idx: i, T: best, X: x, P1: P1}
+		}
+	}
+	if hit.Idx < 0 {
+		err = errors.ErrNoIntersection
+	}
+	return
+}
+
+// IntersectRayAll returns every primitive hit by a ray
+//
+// Parameters:
+//
+//	o *BVH - The hierarchy
+//	q Ray - The ray
+//
+// Returns:
+//
+//	hits []Hit - All hits, sorted by ray parameter
+func (o *BVH) IntersectRayAll(q Ray) (hits []Hit) {
+	if o.root == nil {
+		return
+	}
+	invD := invDir(q.V2)
+	stack := []*bvhNode{o.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := node.box.rayEntry(q.V1, invD, math.Inf(1)); !ok {
+			continue
+		}
+		if node.idx == nil {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+		for _, i := range node.idx {
+			if x, P1, err := o.Prims[i].IntersectRay(q); err == nil {
+				hits = append(hits, Hit{Idx: i, T: x.X[0], X: x, P1: P1})
+			}
+		}
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].T != hits[b].T {
+			return hits[a].T < hits[b].T
+		}
+		return hits[a].Idx < hits[b].Idx
+	})
+	return
+}
+
+// ClosestPoint returns the point on any primitive closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	idx int - The index of the closest primitive, -1 if the hierarchy is empty
+//	P1 rn.Vec - The closest point
+//	dist float64 - The distance between P and P1
+func (o *BVH) ClosestPoint(P rn.Vec) (idx int, P1 rn.Vec, dist float64) {
+	idx, dist = -1, math.Inf(1)
+	if o.root == nil {
+		return
+	}
+	stack := []*bvhNode{o.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node.box.Dist(P) >= dist {
+			continue
+		}
+		if node.idx == nil {
+			// visit the nearer child first so that it tightens the bound early
+			near, far := node.left, node.right
+			if near.box.Dist(P) > far.box.Dist(P) {
+				near, far = far, near
+			}
+			stack = append(stack, far, near)
+			continue
+		}
+		for _, i := range node.idx {
+			Q := o.Prims[i].ClosestPoint(P)
+			if d := Q.Dist(P); d < dist {
+				idx, P1, dist = i, Q, d
+			}
+		}
+	}
+	return
+}
+
+// Overlap returns the primitives whose bounding boxes overlap a box
+//
+// Parameters:
+//
+//	box AABB - The query box
+//
+// Returns:
+//
+//	idx []int - The indices of the overlapping primitives in ascending order
+func (o *BVH) Overlap(box AABB) (idx []int) {
+	if o.root == nil {
+		return
+	}
+	stack := []*bvhNode{o.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !node.box.Overlaps(box) {
+			continue
+		}
+		if node.idx == nil {
+			stack = append(stack, node.left, node.right)
+			continue
+		}
+		for _, i := range node.idx {
+			if primBox := o.Prims[i].AABB(); primBox.Overlaps(box) {
+				idx = append(idx, i)
+			}
+		}
+	}
+	sort.Ints(idx)
+	return
+}
+
+// OverlapBVH returns the pairs of primitives of two hierarchies whose bounding boxes overlap
+//
+// Parameters:
+//
+//	q *BVH - The second hierarchy, may be o itself
+//
+// Returns:
+//
+//	pairs [][2]int - Index pairs into o.Prims and q.Prims, sorted lexicographically
+func (o *BVH) OverlapBVH(q *BVH) (pairs [][2]int) {
+	if o.root == nil || q.root == nil {
+		return
+	}
+	type job struct{ a, b *bvhNode }
+	stack := []job{{o.root, q.root}}
+	for len(stack) > 0 {
+		j := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !j.a.box.Overlaps(j.b.box) {
+			continue
+		}
+		switch aLeaf, bLeaf := j.a.idx != nil, j.b.idx != nil; {
+		case aLeaf && bLeaf:
+			for _, i := range j.a.idx {
+				boxI := o.Prims[i].AABB()
+				for _, k := range j.b.idx {
+					if boxI.Overlaps(q.Prims[k].AABB()) {
+						pairs = append(pairs, [2]int{i, k})
+					}
+				}
+			}
+		case aLeaf || !bLeaf && j.b.box.SurfaceArea() > j.a.box.SurfaceArea():
+			stack = append(stack, job{j.a, j.b.left}, job{j.a, j.b.right})
+		default:
+			stack = append(stack, job{j.a.left, j.b}, job{j.a.right, j.b})
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a][0] != pairs[b][0] {
+			return pairs[a][0] < pairs[b][0]
+		}
+		return pairs[a][1] < pairs[b][1]
+	})
+	return
+}
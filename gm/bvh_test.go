@@ -0,0 +1,139 @@
+package gm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func randomTriangles(rng *rand.Rand, n int) (prims []Primitive) {
+	for k := 0; k < n; k++ {
+		c := v3(rng.Float64()*20-10, rng.Float64()*20-10, rng.Float64()*20-10)
+		jitter := func() rn.Vec {
+			return c.Add(v3(rng.Float64()-0.5, rng.Float64()-0.5, rng.Float64()-0.5))
+		}
+		tri := MakeTriangle(jitter(), jitter(), jitter())
+		prims = append(prims, &tri)
+	}
+	return
+}
+
+func randomRay(rng *rand.Rand) Ray {
+	return MakeRay(
+		v3(rng.Float64()*30-15, rng.Float64()*30-15, rng.Float64()*30-15),
+		v3(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()),
+	)
+}
+
+func TestBVHIntersectRay(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	prims := randomTriangles(rng, 3000)
+	for _, bvh := range []BVH{MakeBVH(prims), MakeBVHConcurrent(prims)} {
+		for k := 0; k < 300; k++ {
+			ray := randomRay(rng)
+			want, wantHits := -1, 0
+			best := math.Inf(1)
+			for i, prim := range prims {
+				if x, _, err := prim.IntersectRay(ray); err == nil {
+					wantHits++
+					if x.X[0] < best {
+						best, want = x.X[0], i
+					}
+				}
+			}
+			hit, err := bvh.IntersectRay(ray)
+			if hit.Idx != want || (want < 0) != (err != nil) {
+				t.Errorf("error:\n%v:\ngot=%v, %v\nwant=%v", ray, hit.Idx, err, want)
+			}
+			hits := bvh.IntersectRayAll(ray)
+			if len(hits) != wantHits {
+				t.Errorf("error:\n%v:\ngot=%v hits\nwant=%v", ray, len(hits), wantHits)
+			}
+			for i := 1; i < len(hits); i++ {
+				if hits[i].T < hits[i-1].T {
+					t.Errorf("error:\nhits are not sorted")
+				}
+			}
+		}
+	}
+}
+
+func TestBVHClosestPoint(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	prims := randomTriangles(rng, 2000)
+	bvh := MakeBVH(prims)
+	for k := 0; k < 200; k++ {
+		P := v3(rng.Float64()*30-15, rng.Float64()*30-15, rng.Float64()*30-15)
+		want := math.Inf(1)
+		for _, prim := range prims {
+			Q := prim.ClosestPoint(P)
+			want = math.Min(want, Q.Dist(P))
+		}
+		_, _, got := bvh.ClosestPoint(P)
+		if got != want {
+			t.Errorf("error:\n%v:\ngot=%v\nwant=%v", P, got, want)
+		}
+	}
+}
+
+func TestBVHOverlap(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	prims := randomTriangles(rng, 1000)
+	others := randomTriangles(rng, 500)
+	bvh, other := MakeBVH(prims), MakeBVHConcurrent(others)
+	for k := 0; k < 50; k++ {
+		P := v3(rng.Float64()*20-10, rng.Float64()*20-10, rng.Float64()*20-10)
+		box := MakeAABB(P, P.Add(v3(2, 3, 1)))
+		var want []int
+		for i, prim := range prims {
+			if primBox := prim.AABB(); primBox.Overlaps(box) {
+				want = append(want, i)
+			}
+		}
+		got := bvh.Overlap(box)
+		if len(got) != len(want) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+				break
+			}
+		}
+	}
+	var want [][2]int
+	for i, a := range prims {
+		boxA := a.AABB()
+		for k, b := range others {
+			if boxA.Overlaps(b.AABB()) {
+				want = append(want, [2]int{i, k})
+			}
+		}
+	}
+	got := bvh.OverlapBVH(&other)
+	if len(got) != len(want) {
+		t.Fatalf("error:\ngot=%v pairs\nwant=%v pairs", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("error:\ngot=%v\nwant=%v", got[i], want[i])
+		}
+	}
+}
+
+func TestBVHBoxes(t *testing.T) {
+	a := MakeAABB(v3(0, 0, 0), v3(1, 1, 1))
+	b := MakeOBB(v3(5, 0.5, 0.5), MakeRotation(0, math.Pi/4), v3(0.5, 0.5, 0.5))
+	bvh := MakeBVH([]Primitive{&b, &a})
+	hit, err := bvh.IntersectRay(MakeRay(v3(-1, 0.5, 0.5), v3(1, 0, 0)))
+	if err != nil || hit.Idx != 1 || hit.T != 1 {
+		t.Errorf("error:\ngot=%v, %v", hit, err)
+	}
+	hits := bvh.IntersectRayAll(MakeRay(v3(-1, 0.5, 0.5), v3(1, 0, 0)))
+	if len(hits) != 2 || hits[1].Idx != 0 || hits[1].T != 5.5 {
+		t.Errorf("error:\ngot=%v", hits)
+	}
+}
@@ -0,0 +1,160 @@
+package gm
+
+import (
+	"fmt"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Camera is a pinhole camera with intrinsics K and extrinsics R, T
+//
+// A world point P has camera coordinates R * P + T, with the z-axis along
+// the viewing direction, and pixel coordinates K * (R * P + T) after
+// division by the depth z.
+type Camera struct {
+	K rn.Mat // 3 x 3 upper triangular calibration matrix
+	R rn.Mat // 3 x 3 rotation from world to camera coordinates
+	T rn.Vec // translation from world to camera coordinates
+}
+
+func (o Camera) String() (str string) {
+	str += fmt.Sprintf("K =\n%v\nR =\n%v\nT = %v", o.K, o.R, o.T)
+	return
+}
+
+// MakeCamera returns a Camera object with the given intrinsics and extrinsics
+//
+// Parameters:
+//
+//	K rn.Mat - The 3 x 3 calibration matrix
+//	R rn.Mat - The 3 x 3 rotation from world to camera coordinates
+//	T rn.Vec - The translation from world to camera coordinates
+//
+// Returns:
+//
+//	cam Camera - The camera
+func MakeCamera(K, R rn.Mat, T rn.Vec) (cam Camera) {
+	if K.M != 3 || K.N != 3 || R.M != 3 || R.N != 3 || T.N != 3 {
+		panic(errors.ErrShape)
+	}
+	cam.K = K
+	cam.R = R
+	cam.T = T
+	return
+}
+
+// MakeIntrinsics returns the calibration matrix of a pinhole camera
+//
+// Parameters:
+//
+//	fx float64 - The focal length in pixels along x
+//	fy float64 - The focal length in pixels along y
+//	cx float64 - The x coordinate of the principal point
+//	cy float64 - The y coordinate of the principal point
+//	skew float64 - The skew, 0 for rectangular pixels
+//
+// Returns:
+//
+//	K rn.Mat - The matrix [fx skew cx; 0 fy cy; 0 0 1]
+func MakeIntrinsics(fx, fy, cx, cy, skew float64) (K rn.Mat) {
+	K = rn.MakeIdentity(3)
+	K.Set(0, 0, fx)
+	K.Set(0, 1, skew)
+	K.Set(0, 2, cx)
+	K.Set(1, 1, fy)
+	K.Set(1, 2, cy)
+	return
+}
+
+// Matrix returns the projection matrix of the camera
+//
+// Parameters:
+//
+//	o *Camera - The camera
+//
+// Returns:
+//
+//	P rn.Mat - The 3 x 4 matrix K * [R | T] acting on homogeneous world coordinates
+func (o *Camera) Matrix() (P rn.Mat) {
+	RT := rn.MakeMat(3, 4, 0)
+	copy(RT.Data, o.R.Data)
+	RT.SetCol(3, o.T)
+	P = o.K.Mul(RT)
+	return
+}
+
+// Center returns the position of the camera in world coordinates
+//
+// Parameters:
+//
+//	o *Camera - The camera
+//
+// Returns:
+//
+//	C rn.Vec - The center of projection -Rᵀ * T
+func (o *Camera) Center() (C rn.Vec) {
+	RT := o.R.Transpose()
+	C = RT.MulVec(o.T)
+	C = C.Scale(-1)
+	return
+}
+
+// Depth returns the depth of a point along the viewing direction
+//
+// Parameters:
+//
+//	P rn.Vec - The world point
+//
+// Returns:
+//
+//	z float64 - The z coordinate of P in camera coordinates, negative behind the camera
+func (o *Camera) Depth(P rn.Vec) (z float64) {
+	Pc := o.R.MulVec(P)
+	z = Pc.X[2] + o.T.X[2]
+	return
+}
+
+// Project returns the pixel a world point is imaged at
+//
+// Points behind the camera are projected as well; use Depth to reject them.
+//
+// Parameters:
+//
+//	P rn.Vec - The world point
+//
+// Returns:
+//
+//	p rn.Vec - The 2D pixel coordinates
+//	err error - ErrInfinity if P lies in the plane of the camera center parallel to the image
+func (o *Camera) Project(P rn.Vec) (p rn.Vec, err error) {
+	M := o.Matrix()
+	p, err = ApplyProjective(M, P)
+	return
+}
+
+// BackProject returns the ray of world points that are imaged at a pixel
+//
+// Parameters:
+//
+//	p rn.Vec - The 2D pixel coordinates
+//
+// Returns:
+//
+//	ray Line - The line through the camera center whose direction has unit depth,
+//	so ray.At(z) is the point at depth z
+//	err error - ErrSingular if K is singular
+func (o *Camera) BackProject(p rn.Vec) (ray Line, err error) {
+	if p.N != 2 {
+		panic(errors.ErrShape)
+	}
+	KInv, err := o.K.Inverse()
+	if err != nil {
+		return
+	}
+	d := KInv.MulVec(ToHomogeneous(p))
+	d = d.Scale(1 / d.X[2])
+	RT := o.R.Transpose()
+	ray = MakeLine(o.Center(), RT.MulVec(d))
+	return
+}
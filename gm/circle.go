@@ -0,0 +1,152 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Circle is the set of points at distance R from C that lie in the plane through C with unit normal Normal
+type Circle struct {
+	C, Normal rn.Vec
+	R         float64
+}
+
+func (o Circle) String() (str string) {
+	str += fmt.Sprintf("|x - %v| = %v, (x - %v) · %v = 0", o.C, o.R, o.C, o.Normal)
+	return
+}
+
+// MakeCircle returns a Circle object with center C and radius R in the plane with normal Normal
+//
+// Parameters:
+//
+//	C rn.Vec - The center
+//	Normal rn.Vec - The normal of the circle's plane, need not be normalized
+//	R float64 - The radius
+//
+// Returns:
+//
+//	circle Circle - The circle
+func MakeCircle(C, Normal rn.Vec, R float64) (circle Circle) {
+	if C.N != 3 || Normal.N != 3 {
+		panic(errors.ErrShape)
+	}
+	circle.C = C
+	circle.Normal = Normal.Scale(1 / Normal.Norm())
+	circle.R = math.Abs(R)
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Circle - circle to compare to q
+//	q Circle - circle to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *Circle) Equal(q Circle) bool {
+	return o.C.Equal(q.C) && o.Normal.Equal(q.Normal) && o.R == q.R
+}
+
+// Plane returns the plane the circle lies in
+//
+// Parameters:
+//
+//	o *Circle - The circle
+//
+// Returns:
+//
+//	plane Plane - The plane through C spanned by two orthonormal directions perpendicular to Normal
+func (o *Circle) Plane() (plane Plane) {
+	u, v := orthonormalBasis(o.Normal)
+	plane = MakePlane(o.C, u, v)
+	return
+}
+
+// At returns the point of the circle at angle θ
+//
+// Parameters:
+//
+//	theta float64 - The angle in radians, measured in the basis of o.Plane()
+//
+// Returns:
+//
+//	P1 rn.Vec - The point C + R * (cos θ * u + sin θ * v)
+func (o *Circle) At(theta float64) (P1 rn.Vec) {
+	u, v := orthonormalBasis(o.Normal)
+	u = u.Scale(o.R * math.Cos(theta))
+	v = v.Scale(o.R * math.Sin(theta))
+	P1 = o.C.Add(u.Add(v))
+	return
+}
+
+// Length returns the circumference of the circle
+//
+// Parameters:
+//
+//	o *Circle - The circle
+//
+// Returns:
+//
+//	l float64 - The circumference 2πR
+func (o *Circle) Length() (l float64) {
+	l = 2 * math.Pi * o.R
+	return
+}
+
+// Contains returns true if P lies on the circle
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if P lies in the circle's plane at distance R from C
+func (o *Circle) Contains(P rn.Vec) bool {
+	return o.Dist(P) <= tolerance(o.C, P)*math.Max(1, o.R)
+}
+
+// ClosestPoint returns the point on the circle closest to P
+//
+// If P lies on the axis of the circle every point is equally close and o.At(0) is returned.
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	P1 rn.Vec - The closest point on the circle
+func (o *Circle) ClosestPoint(P rn.Vec) (P1 rn.Vec) {
+	w := P.Sub(o.C)
+	w = w.Sub(o.Normal.Scale(o.Normal.Dot(w)))
+	nrm := w.Norm()
+	if nrm <= tolerance(o.C, P) {
+		P1 = o.At(0)
+		return
+	}
+	P1 = o.C.Add(w.Scale(o.R / nrm))
+	return
+}
+
+// Dist returns the distance between P and the circle
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance from P to its closest point on the circle
+func (o *Circle) Dist(P rn.Vec) (dist float64) {
+	P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
@@ -0,0 +1,305 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Conic is the set of points P of the plane with Xᵀ * A * X = 0 for X = (P, 1)
+type Conic struct {
+	A rn.Mat // 3 x 3 symmetric matrix
+}
+
+func (o Conic) String() (str string) {
+	str += fmt.Sprintf("xᵀ *\n%v\n* x = 0", o.A)
+	return
+}
+
+// ConicType is the affine class of a conic
+type ConicType int
+
+const (
+	ConicNone                   ConicType = iota // no quadratic terms
+	ConicEllipse                                 // real ellipse, including circles
+	ConicImaginaryEllipse                        // no real points
+	ConicHyperbola                               // two branches
+	ConicParabola                                // one branch
+	ConicPoint                                   // two complex lines through a real point
+	ConicIntersectingLines                       // two real lines that cross
+	ConicParallelLines                           // two distinct real parallel lines
+	ConicImaginaryParallelLines                  // no real points
+	ConicCoincidentLines                         // a double line
+)
+
+var conicNames = [...]string{
+	ConicNone:                   "none",
+	ConicEllipse:                "ellipse",
+	ConicImaginaryEllipse:       "imaginary ellipse",
+	ConicHyperbola:              "hyperbola",
+	ConicParabola:               "parabola",
+	ConicPoint:                  "point",
+	ConicIntersectingLines:      "intersecting lines",
+	ConicParallelLines:          "parallel lines",
+	ConicImaginaryParallelLines: "imaginary parallel lines",
+	ConicCoincidentLines:        "coincident lines",
+}
+
+func (o ConicType) String() string {
+	if o < 0 || int(o) >= len(conicNames) {
+		return "ConicType(?)"
+	}
+	return conicNames[o]
+}
+
+// conicTypes maps the inertia of the quadratic part and of the whole matrix to the conic type
+var conicTypes = map[[4]int]ConicType{
+	{2, 0, 2, 1}: ConicEllipse,
+	{2, 0, 3, 0}: ConicImaginaryEllipse,
+	{1, 1, 2, 1}: ConicHyperbola,
+	{1, 0, 2, 1}: ConicParabola,
+	{2, 0, 2, 0}: ConicPoint,
+	{1, 1, 1, 1}: ConicIntersectingLines,
+	{1, 0, 1, 1}: ConicParallelLines,
+	{1, 0, 2, 0}: ConicImaginaryParallelLines,
+	{1, 0, 1, 0}: ConicCoincidentLines,
+}
+
+// MakeConic returns a Conic object with the given matrix
+//
+// Parameters:
+//
+//	A rn.Mat - The 3 x 3 matrix, only its symmetric part (A + Aᵀ) / 2 is used
+//
+// Returns:
+//
+//	conic Conic - The conic Xᵀ * A * X = 0
+func MakeConic(A rn.Mat) (conic Conic) {
+	if A.M != 3 || A.N != 3 {
+		panic(errors.ErrShape)
+	}
+	conic.A = symmetricPart(A)
+	return
+}
+
+// MakeConicCoeffs returns the conic a*x² + b*x*y + c*y² + d*x + e*y + f = 0
+//
+// Parameters:
+//
+//	a, b, c, d, e, f float64 - The coefficients
+//
+// Returns:
+//
+//	conic Conic - The conic with matrix [a b/2 d/2; b/2 c e/2; d/2 e/2 f]
+func MakeConicCoeffs(a, b, c, d, e, f float64) (conic Conic) {
+	conic.A = rn.Mat{M: 3, N: 3, Data: []float64{
+		a, b / 2, d / 2,
+		b / 2, c, e / 2,
+		d / 2, e / 2, f,
+	}}
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Conic - conic to compare to q
+//	q Conic - conic to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q have the same matrix
+func (o *Conic) Equal(q Conic) bool {
+	return matEqual(o.A, q.A)
+}
+
+// Eval returns the value of the quadratic form at P
+//
+// Parameters:
+//
+//	P rn.Vec - The 2D point
+//
+// Returns:
+//
+//	val float64 - Xᵀ * A * X for X = (P, 1), 0 on the conic
+func (o *Conic) Eval(P rn.Vec) (val float64) {
+	if P.N != 2 {
+		panic(errors.ErrShape)
+	}
+	val = evalForm(o.A, P)
+	return
+}
+
+// Classify returns the affine class of the conic
+//
+// Zero eigenvalues and the sign of the constant after completing the square
+// are decided with a relative tolerance.
+//
+// Parameters:
+//
+//	o *Conic - The conic
+//
+// Returns:
+//
+//	t ConicType - The type, ConicNone if A has no quadratic terms
+func (o *Conic) Classify() (t ConicType) {
+	key, ok := formInertia(o.A)
+	if !ok {
+		return ConicNone
+	}
+	t = conicTypes[key]
+	return
+}
+
+// Transform returns the image of the conic under a projective transformation
+//
+// Parameters:
+//
+//	T rn.Mat - The 3 x 3 matrix acting on homogeneous coordinates
+//
+// Returns:
+//
+//	conic Conic - The conic with matrix T⁻ᵀ * A * T⁻¹
+//	err error - ErrSingular if T is singular
+func (o *Conic) Transform(T rn.Mat) (conic Conic, err error) {
+	if T.M != 3 || T.N != 3 {
+		panic(errors.ErrShape)
+	}
+	conic.A, err = transformForm(o.A, T)
+	return
+}
+
+// IntersectLine returns the intersection points of a conic and a line
+//
+// Parameters:
+//
+//	o *Conic - The conic
+//	q Line - The 2D line
+//
+// Returns:
+//
+//	ts []float64 - The line parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrParallel if the line is part of the conic,
+//	ErrNoIntersection if the line misses the conic
+func (o *Conic) IntersectLine(q Line) (ts []float64, Ps []rn.Vec, err error) {
+	if q.V1.N != 2 {
+		panic(errors.ErrShape)
+	}
+	ts, Ps, err = intersectForm(o.A, q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectRay returns the intersection points of a conic and a ray
+//
+// Parameters:
+//
+//	o *Conic - The conic
+//	q Ray - The 2D ray
+//
+// Returns:
+//
+//	ts []float64 - The ray parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrParallel if the ray is part of the conic,
+//	ErrNoIntersection if the ray misses the conic
+func (o *Conic) IntersectRay(q Ray) (ts []float64, Ps []rn.Vec, err error) {
+	if q.V1.N != 2 {
+		panic(errors.ErrShape)
+	}
+	ts, Ps, err = intersectForm(o.A, q.V1, q.V2, 0, math.Inf(1))
+	return
+}
+
+// IntersectSegment returns the intersection points of a conic and a segment
+//
+// Parameters:
+//
+//	o *Conic - The conic
+//	q Segment - The 2D segment
+//
+// Returns:
+//
+//	ts []float64 - The segment parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrParallel if the segment is part of the conic,
+//	ErrNoIntersection if the segment does not cross the conic
+func (o *Conic) IntersectSegment(q Segment) (ts []float64, Ps []rn.Vec, err error) {
+	if q.V1.N != 2 {
+		panic(errors.ErrShape)
+	}
+	ts, Ps, err = intersectForm(o.A, q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// Tangent returns the tangent line of the conic at P
+//
+// Parameters:
+//
+//	P rn.Vec - A 2D point on the conic
+//
+// Returns:
+//
+//	line Line - The line through P orthogonal to the gradient of the quadratic form
+//	err error - ErrDegenerate if the gradient vanishes, as at the crossing of two lines
+func (o *Conic) Tangent(P rn.Vec) (line Line, err error) {
+	if P.N != 2 {
+		panic(errors.ErrShape)
+	}
+	g, err := formGradient(o.A, P)
+	if err != nil {
+		return
+	}
+	line = MakeLine(P, rn.Vec{N: 2, X: []float64{-g.X[1], g.X[0]}})
+	return
+}
+
+// Polar returns the polar line of a point with respect to the conic
+//
+// For a point on the conic the polar is the tangent, for a point outside
+// it joins the two points of tangency seen from P.
+//
+// Parameters:
+//
+//	P rn.Vec - The 2D pole
+//
+// Returns:
+//
+//	line Line - The line with homogeneous coordinates A * (P, 1)
+//	err error - ErrInfinity if the polar is the line at infinity, as for the center of an
+//	ellipse, ErrDegenerate if P is a singular point of a degenerate conic
+func (o *Conic) Polar(P rn.Vec) (line Line, err error) {
+	if P.N != 2 {
+		panic(errors.ErrShape)
+	}
+	l := o.A.MulVec(ToHomogeneous(P))
+	Q, n, err := hyperplaneFromHomogeneous(l)
+	if err != nil {
+		return
+	}
+	line = MakeLine(Q, rn.Vec{N: 2, X: []float64{-n.X[1], n.X[0]}})
+	return
+}
+
+// Pole returns the pole of a line with respect to the conic
+//
+// Parameters:
+//
+//	line Line - The 2D polar line
+//
+// Returns:
+//
+//	P rn.Vec - The point whose polar is line
+//	err error - ErrSingular if the conic is degenerate, ErrInfinity if the pole is a point at
+//	infinity, as for a line through the center
+func (o *Conic) Pole(line Line) (P rn.Vec, err error) {
+	if line.V1.N != 2 {
+		panic(errors.ErrShape)
+	}
+	n := rn.Vec{N: 2, X: []float64{-line.V2.X[1], line.V2.X[0]}}
+	P, err = poleOf(o.A, n, -n.Dot(line.V1))
+	return
+}
@@ -0,0 +1,139 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+func TestConicClassify(t *testing.T) {
+	tests := []struct {
+		conic Conic
+		want  ConicType
+	}{
+		{MakeConicCoeffs(1, 0, 2, 0, 0, -1), ConicEllipse},
+		// (x - 3)² + (y + 1)² = 4
+		{MakeConicCoeffs(1, 0, 1, -6, 2, 6), ConicEllipse},
+		// a unit circle far from the origin
+		{MakeConicCoeffs(1, 0, 1, -2e4, 0, 1e8-1), ConicEllipse},
+		{MakeConicCoeffs(1, 0, 1, 0, 0, 1), ConicImaginaryEllipse},
+		{MakeConicCoeffs(0, 1, 0, 0, 0, -1), ConicHyperbola},
+		{MakeConicCoeffs(-1, 0, 0, 0, 1, 0), ConicParabola},
+		{MakeConicCoeffs(1, 0, 1, 0, 0, 0), ConicPoint},
+		{MakeConicCoeffs(1, 0, -1, 0, 0, 0), ConicIntersectingLines},
+		{MakeConicCoeffs(1, 0, 0, 0, 0, -1), ConicParallelLines},
+		{MakeConicCoeffs(1, 0, 0, 0, 0, 1), ConicImaginaryParallelLines},
+		// (x + y - 1)²
+		{MakeConicCoeffs(1, 2, 1, -2, -2, 1), ConicCoincidentLines},
+		{MakeConicCoeffs(0, 0, 0, 1, 1, 0), ConicNone},
+	}
+	for _, test := range tests {
+		if got := test.conic.Classify(); got != test.want {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.want)
+		}
+		// the sign of the matrix does not matter
+		neg := MakeConic(rn.Mat{M: 3, N: 3, Data: test.conic.A.Data})
+		for i := range neg.A.Data {
+			neg.A.Data[i] *= -1
+		}
+		if got := neg.Classify(); got != test.want {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.want)
+		}
+	}
+
+	// swapping x and w maps the unit circle to the hyperbola u² - v² = 1
+	circle := MakeConicCoeffs(1, 0, 1, 0, 0, -1)
+	T := rn.Mat{M: 3, N: 3, Data: []float64{0, 0, 1, 0, 1, 0, 1, 0, 0}}
+	hyperbola, err := circle.Transform(T)
+	if err != nil || hyperbola.Classify() != ConicHyperbola {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", hyperbola.Classify(), err, ConicHyperbola)
+	}
+	if got := hyperbola.Eval(v2(math.Sqrt(2), 1)); math.Abs(got) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 0)
+	}
+}
+
+func TestConicIntersectLine(t *testing.T) {
+	circle := MakeConicCoeffs(1, 0, 1, 0, 0, -1)
+	parabola := MakeConicCoeffs(1, 0, 0, 0, -1, 0)
+	lines := MakeConicCoeffs(1, 0, 0, 0, 0, -1)
+	h := math.Sqrt(0.75)
+	tests := []struct {
+		conic Conic
+		line  Line
+		Ps    []rn.Vec
+		err   error
+	}{
+		{circle, MakeLine(v2(0, 0.5), v2(2, 0)), []rn.Vec{v2(-h, 0.5), v2(h, 0.5)}, nil},
+		{circle, MakeLine(v2(5, 1), v2(-1, 0)), []rn.Vec{v2(0, 1)}, nil},
+		{circle, MakeLine(v2(0, 2), v2(1, 0)), nil, errors.ErrNoIntersection},
+		{parabola, MakeLine(v2(2, 0), v2(0, 1)), []rn.Vec{v2(2, 4)}, nil},
+		{parabola, MakeLine(v2(0, 1), v2(1, 1)), []rn.Vec{v2((1-math.Sqrt(5))/2, (3-math.Sqrt(5))/2), v2((1+math.Sqrt(5))/2, (3+math.Sqrt(5))/2)}, nil},
+		{lines, MakeLine(v2(1, 3), v2(0, 1)), nil, errors.ErrParallel},
+		{lines, MakeLine(v2(2, 3), v2(0, 1)), nil, errors.ErrNoIntersection},
+	}
+	for _, test := range tests {
+		ts, Ps, err := test.conic.IntersectLine(test.line)
+		if err != test.err || len(Ps) != len(test.Ps) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", Ps, err, test.Ps, test.err)
+			continue
+		}
+		for i := range Ps {
+			if !vecClose(Ps[i], test.Ps[i], 1e-12) || !vecClose(test.line.At(ts[i]), Ps[i], 1e-12) {
+				t.Errorf("error:\ngot=%v\nwant=%v", Ps[i], test.Ps[i])
+			}
+		}
+	}
+
+	// only the second crossing lies on the ray and the segment
+	if _, Ps, err := circle.IntersectRay(MakeRay(v2(0, 0), v2(1, 0))); err != nil || len(Ps) != 1 || !vecClose(Ps[0], v2(1, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", Ps, err, v2(1, 0))
+	}
+	if _, Ps, err := circle.IntersectSegment(MakeSegment(v2(0, -2), v2(0, 0))); err != nil || len(Ps) != 1 || !vecClose(Ps[0], v2(0, -1), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", Ps, err, v2(0, -1))
+	}
+}
+
+func TestConicPolar(t *testing.T) {
+	circle := MakeConicCoeffs(1, 0, 1, 0, 0, -1)
+	polar, err := circle.Polar(v2(2, 0))
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	// the polar of (2, 0) is the chord x = 1/2 through both points of tangency
+	for _, P := range []rn.Vec{v2(0.5, math.Sqrt(0.75)), v2(0.5, -math.Sqrt(0.75))} {
+		if d := polar.Dist(P); d > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", d, 0)
+		}
+		tangent, err := circle.Tangent(P)
+		if err != nil || tangent.Dist(v2(2, 0)) > 1e-12 {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v", tangent, err, "a tangent through (2, 0)")
+		}
+	}
+	if P, err := circle.Pole(polar); err != nil || !vecClose(P, v2(2, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", P, err, v2(2, 0))
+	}
+	// the polar of a point on the conic is its tangent
+	P := v2(0.6, 0.8)
+	polar, _ = circle.Polar(P)
+	tangent, _ := circle.Tangent(P)
+	if polar.Dist(P) > 1e-12 || math.Abs(polar.V2.Dot(v2(0.6, 0.8))) > 1e-12 || math.Abs(tangent.V2.Dot(v2(0.6, 0.8))) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", polar, tangent)
+	}
+
+	if _, err := circle.Polar(v2(0, 0)); err != errors.ErrInfinity {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+	if _, err := circle.Pole(MakeLine(v2(0, 0), v2(1, 1))); err != errors.ErrInfinity {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+	cross := MakeConicCoeffs(1, 0, -1, 0, 0, 0)
+	if _, err := cross.Tangent(v2(0, 0)); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+	if _, err := cross.Pole(MakeLine(v2(1, 0), v2(0, 1))); err != errors.ErrSingular {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrSingular)
+	}
+}
@@ -0,0 +1,228 @@
+package gm
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// FitPlane returns the least-squares plane through a set of 3D points
+//
+// The plane passes through the centroid and its normal is the eigenvector
+// of the smallest eigenvalue of the covariance matrix, which minimizes the
+// sum of squared orthogonal distances.
+//
+// Parameters:
+//
+//	Ps []rn.Vec - The points
+//
+// Returns:
+//
+//	plane Plane - The plane, V2 and V3 are orthonormal principal directions
+//	rms float64 - The root mean square distance of the points to the plane
+//	err error - ErrDegenerate if there are less than 3 points or they are collinear
+func FitPlane(Ps []rn.Vec) (plane Plane, rms float64, err error) {
+	checkDims(Ps, 3)
+	if len(Ps) < 3 {
+		err = errors.ErrDegenerate
+		return
+	}
+	mean, cov := covariance(Ps)
+	vals, vecs, _ := cov.SymEigen()
+	if vals.X[1] <= eps*vals.X[2] {
+		err = errors.ErrDegenerate
+		return
+	}
+	plane = MakePlane(mean, vecs.GetCol(2), vecs.GetCol(1))
+	n := plane.Normal()
+	for _, P := range Ps {
+		w := P.Sub(mean)
+		d := n.Dot(w)
+		rms += d * d
+	}
+	rms = math.Sqrt(rms / float64(len(Ps)))
+	return
+}
+
+// FitLine returns the least-squares line through a set of points
+//
+// The line passes through the centroid along the principal direction, the
+// eigenvector of the largest eigenvalue of the covariance matrix.
+//
+// Parameters:
+//
+//	Ps []rn.Vec - The points, all of the same dimension
+//
+// Returns:
+//
+//	line Line - The line, V2 has unit length
+//	rms float64 - The root mean square distance of the points to the line
+//	err error - ErrDegenerate if there are less than 2 distinct points
+func FitLine(Ps []rn.Vec) (line Line, rms float64, err error) {
+	if len(Ps) < 2 {
+		err = errors.ErrDegenerate
+		return
+	}
+	checkDims(Ps, Ps[0].N)
+	mean, cov := covariance(Ps)
+	vals, vecs, _ := cov.SymEigen()
+	n := vals.N
+	if vals.X[n-1] == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	line = MakeLine(mean, vecs.GetCol(n-1))
+	for _, P := range Ps {
+		d := line.Dist(P)
+		rms += d * d
+	}
+	rms = math.Sqrt(rms / float64(len(Ps)))
+	return
+}
+
+// FitPlaneRANSAC fits a plane to a set of 3D points that contains outliers
+//
+// Each iteration draws 3 points at random and counts the points within
+// threshold of the plane through them. The plane is then fitted by
+// least squares to the largest such set of inliers.
+//
+// Parameters:
+//
+//	Ps []rn.Vec - The points
+//	threshold float64 - The maximum distance of an inlier to the plane
+//	iterations int - The number of random samples
+//	rng *rand.Rand - The random number generator, seed it for reproducible results
+//
+// Returns:
+//
+//	plane Plane - The least-squares plane of the inliers
+//	inliers []int - The indices of the inliers in ascending order
+//	rms float64 - The root mean square distance of the inliers to the plane
+//	err error - ErrDegenerate if no sample spans a plane
+func FitPlaneRANSAC(Ps []rn.Vec, threshold float64, iterations int, rng *rand.Rand) (plane Plane, inliers []int, rms float64, err error) {
+	checkDims(Ps, 3)
+	inliers = ransac(Ps, 3, threshold, iterations, rng, func(sample []rn.Vec) (dist func(rn.Vec) float64, ok bool) {
+		candidate := MakePlaneByPoints(sample[0], sample[1], sample[2])
+		if parallelDirs(candidate.V2, candidate.V3) {
+			return
+		}
+		n := candidate.Normal()
+		dist = func(P rn.Vec) float64 {
+			w := P.Sub(candidate.V1)
+			return math.Abs(n.Dot(w))
+		}
+		ok = true
+		return
+	})
+	plane, rms, err = FitPlane(gather(Ps, inliers))
+	if err != nil {
+		inliers = nil
+	}
+	return
+}
+
+// FitLineRANSAC fits a line to a set of points that contains outliers
+//
+// Each iteration draws 2 points at random and counts the points within
+// threshold of the line through them. The line is then fitted by least
+// squares to the largest such set of inliers.
+//
+// Parameters:
+//
+//	Ps []rn.Vec - The points, all of the same dimension
+//	threshold float64 - The maximum distance of an inlier to the line
+//	iterations int - The number of random samples
+//	rng *rand.Rand - The random number generator, seed it for reproducible results
+//
+// Returns:
+//
+//	line Line - The least-squares line of the inliers
+//	inliers []int - The indices of the inliers in ascending order
+//	rms float64 - The root mean square distance of the inliers to the line
+//	err error - ErrDegenerate if all samples consist of equal points
+func FitLineRANSAC(Ps []rn.Vec, threshold float64, iterations int, rng *rand.Rand) (line Line, inliers []int, rms float64, err error) {
+	if len(Ps) > 0 {
+		checkDims(Ps, Ps[0].N)
+	}
+	inliers = ransac(Ps, 2, threshold, iterations, rng, func(sample []rn.Vec) (dist func(rn.Vec) float64, ok bool) {
+		if sample[0].Equal(sample[1]) {
+			return
+		}
+		candidate := MakeLineByPoints(sample[0], sample[1])
+		dist, ok = candidate.Dist, true
+		return
+	})
+	line, rms, err = FitLine(gather(Ps, inliers))
+	if err != nil {
+		inliers = nil
+	}
+	return
+}
+
+// ransac returns the largest set of points within threshold of a model through a random sample of size points
+//
+// model returns the distance function of the model through the sample, or
+// false if the sample is degenerate.
+func ransac(Ps []rn.Vec, size int, threshold float64, iterations int, rng *rand.Rand,
+	model func(sample []rn.Vec) (dist func(rn.Vec) float64, ok bool)) (inliers []int) {
+	if len(Ps) < size {
+		return
+	}
+	sample := make([]rn.Vec, size)
+	idx := make([]int, 0, size)
+	for it := 0; it < iterations; it++ {
+		idx = idx[:0]
+		for len(idx) < size {
+			i := rng.Intn(len(Ps))
+			if !containsInt(idx, i) {
+				idx = append(idx, i)
+			}
+		}
+		for k, i := range idx {
+			sample[k] = Ps[i]
+		}
+		dist, ok := model(sample)
+		if !ok {
+			continue
+		}
+		var in []int
+		for i, P := range Ps {
+			if dist(P) <= threshold {
+				in = append(in, i)
+			}
+		}
+		if len(in) > len(inliers) {
+			inliers = in
+		}
+	}
+	return
+}
+
+func containsInt(xs []int, x int) bool {
+	for _, y := range xs {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+// gather returns the points with the given indices
+func gather(Ps []rn.Vec, idx []int) (sub []rn.Vec) {
+	sub = make([]rn.Vec, len(idx))
+	for k, i := range idx {
+		sub[k] = Ps[i]
+	}
+	return
+}
+
+// checkDims panics with ErrShape unless all points have dimension n
+func checkDims(Ps []rn.Vec, n int) {
+	for _, P := range Ps {
+		if P.N != n {
+			panic(errors.ErrShape)
+		}
+	}
+}
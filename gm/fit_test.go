@@ -0,0 +1,107 @@
+package gm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// noisyPlane returns n points of the plane z = 0.5x - 0.25y + 2 with uniform noise of amplitude noise in z
+func noisyPlane(rng *rand.Rand, n int, noise float64) (Ps []rn.Vec) {
+	for k := 0; k < n; k++ {
+		x, y := 10*rng.Float64()-5, 10*rng.Float64()-5
+		Ps = append(Ps, v3(x, y, 0.5*x-0.25*y+2+noise*(2*rng.Float64()-1)))
+	}
+	return
+}
+
+func TestFitPlane(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	want := v3(0.5, -0.25, -1)
+	want = want.Scale(1 / want.Norm())
+	for _, noise := range []float64{0, 1e-3} {
+		plane, rms, err := FitPlane(noisyPlane(rng, 200, noise))
+		if err != nil {
+			t.Fatalf("error:\n%v\n", err)
+		}
+		n := plane.Normal()
+		if got := math.Abs(n.Dot(want)); math.Abs(got-1) > 10*noise+1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", n, want)
+		}
+		if rms > noise+1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", rms, noise)
+		}
+		if d := plane.SignedDist(v3(0, 0, 2)); math.Abs(d) > noise+1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", d, 0)
+		}
+	}
+	if _, _, err := FitPlane([]rn.Vec{v3(0, 0, 0), v3(1, 1, 1), v3(2, 2, 2), v3(3, 3, 3)}); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
+
+func TestFitLine(t *testing.T) {
+	Ps := []rn.Vec{v3(1, 1, 0), v3(3, 3, 0), v3(2, 2, 1), v3(2, 2, -1)}
+	line, rms, err := FitLine(Ps)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	want := v3(1, 1, 0)
+	want = want.Scale(1 / math.Sqrt(2))
+	if got := math.Abs(line.V2.Dot(want)); math.Abs(got-1) > 1e-12 || !vecClose(line.V1, v3(2, 2, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", line, want)
+	}
+	if want := math.Sqrt(0.5); math.Abs(rms-want) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", rms, want)
+	}
+	if _, _, err := FitLine([]rn.Vec{v3(1, 2, 3), v3(1, 2, 3)}); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
+
+func TestFitRANSAC(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	Ps := noisyPlane(rng, 60, 1e-3)
+	// 40 outliers far from the plane
+	for k := 0; k < 40; k++ {
+		P := v3(10*rng.Float64()-5, 10*rng.Float64()-5, 0)
+		P.X[2] = 0.5*P.X[0] - 0.25*P.X[1] + 2 + 1 + 5*rng.Float64()
+		Ps = append(Ps, P)
+	}
+	plane, inliers, rms, err := FitPlaneRANSAC(Ps, 0.01, 100, rand.New(rand.NewSource(7)))
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if len(inliers) != 60 || inliers[59] != 59 || rms > 1e-3 {
+		t.Errorf("error:\ngot=%v inliers, rms=%v\nwant=%v", len(inliers), rms, 60)
+	}
+	if d := plane.SignedDist(v3(0, 0, 2)); math.Abs(d) > 1e-3 {
+		t.Errorf("error:\ngot=%v\nwant=%v", d, 0)
+	}
+	// the same seed gives the same result
+	_, again, _, _ := FitPlaneRANSAC(Ps, 0.01, 100, rand.New(rand.NewSource(7)))
+	if len(again) != len(inliers) {
+		t.Errorf("error:\ngot=%v\nwant=%v", len(again), len(inliers))
+	}
+
+	var Qs []rn.Vec
+	for k := 0; k < 30; k++ {
+		s := rng.Float64()
+		Qs = append(Qs, rn.Vec{N: 2, X: []float64{s, 2*s + 1}})
+		Qs = append(Qs, rn.Vec{N: 2, X: []float64{rng.Float64(), rng.Float64()}})
+	}
+	line, inliers, _, err := FitLineRANSAC(Qs, 1e-9, 200, rand.New(rand.NewSource(1)))
+	if err != nil || len(inliers) != 30 {
+		t.Fatalf("error:\ngot=%v inliers, %v\nwant=%v", len(inliers), err, 30)
+	}
+	if d := line.Dist(rn.Vec{N: 2, X: []float64{-1, -1}}); d > 1e-9 {
+		t.Errorf("error:\ngot=%v\nwant=%v", d, 0)
+	}
+
+	if _, _, _, err := FitLineRANSAC(Qs[:1], 1, 10, rng); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
@@ -0,0 +1,580 @@
+package gm
+
+import (
+	"fmt"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// HalfEdge is a directed edge of a HalfEdgeMesh
+type HalfEdge struct {
+	Origin int // the vertex the half-edge starts at, -1 if it has been removed
+	Twin   int // the half-edge in the opposite direction
+	Next   int // the next half-edge around the face, or around the hole for boundary half-edges
+	Face   int // the face to the left, -1 for boundary half-edges
+}
+
+// HalfEdgeMesh is a triangle mesh with explicit adjacency
+//
+// Every edge is stored as two half-edges of opposite direction. Holes are
+// closed by boundary half-edges without a face that are linked into loops,
+// so every half-edge has a twin and a successor. Removed elements keep their
+// indices and are marked with -1; Mesh drops them.
+type HalfEdgeMesh struct {
+	Vertices   []rn.Vec
+	HalfEdges  []HalfEdge
+	VertexEdge []int // an outgoing half-edge of each vertex, -1 for isolated or removed vertices
+	FaceEdge   []int // a half-edge of each face, -1 for removed faces
+}
+
+func (o HalfEdgeMesh) String() (str string) {
+	str += fmt.Sprintf("half-edge mesh with %d vertices, %d edges and %d faces", o.numVertices(), o.numEdges(), o.numFaces())
+	return
+}
+
+// MakeHalfEdgeMesh returns the half-edge structure of an indexed triangle mesh
+//
+// Vertices where several fans of faces meet are accepted, see IsManifold.
+//
+// Parameters:
+//
+//	mesh Mesh - The mesh, with consistently oriented faces
+//
+// Returns:
+//
+//	hm HalfEdgeMesh - The half-edge mesh, face f of mesh is face f of hm
+//	err error - ErrDegenerate if a face repeats a vertex, ErrNonManifold if an
+//	edge is used twice in the same direction
+func MakeHalfEdgeMesh(mesh Mesh) (hm HalfEdgeMesh, err error) {
+	hm.Vertices = append([]rn.Vec{}, mesh.Vertices...)
+	hm.VertexEdge = make([]int, len(mesh.Vertices))
+	for v := range hm.VertexEdge {
+		hm.VertexEdge[v] = -1
+	}
+	hm.FaceEdge = make([]int, len(mesh.Faces))
+	hm.HalfEdges = make([]HalfEdge, 3*len(mesh.Faces))
+	index := map[[2]int]int{}
+	for f, face := range mesh.Faces {
+		if face[0] == face[1] || face[1] == face[2] || face[2] == face[0] {
+			err = errors.ErrDegenerate
+			return
+		}
+		for k := 0; k < 3; k++ {
+			h := 3*f + k
+			e := [2]int{face[k], face[(k+1)%3]}
+			if _, ok := index[e]; ok {
+				err = errors.ErrNonManifold
+				return
+			}
+			index[e] = h
+			hm.HalfEdges[h] = HalfEdge{Origin: face[k], Twin: -1, Next: 3*f + (k+1)%3, Face: f}
+			hm.VertexEdge[face[k]] = h
+		}
+		hm.FaceEdge[f] = 3 * f
+	}
+	n := len(hm.HalfEdges)
+	for h := 0; h < n; h++ {
+		if hm.HalfEdges[h].Twin >= 0 {
+			continue
+		}
+		a, b := hm.HalfEdges[h].Origin, hm.HalfEdges[hm.HalfEdges[h].Next].Origin
+		if t, ok := index[[2]int{b, a}]; ok {
+			hm.HalfEdges[h].Twin = t
+			hm.HalfEdges[t].Twin = h
+			continue
+		}
+		hm.HalfEdges[h].Twin = len(hm.HalfEdges)
+		hm.HalfEdges = append(hm.HalfEdges, HalfEdge{Origin: b, Twin: h, Next: -1, Face: -1})
+	}
+	// a boundary half-edge ending at a continues with the boundary half-edge
+	// at the other end of the fan of faces around a
+	for g := n; g < len(hm.HalfEdges); g++ {
+		e := hm.HalfEdges[g].Twin
+		for hm.HalfEdges[e].Face >= 0 {
+			e = hm.HalfEdges[hm.Prev(e)].Twin
+		}
+		hm.HalfEdges[g].Next = e
+	}
+	return
+}
+
+// Mesh returns the indexed triangle mesh of the remaining faces
+//
+// Parameters:
+//
+//	o *HalfEdgeMesh - The half-edge mesh
+//
+// Returns:
+//
+//	mesh Mesh - The faces in index order and the vertices with edges in index order
+func (o *HalfEdgeMesh) Mesh() (mesh Mesh) {
+	index := make([]int, len(o.Vertices))
+	for v := range o.Vertices {
+		if o.VertexEdge[v] >= 0 {
+			index[v] = len(mesh.Vertices)
+			mesh.Vertices = append(mesh.Vertices, o.Vertices[v])
+		}
+	}
+	for f, h := range o.FaceEdge {
+		if h < 0 {
+			continue
+		}
+		face := o.FaceVertices(f)
+		mesh.Faces = append(mesh.Faces, [3]int{index[face[0]], index[face[1]], index[face[2]]})
+	}
+	return
+}
+
+// Dest returns the vertex a half-edge points to
+//
+// Parameters:
+//
+//	h int - The half-edge
+//
+// Returns:
+//
+//	v int - The origin of the twin of h
+func (o *HalfEdgeMesh) Dest(h int) (v int) {
+	v = o.HalfEdges[o.HalfEdges[h].Twin].Origin
+	return
+}
+
+// Prev returns the half-edge before h around its face or hole
+//
+// Parameters:
+//
+//	h int - The half-edge
+//
+// Returns:
+//
+//	p int - The half-edge whose successor is h
+func (o *HalfEdgeMesh) Prev(h int) (p int) {
+	p = h
+	for o.HalfEdges[p].Next != h {
+		p = o.HalfEdges[p].Next
+	}
+	return
+}
+
+// FaceVertices returns the corners of a face
+//
+// Parameters:
+//
+//	f int - The face
+//
+// Returns:
+//
+//	vs [3]int - The vertices in counterclockwise order
+func (o *HalfEdgeMesh) FaceVertices(f int) (vs [3]int) {
+	h := o.FaceEdge[f]
+	for k := range vs {
+		vs[k] = o.HalfEdges[h].Origin
+		h = o.HalfEdges[h].Next
+	}
+	return
+}
+
+// Outgoing returns the half-edges that start at a vertex
+//
+// Parameters:
+//
+//	v int - The vertex
+//
+// Returns:
+//
+//	hs []int - The half-edges of the fan of v that contains VertexEdge[v],
+//	in clockwise order seen from outside
+func (o *HalfEdgeMesh) Outgoing(v int) (hs []int) {
+	h0 := o.VertexEdge[v]
+	if h0 < 0 {
+		return
+	}
+	for h := h0; ; {
+		hs = append(hs, h)
+		h = o.HalfEdges[o.HalfEdges[h].Twin].Next
+		if h == h0 {
+			break
+		}
+	}
+	return
+}
+
+// VertexNeighbors returns the vertices that share an edge with v
+//
+// Parameters:
+//
+//	v int - The vertex
+//
+// Returns:
+//
+//	vs []int - The neighbors in the order of Outgoing
+func (o *HalfEdgeMesh) VertexNeighbors(v int) (vs []int) {
+	for _, h := range o.Outgoing(v) {
+		vs = append(vs, o.Dest(h))
+	}
+	return
+}
+
+// VertexFaces returns the faces around a vertex
+//
+// Parameters:
+//
+//	v int - The vertex
+//
+// Returns:
+//
+//	fs []int - The faces to the left of the half-edges of Outgoing
+func (o *HalfEdgeMesh) VertexFaces(v int) (fs []int) {
+	for _, h := range o.Outgoing(v) {
+		if f := o.HalfEdges[h].Face; f >= 0 {
+			fs = append(fs, f)
+		}
+	}
+	return
+}
+
+// EdgeFaces returns the faces on both sides of an edge
+//
+// Parameters:
+//
+//	h int - A half-edge of the edge
+//
+// Returns:
+//
+//	f1 int - The face to the left of h, -1 on the boundary
+//	f2 int - The face to the right of h, -1 on the boundary
+func (o *HalfEdgeMesh) EdgeFaces(h int) (f1, f2 int) {
+	f1 = o.HalfEdges[h].Face
+	f2 = o.HalfEdges[o.HalfEdges[h].Twin].Face
+	return
+}
+
+// IsBoundaryEdge returns true if an edge has a face on one side only
+//
+// Parameters:
+//
+//	h int - A half-edge of the edge
+//
+// Returns:
+//
+//	bool - true if h or its twin is a boundary half-edge
+func (o *HalfEdgeMesh) IsBoundaryEdge(h int) bool {
+	f1, f2 := o.EdgeFaces(h)
+	return f1 < 0 || f2 < 0
+}
+
+// IsBoundaryVertex returns true if a vertex lies on a boundary loop
+//
+// Parameters:
+//
+//	v int - The vertex
+//
+// Returns:
+//
+//	bool - true if a boundary half-edge starts at v
+func (o *HalfEdgeMesh) IsBoundaryVertex(v int) bool {
+	for _, h := range o.Outgoing(v) {
+		if o.HalfEdges[h].Face < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BoundaryLoops returns the boundaries of the holes of the mesh
+//
+// Parameters:
+//
+//	o *HalfEdgeMesh - The half-edge mesh
+//
+// Returns:
+//
+//	loops [][]int - The vertices of each loop, clockwise seen from outside
+func (o *HalfEdgeMesh) BoundaryLoops() (loops [][]int) {
+	seen := make([]bool, len(o.HalfEdges))
+	for h0, he := range o.HalfEdges {
+		if he.Origin < 0 || he.Face >= 0 || seen[h0] {
+			continue
+		}
+		var loop []int
+		for h := h0; !seen[h]; h = o.HalfEdges[h].Next {
+			seen[h] = true
+			loop = append(loop, o.HalfEdges[h].Origin)
+		}
+		loops = append(loops, loop)
+	}
+	return
+}
+
+// IsManifold returns true if the faces around every vertex form a single fan
+//
+// Parameters:
+//
+//	o *HalfEdgeMesh - The half-edge mesh
+//
+// Returns:
+//
+//	bool - true if Outgoing reaches every half-edge of every vertex
+func (o *HalfEdgeMesh) IsManifold() bool {
+	count := make([]int, len(o.Vertices))
+	for _, he := range o.HalfEdges {
+		if he.Origin >= 0 {
+			count[he.Origin]++
+		}
+	}
+	for v := range o.Vertices {
+		if len(o.Outgoing(v)) != count[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// EulerCharacteristic returns V - E + F
+//
+// Parameters:
+//
+//	o *HalfEdgeMesh - The half-edge mesh
+//
+// Returns:
+//
+//	chi int - The Euler characteristic, 2 - 2g - b for a connected surface
+//	of genus g with b boundary loops
+func (o *HalfEdgeMesh) EulerCharacteristic() (chi int) {
+	chi = o.numVertices() - o.numEdges() + o.numFaces()
+	return
+}
+
+func (o *HalfEdgeMesh) numVertices() (n int) {
+	for _, h := range o.VertexEdge {
+		if h >= 0 {
+			n++
+		}
+	}
+	return
+}
+
+func (o *HalfEdgeMesh) numEdges() (n int) {
+	for _, he := range o.HalfEdges {
+		if he.Origin >= 0 {
+			n++
+		}
+	}
+	n /= 2
+	return
+}
+
+func (o *HalfEdgeMesh) numFaces() (n int) {
+	for _, h := range o.FaceEdge {
+		if h >= 0 {
+			n++
+		}
+	}
+	return
+}
+
+// findEdge returns the half-edge from a to b, or -1 if there is none
+func (o *HalfEdgeMesh) findEdge(a, b int) int {
+	for _, h := range o.Outgoing(a) {
+		if o.Dest(h) == b {
+			return h
+		}
+	}
+	return -1
+}
+
+// Flip replaces an interior edge by the other diagonal of its two faces
+//
+// Parameters:
+//
+//	h int - A half-edge of the edge, it becomes a half-edge of the new edge
+//
+// Returns:
+//
+//	err error - ErrTopology if the edge is on the boundary or the other diagonal is already an edge
+func (o *HalfEdgeMesh) Flip(h int) (err error) {
+	he := o.HalfEdges
+	t := he[h].Twin
+	f1, f2 := he[h].Face, he[t].Face
+	if f1 < 0 || f2 < 0 {
+		err = errors.ErrTopology
+		return
+	}
+	// h = a→b in (a, b, c) and t = b→a in (b, a, d) become d→c in (d, c, a) and c→d in (c, d, b)
+	h1, t1 := he[h].Next, he[t].Next
+	h2, t2 := he[h1].Next, he[t1].Next
+	a, b, c, d := he[h].Origin, he[t].Origin, he[h2].Origin, he[t2].Origin
+	if c == d || o.findEdge(c, d) >= 0 {
+		err = errors.ErrTopology
+		return
+	}
+	he[h] = HalfEdge{Origin: d, Twin: t, Next: h2, Face: f1}
+	he[h2].Next = t1
+	he[t1].Next, he[t1].Face = h, f1
+	he[t] = HalfEdge{Origin: c, Twin: h, Next: t2, Face: f2}
+	he[t2].Next = h1
+	he[h1].Next, he[h1].Face = t, f2
+	o.FaceEdge[f1], o.FaceEdge[f2] = h, t
+	if o.VertexEdge[a] == h {
+		o.VertexEdge[a] = t1
+	}
+	if o.VertexEdge[b] == t {
+		o.VertexEdge[b] = h1
+	}
+	return
+}
+
+// Split inserts a vertex at the midpoint of an edge and splits the faces on both sides
+//
+// Parameters:
+//
+//	h int - A half-edge of the edge, afterwards it ends at the new vertex
+//
+// Returns:
+//
+//	v int - The new vertex
+func (o *HalfEdgeMesh) Split(h int) (v int) {
+	t := o.HalfEdges[h].Twin
+	P := o.Vertices[o.HalfEdges[h].Origin].Add(o.Vertices[o.HalfEdges[t].Origin])
+	v = len(o.Vertices)
+	o.Vertices = append(o.Vertices, P.Scale(0.5))
+	// h = a→b and t = b→a become a→v, v→b and b→v, v→a
+	hn, tn := len(o.HalfEdges), len(o.HalfEdges)+1
+	o.HalfEdges = append(o.HalfEdges, HalfEdge{Origin: v, Twin: t}, HalfEdge{Origin: v, Twin: h})
+	o.HalfEdges[h].Twin = tn
+	o.HalfEdges[t].Twin = hn
+	o.VertexEdge = append(o.VertexEdge, hn)
+	o.splitSide(h, hn)
+	o.splitSide(t, tn)
+	return
+}
+
+// splitSide links the new half-edge hn after h and splits the face to the left of h
+func (o *HalfEdgeMesh) splitSide(h, hn int) {
+	h1 := o.HalfEdges[h].Next
+	f := o.HalfEdges[h].Face
+	o.HalfEdges[hn].Next, o.HalfEdges[hn].Face = h1, f
+	if f < 0 {
+		o.HalfEdges[h].Next = hn
+		return
+	}
+	// (a, v, b, c) is cut along v-c into (a, v, c) and (v, b, c)
+	h2 := o.HalfEdges[h1].Next
+	v, c := o.HalfEdges[hn].Origin, o.HalfEdges[h2].Origin
+	g := len(o.FaceEdge)
+	e1, e2 := len(o.HalfEdges), len(o.HalfEdges)+1
+	o.HalfEdges = append(o.HalfEdges,
+		HalfEdge{Origin: v, Twin: e2, Next: h2, Face: f},
+		HalfEdge{Origin: c, Twin: e1, Next: hn, Face: g})
+	o.HalfEdges[h].Next = e1
+	o.HalfEdges[hn].Face = g
+	o.HalfEdges[h1].Next, o.HalfEdges[h1].Face = e2, g
+	o.FaceEdge[f] = h
+	o.FaceEdge = append(o.FaceEdge, hn)
+}
+
+// Collapse removes an edge by merging the vertex it points to into the vertex it starts at
+//
+// The faces on both sides of the edge are removed and the remaining vertex
+// keeps its position. The collapse is refused if it would make the mesh
+// non-manifold or degenerate.
+//
+// Parameters:
+//
+//	h int - The half-edge, Dest(h) is removed and Origin of h remains
+//
+// Returns:
+//
+//	err error - ErrTopology if the collapse is refused
+func (o *HalfEdgeMesh) Collapse(h int) (err error) {
+	if !o.canCollapse(h) {
+		err = errors.ErrTopology
+		return
+	}
+	he := o.HalfEdges
+	t := he[h].Twin
+	a, b := he[h].Origin, he[t].Origin
+	out := append(o.Outgoing(a), o.Outgoing(b)...)
+	o.collapseSide(h)
+	o.collapseSide(t)
+	he[h].Origin, he[t].Origin = -1, -1
+	o.VertexEdge[a], o.VertexEdge[b] = -1, -1
+	for _, g := range out {
+		if he[g].Origin == b {
+			he[g].Origin = a
+		}
+		if he[g].Origin == a {
+			o.VertexEdge[a] = g
+		}
+	}
+	return
+}
+
+// collapseSide removes the face or boundary half-edge to the left of the collapsing half-edge h
+func (o *HalfEdgeMesh) collapseSide(h int) {
+	he := o.HalfEdges
+	if he[h].Face < 0 {
+		p := o.Prev(h)
+		he[p].Next = he[h].Next
+		return
+	}
+	// h = a→b, h1 = b→c and h2 = c→a: the edges b-c and c-a become one
+	h1 := he[h].Next
+	h2 := he[h1].Next
+	x, y := he[h1].Twin, he[h2].Twin
+	he[x].Twin, he[y].Twin = y, x
+	if c := he[h2].Origin; o.VertexEdge[c] == h2 {
+		o.VertexEdge[c] = x
+	}
+	o.FaceEdge[he[h].Face] = -1
+	he[h1].Origin, he[h2].Origin = -1, -1
+}
+
+// canCollapse returns true if collapsing h keeps the mesh a manifold without degenerate faces
+func (o *HalfEdgeMesh) canCollapse(h int) bool {
+	he := o.HalfEdges
+	t := he[h].Twin
+	a, b := he[h].Origin, he[t].Origin
+	var opposite []int
+	for _, g := range []int{h, t} {
+		if he[g].Face < 0 {
+			continue
+		}
+		g1 := he[g].Next
+		g2 := he[g1].Next
+		// a face whose other edges are both on the boundary would leave a dangling edge
+		if he[he[g1].Twin].Face < 0 && he[he[g2].Twin].Face < 0 {
+			return false
+		}
+		opposite = append(opposite, he[g2].Origin)
+	}
+	// link condition: the common neighbors of a and b are the vertices opposite to the edge
+	na := map[int]bool{}
+	for _, v := range o.VertexNeighbors(a) {
+		na[v] = true
+	}
+	common := 0
+	for _, v := range o.VertexNeighbors(b) {
+		if !na[v] {
+			continue
+		}
+		common++
+		if v != opposite[0] && (len(opposite) < 2 || v != opposite[1]) {
+			return false
+		}
+	}
+	if common != len(opposite) {
+		return false
+	}
+	// an interior edge between two boundary vertices would pinch the surface
+	if !o.IsBoundaryEdge(h) && o.IsBoundaryVertex(a) && o.IsBoundaryVertex(b) {
+		return false
+	}
+	// the edge of a tetrahedron would leave two faces glued back to back
+	if len(opposite) == 2 && len(o.Outgoing(opposite[0])) == 3 && len(o.Outgoing(opposite[1])) == 3 &&
+		o.findEdge(opposite[0], opposite[1]) >= 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// unitSquare returns the unit square in the xy-plane split along the diagonal 0-2
+func unitSquare() Mesh {
+	return MakeMesh([]rn.Vec{v3(0, 0, 0), v3(1, 0, 0), v3(1, 1, 0), v3(0, 1, 0)}, [][3]int{{0, 1, 2}, {0, 2, 3}})
+}
+
+func TestHalfEdgeTopology(t *testing.T) {
+	tests := []struct {
+		name     string
+		mesh     Mesh
+		chi      int
+		loops    int
+		manifold bool
+	}{
+		{"cube", unitCube(), 2, 0, true},
+		{"square", unitSquare(), 1, 1, true},
+		{"open cube", MakeMesh(unitCube().Vertices, unitCube().Faces[2:]), 1, 1, true},
+		// two triangles that only share vertex 0
+		{"bowtie", MakeMesh([]rn.Vec{v3(0, 0, 0), v3(1, 0, 0), v3(1, 1, 0), v3(-1, 0, 0), v3(-1, -1, 0)},
+			[][3]int{{0, 1, 2}, {0, 3, 4}}), 1, 2, false},
+	}
+	for _, test := range tests {
+		hm, err := MakeHalfEdgeMesh(test.mesh)
+		if err != nil {
+			t.Fatalf("error:\n%v: %v\n", test.name, err)
+		}
+		if got := hm.EulerCharacteristic(); got != test.chi {
+			t.Errorf("error:\n%v\ngot=%v\nwant=%v", test.name, got, test.chi)
+		}
+		if got := len(hm.BoundaryLoops()); got != test.loops {
+			t.Errorf("error:\n%v\ngot=%v loops\nwant=%v", test.name, got, test.loops)
+		}
+		if got := hm.IsManifold(); got != test.manifold {
+			t.Errorf("error:\n%v\ngot=%v\nwant=%v", test.name, got, test.manifold)
+		}
+		if got := hm.Mesh(); !sameMesh(got, test.mesh, 0) {
+			t.Errorf("error:\n%v\ngot=%v\nwant=%v", test.name, got, test.mesh)
+		}
+	}
+
+	hm, _ := MakeHalfEdgeMesh(unitSquare())
+	if got := hm.BoundaryLoops()[0]; len(got) != 4 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "4 vertices")
+	}
+	h := hm.findEdge(0, 2)
+	if f1, f2 := hm.EdgeFaces(h); f1 != 1 || f2 != 0 || hm.IsBoundaryEdge(h) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", f1, f2, 1, 0)
+	}
+	if got := hm.VertexFaces(0); len(got) != 2 || !hm.IsBoundaryVertex(0) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "2 faces")
+	}
+
+	cube, _ := MakeHalfEdgeMesh(unitCube())
+	for v := range cube.Vertices {
+		if got := len(cube.VertexNeighbors(v)); got != len(cube.VertexFaces(v)) || cube.IsBoundaryVertex(v) {
+			t.Errorf("error:\nvertex %v has %v neighbors and %v faces", v, got, len(cube.VertexFaces(v)))
+		}
+	}
+
+	// three faces on the edge 0-1
+	fin := MakeMesh([]rn.Vec{v3(0, 0, 0), v3(1, 0, 0), v3(0, 1, 0), v3(0, 0, 1), v3(0, -1, 0)},
+		[][3]int{{0, 1, 2}, {1, 0, 3}, {0, 1, 4}})
+	if _, err := MakeHalfEdgeMesh(fin); err != errors.ErrNonManifold {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrNonManifold)
+	}
+}
+
+func TestHalfEdgeOps(t *testing.T) {
+	square, _ := MakeHalfEdgeMesh(unitSquare())
+	h := square.findEdge(0, 2)
+	if err := square.Flip(h); err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if square.findEdge(0, 2) >= 0 || square.findEdge(1, 3) < 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", square.Mesh().Faces, "diagonal 1-3")
+	}
+	if got := square.Mesh(); got.SurfaceArea() != 1 || len(got.BoundaryEdges()) != 4 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "the unit square")
+	}
+	if err := square.Flip(square.findEdge(0, 1)); err != errors.ErrTopology {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrTopology)
+	}
+
+	cube, _ := MakeHalfEdgeMesh(unitCube())
+	h = cube.findEdge(0, 1)
+	v := cube.Split(h)
+	if !vecClose(cube.Vertices[v], v3(0.5, 0, 0), 0) || len(cube.VertexFaces(v)) != 4 {
+		t.Errorf("error:\ngot=%v with faces %v\nwant=%v", cube.Vertices[v], cube.VertexFaces(v), v3(0.5, 0, 0))
+	}
+	mesh := cube.Mesh()
+	if got := cube.EulerCharacteristic(); got != 2 || !mesh.IsWatertight() || math.Abs(mesh.Volume()-1) > 1e-12 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", got, mesh, "a closed cube")
+	}
+	if err := cube.Collapse(cube.findEdge(0, v)); err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if err := cube.Flip(cube.findEdge(1, 2)); err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	mesh = cube.Mesh()
+	if got := cube.EulerCharacteristic(); got != 2 || len(mesh.Faces) != 12 || !mesh.IsWatertight() || !cube.IsManifold() {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", got, mesh, "a closed mesh with 12 faces")
+	}
+
+	tet := MakeMesh([]rn.Vec{v3(0, 0, 0), v3(1, 0, 0), v3(0, 1, 0), v3(0, 0, 1)}, [][3]int{{0, 2, 1}, {0, 1, 3}, {1, 2, 3}, {0, 3, 2}})
+	hm, _ := MakeHalfEdgeMesh(tet)
+	if err := hm.Collapse(hm.findEdge(0, 1)); err != errors.ErrTopology {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrTopology)
+	}
+
+	// collapsing a boundary edge of the square leaves one triangle
+	square, _ = MakeHalfEdgeMesh(unitSquare())
+	if err := square.Collapse(square.findEdge(1, 2)); err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	mesh = square.Mesh()
+	if got := square.BoundaryLoops(); len(mesh.Faces) != 1 || len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", mesh, got, "one triangle")
+	}
+	if err := square.Collapse(square.findEdge(0, 1)); err != errors.ErrTopology {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrTopology)
+	}
+}
@@ -0,0 +1,345 @@
+package gm
+
+import (
+	"math"
+	"sort"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Hull is the convex hull of a 3D point set
+type Hull struct {
+	Points []rn.Vec // the input points
+	Faces  [][3]int // triangles as indices into Points, counterclockwise seen from outside
+	tol    float64  // distance below which a point counts as lying on a face
+	planes []hullFace
+}
+
+type hullFace struct {
+	v       [3]int
+	n       rn.Vec // outward unit normal
+	d       float64
+	outside []int
+	alive   bool
+}
+
+func (o *hullFace) dist(P rn.Vec) float64 {
+	return o.n.Dot(P) - o.d
+}
+
+// MakeHull computes the convex hull of a 3D point set with the quickhull algorithm
+//
+// Points that lie on a face of the hull within a relative tolerance, as
+// well as duplicates, are not used as hull vertices, so coplanar faces are
+// triangulated only through their corners.
+//
+// Parameters:
+//
+//	points []rn.Vec - The points
+//
+// Returns:
+//
+//	hull Hull - The convex hull
+//	err error - ErrDegenerate if the points do not span a volume
+func MakeHull(points []rn.Vec) (hull Hull, err error) {
+	hull.Points = points
+	if len(points) < 4 {
+		err = errors.ErrDegenerate
+		return
+	}
+	var scale float64
+	for _, P := range points {
+		if P.N != 3 {
+			panic(errors.ErrShape)
+		}
+		for _, x := range P.X {
+			scale = math.Max(scale, math.Abs(x))
+		}
+	}
+	hull.tol = 1e-12 * scale
+	simplex, ok := hull.initialSimplex()
+	if !ok {
+		err = errors.ErrDegenerate
+		return
+	}
+	a, b, c, d := simplex[0], simplex[1], simplex[2], simplex[3]
+	edges := map[[2]int]int{}
+	for _, f := range [][3]int{{a, b, c}, {a, d, b}, {b, d, c}, {c, d, a}} {
+		hull.addFace(f, edges)
+	}
+	for i := range points {
+		if i == a || i == b || i == c || i == d {
+			continue
+		}
+		hull.assign(i, 0, len(hull.planes))
+	}
+	for fi := 0; fi < len(hull.planes); fi++ {
+		if f := &hull.planes[fi]; f.alive && len(f.outside) > 0 {
+			hull.expand(fi, edges)
+		}
+	}
+	for _, f := range hull.planes {
+		if f.alive {
+			hull.Faces = append(hull.Faces, f.v)
+		}
+	}
+	hull.planes = nil
+	return
+}
+
+// initialSimplex returns four points that span a tetrahedron with (a, b, c) facing away from d
+func (o *Hull) initialSimplex() (simplex [4]int, ok bool) {
+	Ps := o.Points
+	// the two extreme points along the coordinate axis with the largest spread
+	var a, b int
+	best := -1.0
+	for i := 0; i < 3; i++ {
+		lo, hi := 0, 0
+		for k, P := range Ps {
+			if P.X[i] < Ps[lo].X[i] {
+				lo = k
+			}
+			if P.X[i] > Ps[hi].X[i] {
+				hi = k
+			}
+		}
+		if spread := Ps[hi].X[i] - Ps[lo].X[i]; spread > best {
+			best, a, b = spread, lo, hi
+		}
+	}
+	if best <= o.tol {
+		return
+	}
+	line := MakeLineByPoints(Ps[a], Ps[b])
+	c, best := -1, o.tol
+	for k, P := range Ps {
+		foot := line.At(projectParam(line, P))
+		if d := foot.Dist(P); d > best {
+			best, c = d, k
+		}
+	}
+	if c < 0 {
+		return
+	}
+	n := triNormal(Ps[a], Ps[b], Ps[c])
+	d, best := -1, o.tol
+	for k, P := range Ps {
+		w := P.Sub(Ps[a])
+		if dist := math.Abs(n.Dot(w)); dist > best {
+			best, d = dist, k
+		}
+	}
+	if d < 0 {
+		return
+	}
+	if w := Ps[d].Sub(Ps[a]); n.Dot(w) > 0 {
+		b, c = c, b
+	}
+	simplex, ok = [4]int{a, b, c, d}, true
+	return
+}
+
+// projectParam returns the parameter of the point on line closest to P
+func projectParam(line Line, P rn.Vec) float64 {
+	w := P.Sub(line.V1)
+	return line.V2.Dot(w) / line.V2.Dot(line.V2)
+}
+
+// triNormal returns the unit normal of the triangle (a, b, c)
+func triNormal(a, b, c rn.Vec) (n rn.Vec) {
+	tri := MakeTriangle(a, b, c)
+	n = tri.Normal()
+	return
+}
+
+func (o *Hull) addFace(v [3]int, edges map[[2]int]int) (fi int) {
+	n := triNormal(o.Points[v[0]], o.Points[v[1]], o.Points[v[2]])
+	o.planes = append(o.planes, hullFace{v: v, n: n, d: n.Dot(o.Points[v[0]]), alive: true})
+	fi = len(o.planes) - 1
+	for k := 0; k < 3; k++ {
+		edges[[2]int{v[k], v[(k+1)%3]}] = fi
+	}
+	return
+}
+
+// assign adds point i to the outside set of the first face in planes[from:to] it lies above
+func (o *Hull) assign(i, from, to int) {
+	for fi := from; fi < to; fi++ {
+		if f := &o.planes[fi]; f.alive && f.dist(o.Points[i]) > o.tol {
+			f.outside = append(f.outside, i)
+			return
+		}
+	}
+}
+
+// expand adds the point of face fi's outside set that is furthest from the face to the hull
+func (o *Hull) expand(fi int, edges map[[2]int]int) {
+	f := &o.planes[fi]
+	eye, best := -1, -1.0
+	for _, i := range f.outside {
+		if d := f.dist(o.Points[i]); d > best {
+			best, eye = d, i
+		}
+	}
+	E := o.Points[eye]
+	// collect the faces visible from the eye point, starting at fi
+	visible := map[int]bool{fi: true}
+	queue := []int{fi}
+	var horizon [][2]int
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		for k := 0; k < 3; k++ {
+			u, v := o.planes[g].v[k], o.planes[g].v[(k+1)%3]
+			h := edges[[2]int{v, u}]
+			if visible[h] {
+				continue
+			}
+			if o.planes[h].dist(E) > o.tol {
+				visible[h] = true
+				queue = append(queue, h)
+			} else {
+				horizon = append(horizon, [2]int{u, v})
+			}
+		}
+	}
+	var orphans []int
+	for g := range visible {
+		face := &o.planes[g]
+		face.alive = false
+		for k := 0; k < 3; k++ {
+			delete(edges, [2]int{face.v[k], face.v[(k+1)%3]})
+		}
+		orphans = append(orphans, face.outside...)
+		face.outside = nil
+	}
+	from := len(o.planes)
+	for _, e := range horizon {
+		o.addFace([3]int{e[0], e[1], eye}, edges)
+	}
+	// process orphans in index order so that hull construction is deterministic
+	sort.Ints(orphans)
+	for _, i := range orphans {
+		if i != eye {
+			o.assign(i, from, len(o.planes))
+		}
+	}
+}
+
+// Triangles returns the faces of the hull as triangles
+//
+// Parameters:
+//
+//	o *Hull - The hull
+//
+// Returns:
+//
+//	tris []Triangle - The faces, their normals point outward
+func (o *Hull) Triangles() (tris []Triangle) {
+	for _, f := range o.Faces {
+		tris = append(tris, MakeTriangle(o.Points[f[0]], o.Points[f[1]], o.Points[f[2]]))
+	}
+	return
+}
+
+// Planes returns the planes of the faces of the hull
+//
+// Parameters:
+//
+//	o *Hull - The hull
+//
+// Returns:
+//
+//	planes []Plane - The face planes built with MakePlaneByPoints, their normals point outward
+func (o *Hull) Planes() (planes []Plane) {
+	for _, f := range o.Faces {
+		planes = append(planes, MakePlaneByPoints(o.Points[f[0]], o.Points[f[1]], o.Points[f[2]]))
+	}
+	return
+}
+
+// Vertices returns the indices of the points that are corners of the hull
+//
+// Parameters:
+//
+//	o *Hull - The hull
+//
+// Returns:
+//
+//	idx []int - The indices into Points in ascending order
+func (o *Hull) Vertices() (idx []int) {
+	seen := map[int]bool{}
+	for _, f := range o.Faces {
+		for _, i := range f {
+			if !seen[i] {
+				seen[i] = true
+				idx = append(idx, i)
+			}
+		}
+	}
+	sort.Ints(idx)
+	return
+}
+
+// Volume returns the volume enclosed by the hull
+//
+// Parameters:
+//
+//	o *Hull - The hull
+//
+// Returns:
+//
+//	vol float64 - The volume, the sum of the signed tetrahedra spanned by the faces and a vertex
+func (o *Hull) Volume() (vol float64) {
+	if len(o.Faces) == 0 {
+		return
+	}
+	ref := o.Points[o.Faces[0][0]]
+	for _, f := range o.Faces {
+		a, b, c := o.Points[f[0]].Sub(ref), o.Points[f[1]].Sub(ref), o.Points[f[2]].Sub(ref)
+		bc := b.Cross(c)
+		vol += a.Dot(bc)
+	}
+	vol /= 6
+	return
+}
+
+// SurfaceArea returns the total area of the faces of the hull
+//
+// Parameters:
+//
+//	o *Hull - The hull
+//
+// Returns:
+//
+//	area float64 - The surface area
+func (o *Hull) SurfaceArea() (area float64) {
+	for _, tri := range o.Triangles() {
+		area += tri.Area()
+	}
+	return
+}
+
+// Contains returns true if P lies inside or on the hull
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if P lies behind or on every face plane
+func (o *Hull) Contains(P rn.Vec) bool {
+	if len(o.Faces) == 0 {
+		return false
+	}
+	for _, f := range o.Faces {
+		n := triNormal(o.Points[f[0]], o.Points[f[1]], o.Points[f[2]])
+		w := P.Sub(o.Points[f[0]])
+		if n.Dot(w) > o.tol {
+			return false
+		}
+	}
+	return true
+}
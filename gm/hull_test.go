@@ -0,0 +1,85 @@
+package gm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func TestHullCube(t *testing.T) {
+	var points []rn.Vec
+	// corners, edge midpoints, face centers, interior points and duplicates
+	for _, x := range []float64{0, 0.5, 1} {
+		for _, y := range []float64{0, 0.5, 1} {
+			for _, z := range []float64{0, 0.5, 1} {
+				points = append(points, v3(x, y, z), v3(x, y, z))
+			}
+		}
+	}
+	hull, err := MakeHull(points)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if got := len(hull.Vertices()); got != 8 {
+		t.Errorf("error:\ngot=%v vertices\nwant=%v", got, 8)
+	}
+	if got := len(hull.Faces); got != 12 {
+		t.Errorf("error:\ngot=%v faces\nwant=%v", got, 12)
+	}
+	if got := hull.Volume(); math.Abs(got-1) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 1)
+	}
+	if got := hull.SurfaceArea(); math.Abs(got-6) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 6)
+	}
+	center := v3(0.5, 0.5, 0.5)
+	for _, plane := range hull.Planes() {
+		if plane.SignedDist(center) >= 0 {
+			t.Errorf("error:\nface %v does not point outward", plane)
+		}
+	}
+	if !hull.Contains(v3(0.2, 0.9, 1)) || hull.Contains(v3(0.2, 0.9, 1.01)) {
+		t.Errorf("error:\nContains")
+	}
+}
+
+func TestHullRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var points []rn.Vec
+	for k := 0; k < 2000; k++ {
+		P := v3(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64())
+		points = append(points, P.Scale(1/P.Norm()))
+	}
+	points = append(points, v3(0, 0, 0), v3(0.1, 0.2, 0.3))
+	hull, err := MakeHull(points)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	// closed triangulated sphere: V - E + F = 2 with E = 3F/2
+	if V, F := len(hull.Vertices()), len(hull.Faces); V-3*F/2+F != 2 {
+		t.Errorf("error:\nV=%v F=%v is not a closed surface", V, F)
+	}
+	for _, P := range points {
+		if !hull.Contains(P) {
+			t.Errorf("error:\n%v not inside", P)
+		}
+	}
+	if got := hull.Volume(); got > 4*math.Pi/3 || got < 4 {
+		t.Errorf("error:\ngot=%v\nwant≈%v", got, 4*math.Pi/3)
+	}
+}
+
+func TestHullDegenerate(t *testing.T) {
+	for _, points := range [][]rn.Vec{
+		{v3(0, 0, 0), v3(1, 0, 0), v3(0, 1, 0)},
+		{v3(0, 0, 0), v3(1, 0, 0), v3(0, 1, 0), v3(1, 1, 0), v3(0.5, 0.5, 0)},
+		{v3(0, 0, 0), v3(1, 1, 1), v3(2, 2, 2), v3(3, 3, 3)},
+		{v3(1, 1, 1), v3(1, 1, 1), v3(1, 1, 1), v3(1, 1, 1)},
+	} {
+		if _, err := MakeHull(points); err == nil {
+			t.Errorf("error:\n%v: expected ErrDegenerate", points)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package gm
 
 import (
 	"fmt"
+	"math"
 
+	"github.com/add1609/lin/errors"
 	"github.com/add1609/lin/rn"
 )
 
@@ -75,8 +77,47 @@ func (o *Line) At(s float64) (P1 rn.Vec) {
 	return
 }
 
+// ClosestPoint returns the point of the line closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	s float64 - The parameter of the closest point
+//	P1 rn.Vec - The closest point
+func (o *Line) ClosestPoint(P rn.Vec) (s float64, P1 rn.Vec) {
+	if dd := o.V2.Dot(o.V2); dd > 0 {
+		w := P.Sub(o.V1)
+		s = o.V2.Dot(w) / dd
+	}
+	P1 = o.At(s)
+	return
+}
+
+// Dist returns the distance between P and the line
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance from P to its closest point on the line
+func (o *Line) Dist(P rn.Vec) (dist float64) {
+	_, P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
+
 // IntersectPlane returns the intersection point of a line and a plane (if it exists)
 //
+// Whether the line is parallel to the plane is decided exactly from the 3 x 3
+// minors of its direction and the plane's, not by the numerical rank that
+// Affine.Intersect uses. In dimensions other than 3 a line that is not
+// parallel to the plane meets it only if it passes within a relative tolerance.
+//
 // Parameters:
 //
 //	o *Line - The line
@@ -84,24 +125,20 @@ func (o *Line) At(s float64) (P1 rn.Vec) {
 //
 // Returns:
 //
-//	x rn.Vec - The value of λ and μ that satisfy the equation
+//	x rn.Vec - The line parameter and the values of λ and μ of the plane at the intersection point
 //	P1 rn.Vec - The intersection point
-//	err error - An error if one occurred
+//	err error - ErrParallel if the line is parallel to the plane, ErrNoIntersection if it
+//	misses the plane
 func (o *Line) IntersectPlane(plane Plane) (x, P1 rn.Vec, err error) {
-	lgs := rn.MakeMat(3, 4, 0)
-	lgs.SetCol(0, o.V2)
-	lgs.SetCol(1, plane.V2.Scale(-1))
-	lgs.SetCol(2, plane.V3.Scale(-1))
-	lgs.SetCol(3, plane.V1.Sub(o.V1))
-	_, x, err = lgs.GaussSolve()
-	if err != nil {
-		P1 = o.V1.Add(o.V2.Scale(x.Get(0)))
-	}
+	x, P1, err = intersectPlane(o.V1, o.V2, math.Inf(-1), math.Inf(1), plane)
 	return
 }
 
 // IntersectLine returns the intersection point of two lines (if it exists)
 //
+// Whether the lines are parallel is decided exactly; non-parallel lines
+// intersect if their closest points coincide within a relative tolerance.
+//
 // Parameters:
 //
 //	o *Line - The first line
@@ -109,17 +146,14 @@ func (o *Line) IntersectPlane(plane Plane) (x, P1 rn.Vec, err error) {
 //
 // Returns:
 //
-//	x rn.Vec - The value of λ and μ that satisfy the equation
+//	x rn.Vec - The values of the parameters of o and q at the intersection point
 //	P1 rn.Vec - The intersection point
-//	err error - An error if one occurred
+//	err error - ErrParallel if the lines are parallel, ErrNoIntersection if they are skew
 func (o *Line) IntersectLine(q Line) (x, P1 rn.Vec, err error) {
-	lgs := rn.MakeMat(3, 3, 0)
-	lgs.SetCol(0, o.V2)
-	lgs.SetCol(1, q.V2.Scale(-1))
-	lgs.SetCol(2, q.V1.Sub(o.V1))
-	_, x, err = lgs.GaussSolve()
-	if err != nil {
-		P1 = o.V1.Add(o.V2.Scale(x.Get(0)))
+	if parallelDirs(o.V2, q.V2) {
+		err = errors.ErrParallel
+		return
 	}
+	x, P1, err = intersectParametric(o.V1, o.V2, math.Inf(-1), math.Inf(1), q.V1, q.V2, math.Inf(-1), math.Inf(1))
 	return
 }
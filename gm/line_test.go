@@ -0,0 +1,85 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+func TestLineIntersectPlane(t *testing.T) {
+	plane := MakePlane(v3(0, 0, 0), v3(1, 0, 0), v3(0, 1, 0))
+	for _, test := range []struct {
+		line    Line
+		wantX   rn.Vec
+		wantP1  rn.Vec
+		wantErr error
+	}{
+		{MakeLine(v3(1, 2, 3), v3(0, 0, -1)), v3(3, 1, 2), v3(1, 2, 0), nil},
+		// nearly parallel lines still have a unique intersection point
+		{MakeLine(v3(0, 0, 1), v3(1, 0, 1e-20)), v3(-1e20, -1e20, 0), v3(-1e20, 0, 0), nil},
+		{MakeLine(v3(0, 0, 1), v3(1, 1, 0)), rn.Vec{}, rn.Vec{}, errors.ErrParallel},
+		{MakeLine(v3(0, 0, 0), v3(1, 1, 0)), rn.Vec{}, rn.Vec{}, errors.ErrParallel},
+	} {
+		for _, f := range []func() (x, P1 rn.Vec, err error){
+			func() (rn.Vec, rn.Vec, error) { return test.line.IntersectPlane(plane) },
+			func() (rn.Vec, rn.Vec, error) { return plane.IntersectLine(test.line) },
+		} {
+			x, P1, err := f()
+			if err != test.wantErr {
+				t.Errorf("error:\ngot=%v\nwant=%v", err, test.wantErr)
+				continue
+			}
+			if err == nil && (!vecClose(x, test.wantX, 1e-9) || !vecClose(P1, test.wantP1, 1e-9)) {
+				t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", x, P1, test.wantX, test.wantP1)
+			}
+		}
+	}
+}
+
+func TestLineIntersectTinyDirections(t *testing.T) {
+	// directions whose products underflow are still decided and solved exactly up to rounding
+	plane := MakePlane(v3(0, 0, 0), v3(1e-120, 0, 0), v3(0, 1e-120, 0))
+	line := MakeLine(v3(1, 2, 3), v3(0, 0, -1))
+	for _, f := range []func() (x, P1 rn.Vec, err error){
+		func() (rn.Vec, rn.Vec, error) { return line.IntersectPlane(plane) },
+		func() (rn.Vec, rn.Vec, error) { return plane.IntersectLine(line) },
+	} {
+		x, P1, err := f()
+		want := v3(3, 1e120, 2e120)
+		if err != nil || !vecClose(P1, v3(1, 2, 0), 1e-12) || math.Abs(x.X[0]-3) > 1e-12 ||
+			math.Abs(x.X[1]/want.X[1]-1) > 1e-12 || math.Abs(x.X[2]/want.X[2]-1) > 1e-12 {
+			t.Errorf("error:\ngot=%v, %v, %v\nwant=%v, %v", x, P1, err, want, v3(1, 2, 0))
+		}
+	}
+	a, b := MakeLine(v3(0, 0, 0), v3(1e-200, 0, 0)), MakeLine(v3(2, 1, 0), v3(0, -1e-200, 0))
+	x, P1, err := a.IntersectLine(b)
+	if err != nil || !vecClose(P1, v3(2, 0, 0), 1e-12) || math.Abs(x.X[0]/2e200-1) > 1e-12 || math.Abs(x.X[1]/1e200-1) > 1e-12 {
+		t.Errorf("error:\ngot=%v, %v, %v\nwant=%v, %v", x, P1, err, "[2e200, 1e200]", v3(2, 0, 0))
+	}
+}
+
+func TestLineIntersectLine(t *testing.T) {
+	line := MakeLine(v3(0, 0, 0), v3(1, 0, 0))
+	for _, test := range []struct {
+		q       Line
+		wantX   rn.Vec
+		wantP1  rn.Vec
+		wantErr error
+	}{
+		{MakeLine(v3(2, 1, 0), v3(0, -2, 0)), rn.Vec{N: 2, X: []float64{2, 0.5}}, v3(2, 0, 0), nil},
+		{MakeLine(v3(2, 1, 1), v3(0, -2, 0)), rn.Vec{}, rn.Vec{}, errors.ErrNoIntersection},
+		{MakeLine(v3(0, 1, 0), v3(-3, 0, 0)), rn.Vec{}, rn.Vec{}, errors.ErrParallel},
+		{MakeLine(v3(5, 0, 0), v3(2, 0, 0)), rn.Vec{}, rn.Vec{}, errors.ErrParallel},
+	} {
+		x, P1, err := line.IntersectLine(test.q)
+		if err != test.wantErr {
+			t.Errorf("error:\ngot=%v\nwant=%v", err, test.wantErr)
+			continue
+		}
+		if err == nil && (!vecClose(x, test.wantX, 1e-12) || !vecClose(P1, test.wantP1, 1e-12)) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", x, P1, test.wantX, test.wantP1)
+		}
+	}
+}
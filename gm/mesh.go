@@ -0,0 +1,332 @@
+package gm
+
+import (
+	"fmt"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Mesh is a triangle mesh in 3D with indexed faces
+type Mesh struct {
+	Vertices []rn.Vec
+	Faces    [][3]int // indices into Vertices, counterclockwise seen from outside
+}
+
+func (o Mesh) String() (str string) {
+	str += fmt.Sprintf("mesh with %d vertices and %d faces", len(o.Vertices), len(o.Faces))
+	return
+}
+
+// MakeMesh returns a Mesh object with the given vertices and faces
+//
+// Parameters:
+//
+//	vertices []rn.Vec - The vertices, all of dimension 3
+//	faces [][3]int - The triangles as indices into vertices
+//
+// Returns:
+//
+//	mesh Mesh - The mesh
+func MakeMesh(vertices []rn.Vec, faces [][3]int) (mesh Mesh) {
+	for _, v := range vertices {
+		if v.N != 3 {
+			panic(errors.ErrShape)
+		}
+	}
+	for _, f := range faces {
+		for _, i := range f {
+			if i < 0 || i >= len(vertices) {
+				panic(errors.ErrIndexOutOfRange)
+			}
+		}
+	}
+	mesh.Vertices = vertices
+	mesh.Faces = faces
+	return
+}
+
+// Triangle returns face f as a triangle
+//
+// Parameters:
+//
+//	f int - The index of the face
+//
+// Returns:
+//
+//	tri Triangle - The triangle
+func (o *Mesh) Triangle(f int) (tri Triangle) {
+	face := o.Faces[f]
+	tri = MakeTriangle(o.Vertices[face[0]], o.Vertices[face[1]], o.Vertices[face[2]])
+	return
+}
+
+// Triangles returns all faces as triangles
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	tris []Triangle - The triangles in face order
+func (o *Mesh) Triangles() (tris []Triangle) {
+	tris = make([]Triangle, len(o.Faces))
+	for f := range o.Faces {
+		tris[f] = o.Triangle(f)
+	}
+	return
+}
+
+// Primitives returns the faces as primitives for MakeBVH
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	prims []Primitive - The triangles in face order
+func (o *Mesh) Primitives() (prims []Primitive) {
+	tris := o.Triangles()
+	prims = make([]Primitive, len(tris))
+	for f := range tris {
+		prims[f] = &tris[f]
+	}
+	return
+}
+
+// faceCross returns (V2 - V1) × (V3 - V1) of face f, twice the area times the unit normal
+func (o *Mesh) faceCross(f int) rn.Vec {
+	face := o.Faces[f]
+	e1 := o.Vertices[face[1]].Sub(o.Vertices[face[0]])
+	e2 := o.Vertices[face[2]].Sub(o.Vertices[face[0]])
+	return e1.Cross(e2)
+}
+
+// FaceNormals returns the unit normals of the faces
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	ns []rn.Vec - The normals in face order, zero for degenerate faces
+func (o *Mesh) FaceNormals() (ns []rn.Vec) {
+	ns = make([]rn.Vec, len(o.Faces))
+	for f := range o.Faces {
+		ns[f] = normalize(o.faceCross(f))
+	}
+	return
+}
+
+// VertexNormals returns the area-weighted average of the normals of the faces at each vertex
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	ns []rn.Vec - The unit normals in vertex order, zero for vertices without faces
+func (o *Mesh) VertexNormals() (ns []rn.Vec) {
+	ns = make([]rn.Vec, len(o.Vertices))
+	for i := range ns {
+		ns[i] = rn.MakeVec(3, 0)
+	}
+	for f, face := range o.Faces {
+		n := o.faceCross(f)
+		for _, i := range face {
+			ns[i] = ns[i].Add(n)
+		}
+	}
+	for i := range ns {
+		ns[i] = normalize(ns[i])
+	}
+	return
+}
+
+// normalize returns v / |v|, or v if it is zero
+func normalize(v rn.Vec) rn.Vec {
+	if nrm := v.Norm(); nrm > 0 {
+		return v.Scale(1 / nrm)
+	}
+	return v
+}
+
+// SurfaceArea returns the total area of the faces
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	area float64 - The surface area
+func (o *Mesh) SurfaceArea() (area float64) {
+	for f := range o.Faces {
+		n := o.faceCross(f)
+		area += n.Norm() / 2
+	}
+	return
+}
+
+// Volume returns the volume enclosed by the mesh as the sum of signed tetrahedra
+//
+// The result is only meaningful for watertight meshes; it is positive if
+// the faces are oriented counterclockwise seen from outside.
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	vol float64 - The signed volume
+func (o *Mesh) Volume() (vol float64) {
+	if len(o.Faces) == 0 {
+		return
+	}
+	// tetrahedra are spanned from a vertex of the mesh rather than the origin to limit cancellation
+	ref := o.Vertices[o.Faces[0][0]]
+	for _, f := range o.Faces {
+		a, b, c := o.Vertices[f[0]].Sub(ref), o.Vertices[f[1]].Sub(ref), o.Vertices[f[2]].Sub(ref)
+		bc := b.Cross(c)
+		vol += a.Dot(bc)
+	}
+	vol /= 6
+	return
+}
+
+// Centroid returns the center of mass of the solid enclosed by a watertight mesh
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	C rn.Vec - The centroid
+//	err error - ErrDegenerate if the enclosed volume is zero
+func (o *Mesh) Centroid() (C rn.Vec, err error) {
+	C = rn.MakeVec(3, 0)
+	if len(o.Faces) == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	ref := o.Vertices[o.Faces[0][0]]
+	var vol float64
+	for _, f := range o.Faces {
+		a, b, c := o.Vertices[f[0]].Sub(ref), o.Vertices[f[1]].Sub(ref), o.Vertices[f[2]].Sub(ref)
+		bc := b.Cross(c)
+		v := a.Dot(bc)
+		vol += v
+		// the centroid of the tetrahedron (ref, a, b, c) relative to ref is (a + b + c) / 4
+		s := a.Add(b)
+		s = s.Add(c)
+		C = C.Add(s.Scale(v))
+	}
+	if vol == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	C = C.Scale(1 / (4 * vol))
+	C = C.Add(ref)
+	return
+}
+
+// BoundaryEdges returns the directed edges that are not matched by an edge in the opposite direction
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	edges [][2]int - The unmatched edges in face order, an edge used twice in the
+//	same direction is returned for each use
+func (o *Mesh) BoundaryEdges() (edges [][2]int) {
+	count := map[[2]int]int{}
+	for _, f := range o.Faces {
+		for k := 0; k < 3; k++ {
+			count[[2]int{f[k], f[(k+1)%3]}]++
+		}
+	}
+	for _, f := range o.Faces {
+		for k := 0; k < 3; k++ {
+			e := [2]int{f[k], f[(k+1)%3]}
+			if count[e] != 1 || count[[2]int{e[1], e[0]}] != 1 {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return
+}
+
+// IsWatertight returns true if the mesh is a closed, consistently oriented 2-manifold
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	bool - true if there are faces, none of them repeats a vertex, and every
+//	directed edge is used exactly once and its reverse exactly once
+func (o *Mesh) IsWatertight() bool {
+	if len(o.Faces) == 0 {
+		return false
+	}
+	for _, f := range o.Faces {
+		if f[0] == f[1] || f[1] == f[2] || f[2] == f[0] {
+			return false
+		}
+	}
+	return len(o.BoundaryEdges()) == 0
+}
+
+// AABB returns the bounding box of the vertices
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	box AABB - The bounding box
+func (o *Mesh) AABB() (box AABB) {
+	box = MakeAABBFromPoints(o.Vertices)
+	return
+}
+
+// Weld merges vertices with identical coordinates and drops vertices that no face uses
+//
+// Parameters:
+//
+//	o *Mesh - The mesh
+//
+// Returns:
+//
+//	mesh Mesh - A new mesh with the same faces, the vertices in order of first use
+func (o *Mesh) Weld() (mesh Mesh) {
+	index := map[[3]float64]int{}
+	mesh.Faces = make([][3]int, len(o.Faces))
+	for f, face := range o.Faces {
+		for k, i := range face {
+			mesh.Faces[f][k] = mesh.addVertex(o.Vertices[i], index)
+		}
+	}
+	return
+}
+
+// addVertex returns the index of P, appending it unless index already holds it
+func (o *Mesh) addVertex(P rn.Vec, index map[[3]float64]int) int {
+	key := vec3(P)
+	if i, ok := index[key]; ok {
+		return i
+	}
+	index[key] = len(o.Vertices)
+	o.Vertices = append(o.Vertices, P)
+	return index[key]
+}
@@ -0,0 +1,219 @@
+package gm
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// unitCube returns the closed unit cube with outward facing triangles
+func unitCube() Mesh {
+	var vertices []rn.Vec
+	for i := 0; i < 8; i++ {
+		vertices = append(vertices, v3(float64(i&1), float64(i>>1&1), float64(i>>2&1)))
+	}
+	faces := [][3]int{
+		{0, 2, 1}, {1, 2, 3}, // z = 0
+		{4, 5, 6}, {5, 7, 6}, // z = 1
+		{0, 1, 4}, {1, 5, 4}, // y = 0
+		{2, 6, 3}, {3, 6, 7}, // y = 1
+		{0, 4, 2}, {2, 4, 6}, // x = 0
+		{1, 3, 5}, {3, 7, 5}, // x = 1
+	}
+	return MakeMesh(vertices, faces)
+}
+
+func TestMeshProperties(t *testing.T) {
+	cube := unitCube()
+	if got := cube.SurfaceArea(); math.Abs(got-6) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 6)
+	}
+	if got := cube.Volume(); math.Abs(got-1) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 1)
+	}
+	C, err := cube.Centroid()
+	if err != nil || !vecClose(C, v3(0.5, 0.5, 0.5), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", C, err, v3(0.5, 0.5, 0.5))
+	}
+	if !cube.IsWatertight() {
+		t.Errorf("error:\ngot=%v\nwant=%v", false, true)
+	}
+	ns := cube.VertexNormals()
+	want := v3(1, 1, 1)
+	want = want.Scale(1 / math.Sqrt(3))
+	if !vecClose(ns[7], want, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", ns[7], want)
+	}
+
+	open := MakeMesh(cube.Vertices, cube.Faces[2:])
+	if open.IsWatertight() {
+		t.Errorf("error:\ngot=%v\nwant=%v", true, false)
+	}
+	if got := len(open.BoundaryEdges()); got != 4 {
+		t.Errorf("error:\ngot=%v boundary edges\nwant=%v", got, 4)
+	}
+
+	flipped := MakeMesh(cube.Vertices, append([][3]int{}, cube.Faces...))
+	flipped.Faces[0] = [3]int{0, 1, 2}
+	if flipped.IsWatertight() {
+		t.Errorf("error:\ngot=%v\nwant=%v", true, false)
+	}
+}
+
+func TestMeshWeld(t *testing.T) {
+	// two triangles of a square with the shared corners duplicated
+	mesh := MakeMesh([]rn.Vec{v3(0, 0, 0), v3(1, 0, 0), v3(1, 1, 0), v3(0, 0, 0), v3(1, 1, 0), v3(0, 1, 0), v3(5, 5, 5)},
+		[][3]int{{0, 1, 2}, {3, 4, 5}})
+	welded := mesh.Weld()
+	if got := len(welded.Vertices); got != 4 {
+		t.Errorf("error:\ngot=%v vertices\nwant=%v", got, 4)
+	}
+	want := [][3]int{{0, 1, 2}, {0, 2, 3}}
+	for f := range want {
+		if welded.Faces[f] != want[f] {
+			t.Errorf("error:\ngot=%v\nwant=%v", welded.Faces[f], want[f])
+		}
+	}
+}
+
+// sameMesh returns true if a and b have the same faces and vertices within tol
+func sameMesh(a, b Mesh, tol float64) bool {
+	if len(a.Vertices) != len(b.Vertices) || len(a.Faces) != len(b.Faces) {
+		return false
+	}
+	for i := range a.Vertices {
+		if !vecClose(a.Vertices[i], b.Vertices[i], tol) {
+			return false
+		}
+	}
+	for f := range a.Faces {
+		if a.Faces[f] != b.Faces[f] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMeshRoundTrip(t *testing.T) {
+	cube := unitCube()
+	// STL stores triangle soups, welding recovers vertices in order of first use
+	welded := cube.Weld()
+	tests := []struct {
+		name  string
+		write func(*bytes.Buffer) error
+		read  func(*bytes.Buffer) (Mesh, error)
+		want  Mesh
+	}{
+		{"obj", func(b *bytes.Buffer) error { return WriteOBJ(b, cube) }, func(b *bytes.Buffer) (Mesh, error) { return ReadOBJ(b) }, cube},
+		{"stl ascii", func(b *bytes.Buffer) error { return WriteSTL(b, cube, false) }, func(b *bytes.Buffer) (Mesh, error) { return ReadSTL(b) }, welded},
+		{"stl binary", func(b *bytes.Buffer) error { return WriteSTL(b, cube, true) }, func(b *bytes.Buffer) (Mesh, error) { return ReadSTL(b) }, welded},
+		{"ply ascii", func(b *bytes.Buffer) error { return WritePLY(b, cube, false) }, func(b *bytes.Buffer) (Mesh, error) { return ReadPLY(b) }, cube},
+		{"ply binary", func(b *bytes.Buffer) error { return WritePLY(b, cube, true) }, func(b *bytes.Buffer) (Mesh, error) { return ReadPLY(b) }, cube},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := test.write(&buf); err != nil {
+			t.Fatalf("error:\n%v: %v\n", test.name, err)
+		}
+		got, err := test.read(&buf)
+		if err != nil || !sameMesh(got, test.want, 0) {
+			t.Errorf("error:\n%v\ngot=%v, %v\nwant=%v", test.name, got, err, test.want)
+		}
+		if !got.IsWatertight() {
+			t.Errorf("error:\n%v is not watertight", test.name)
+		}
+	}
+}
+
+func TestMeshRead(t *testing.T) {
+	obj := `# a unit square as one quad, and a triangle with relative indices
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vn 0 0 1
+f 1//1 2//1 3/1/1 4
+v 0 0 1
+f -1 -5 -4
+`
+	mesh, err := ReadOBJ(strings.NewReader(obj))
+	want := [][3]int{{0, 1, 2}, {0, 2, 3}, {4, 0, 1}}
+	if err != nil || len(mesh.Faces) != len(want) {
+		t.Fatalf("error:\ngot=%v, %v\nwant=%v", mesh.Faces, err, want)
+	}
+	for f := range want {
+		if mesh.Faces[f] != want[f] {
+			t.Errorf("error:\ngot=%v\nwant=%v", mesh.Faces[f], want[f])
+		}
+	}
+
+	ply := `ply
+format ascii 1.0
+comment with an extra property and element
+element vertex 4
+property float x
+property float y
+property float z
+property uchar red
+element face 1
+property list uchar int vertex_indices
+element edge 1
+property int vertex1
+property int vertex2
+end_header
+0 0 0 255
+1 0 0 255
+1 1 0 255
+0 1 0 255
+4 0 1 2 3
+0 1
+`
+	mesh, err = ReadPLY(strings.NewReader(ply))
+	if err != nil || len(mesh.Vertices) != 4 || len(mesh.Faces) != 2 || mesh.SurfaceArea() != 1 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", mesh, err, "4 vertices, 2 faces")
+	}
+
+	// a binary STL whose header starts with "solid" is still read as binary
+	var buf bytes.Buffer
+	if err := WriteSTL(&buf, unitCube(), true); err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	data := buf.Bytes()
+	copy(data, "solid cube")
+	mesh, err = ReadSTL(bytes.NewReader(data))
+	if err != nil || len(mesh.Faces) != 12 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", mesh, err, "12 faces")
+	}
+
+	bad := []string{"v 1 2\n", "v 0 0 0\nf 1 2 3\n", "f 1 x 2\n"}
+	for _, s := range bad {
+		if _, err := ReadOBJ(strings.NewReader(s)); err == nil {
+			t.Errorf("error:\n%q\ngot=%v\nwant=%v", s, err, "ErrFormat")
+		}
+	}
+	if _, err := ReadSTL(strings.NewReader("not an stl")); err == nil {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, "ErrFormat")
+	}
+	header := "ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n0\n1\n2\n"
+	badPLY := []string{
+		"ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nend_header\n",
+		header + "-1 0 1 2\n",
+		header + "3.5 0 1 2\n",
+		header + "2000000000 0 1 2\n",
+		header + "3 0 1.7 2\n",
+		header + "3 0 1 3\n",
+		header + "3 0 -1 2\n",
+	}
+	for _, s := range badPLY {
+		if _, err := ReadPLY(strings.NewReader(s)); err != errors.ErrFormat {
+			t.Errorf("error:\n%q\ngot=%v\nwant=%v", s, err, errors.ErrFormat)
+		}
+	}
+	if mesh, err := ReadPLY(strings.NewReader(header + "3 0 1 2\n")); err != nil || len(mesh.Faces) != 1 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", mesh, err, "1 face")
+	}
+}
@@ -0,0 +1,524 @@
+package gm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// ReadOBJ reads a mesh from a Wavefront OBJ file
+//
+// Only vertex positions (v) and faces (f) are read; polygonal faces are
+// split into triangle fans. Face indices may use the v/vt/vn forms and
+// negative (relative) indices. All other statements are ignored.
+//
+// Parameters:
+//
+//	r io.Reader - The input
+//
+// Returns:
+//
+//	mesh Mesh - The mesh
+//	err error - ErrFormat if the file is malformed, or the error of r
+func ReadOBJ(r io.Reader) (mesh Mesh, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			if len(fields) < 4 {
+				err = errors.ErrFormat
+				return
+			}
+			P := rn.MakeVec(3, 0)
+			for i := 0; i < 3; i++ {
+				if P.X[i], err = strconv.ParseFloat(fields[i+1], 64); err != nil {
+					err = errors.ErrFormat
+					return
+				}
+			}
+			mesh.Vertices = append(mesh.Vertices, P)
+		case "f":
+			if len(fields) < 4 {
+				err = errors.ErrFormat
+				return
+			}
+			idx := make([]int, len(fields)-1)
+			for k, field := range fields[1:] {
+				// only the vertex index before the first slash matters
+				i, perr := strconv.Atoi(strings.SplitN(field, "/", 2)[0])
+				switch {
+				case perr != nil || i == 0:
+					err = errors.ErrFormat
+					return
+				case i < 0:
+					i += len(mesh.Vertices)
+				default:
+					i--
+				}
+				if i < 0 || i >= len(mesh.Vertices) {
+					err = errors.ErrFormat
+					return
+				}
+				idx[k] = i
+			}
+			for k := 1; k+1 < len(idx); k++ {
+				mesh.Faces = append(mesh.Faces, [3]int{idx[0], idx[k], idx[k+1]})
+			}
+		}
+	}
+	err = sc.Err()
+	return
+}
+
+// WriteOBJ writes a mesh as a Wavefront OBJ file
+//
+// Parameters:
+//
+//	w io.Writer - The output
+//	mesh Mesh - The mesh
+//
+// Returns:
+//
+//	err error - The error of w, if any
+func WriteOBJ(w io.Writer, mesh Mesh) (err error) {
+	bw := bufio.NewWriter(w)
+	for _, P := range mesh.Vertices {
+		fmt.Fprintf(bw, "v %s %s %s\n", formatFloat(P.X[0]), formatFloat(P.X[1]), formatFloat(P.X[2]))
+	}
+	for _, f := range mesh.Faces {
+		fmt.Fprintf(bw, "f %d %d %d\n", f[0]+1, f[1]+1, f[2]+1)
+	}
+	err = bw.Flush()
+	return
+}
+
+// formatFloat returns the shortest representation of x that parses back to x
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+// ReadSTL reads a mesh from an ASCII or binary STL file
+//
+// STL stores every triangle with its own corners, so corners with identical
+// coordinates are merged into one vertex. The stored normals are ignored.
+//
+// Parameters:
+//
+//	r io.Reader - The input
+//
+// Returns:
+//
+//	mesh Mesh - The mesh
+//	err error - ErrFormat if the file is malformed, or the error of r
+func ReadSTL(r io.Reader) (mesh Mesh, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return
+	}
+	// binary files may also start with "solid", so the size decides
+	if len(data) >= 84 {
+		n := binary.LittleEndian.Uint32(data[80:84])
+		if uint64(len(data)) == 84+50*uint64(n) {
+			mesh = readSTLBinary(data[84:], int(n))
+			return
+		}
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		err = errors.ErrFormat
+		return
+	}
+	mesh, err = readSTLASCII(data)
+	return
+}
+
+func readSTLBinary(data []byte, n int) (mesh Mesh) {
+	index := map[[3]float64]int{}
+	mesh.Faces = make([][3]int, n)
+	for t := 0; t < n; t++ {
+		rec := data[50*t : 50*t+50]
+		for k := 0; k < 3; k++ {
+			P := rn.MakeVec(3, 0)
+			for i := 0; i < 3; i++ {
+				bits := binary.LittleEndian.Uint32(rec[12+12*k+4*i:])
+				P.X[i] = float64(math.Float32frombits(bits))
+			}
+			mesh.Faces[t][k] = mesh.addVertex(P, index)
+		}
+	}
+	return
+}
+
+func readSTLASCII(data []byte) (mesh Mesh, err error) {
+	index := map[[3]float64]int{}
+	var corners []int
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "vertex":
+			if len(fields) != 4 {
+				err = errors.ErrFormat
+				return
+			}
+			P := rn.MakeVec(3, 0)
+			for i := 0; i < 3; i++ {
+				if P.X[i], err = strconv.ParseFloat(fields[i+1], 64); err != nil {
+					err = errors.ErrFormat
+					return
+				}
+			}
+			corners = append(corners, mesh.addVertex(P, index))
+		case "endloop":
+			if len(corners) != 3 {
+				err = errors.ErrFormat
+				return
+			}
+			mesh.Faces = append(mesh.Faces, [3]int{corners[0], corners[1], corners[2]})
+			corners = corners[:0]
+		}
+	}
+	err = sc.Err()
+	return
+}
+
+// WriteSTL writes a mesh as an STL file
+//
+// Parameters:
+//
+//	w io.Writer - The output
+//	mesh Mesh - The mesh
+//	binaryFormat bool - true for binary STL with float32 coordinates, false for ASCII STL
+//
+// Returns:
+//
+//	err error - The error of w, if any
+func WriteSTL(w io.Writer, mesh Mesh, binaryFormat bool) (err error) {
+	ns := mesh.FaceNormals()
+	bw := bufio.NewWriter(w)
+	if binaryFormat {
+		var header [84]byte
+		copy(header[:], "binary STL written by lin")
+		binary.LittleEndian.PutUint32(header[80:], uint32(len(mesh.Faces)))
+		bw.Write(header[:])
+		var rec [50]byte
+		for f, face := range mesh.Faces {
+			for i := 0; i < 3; i++ {
+				binary.LittleEndian.PutUint32(rec[4*i:], math.Float32bits(float32(ns[f].X[i])))
+			}
+			for k, v := range face {
+				for i := 0; i < 3; i++ {
+					binary.LittleEndian.PutUint32(rec[12+12*k+4*i:], math.Float32bits(float32(mesh.Vertices[v].X[i])))
+				}
+			}
+			bw.Write(rec[:])
+		}
+		err = bw.Flush()
+		return
+	}
+	fmt.Fprintln(bw, "solid mesh")
+	for f, face := range mesh.Faces {
+		n := ns[f]
+		fmt.Fprintf(bw, "  facet normal %s %s %s\n    outer loop\n", formatFloat(n.X[0]), formatFloat(n.X[1]), formatFloat(n.X[2]))
+		for _, v := range face {
+			P := mesh.Vertices[v]
+			fmt.Fprintf(bw, "      vertex %s %s %s\n", formatFloat(P.X[0]), formatFloat(P.X[1]), formatFloat(P.X[2]))
+		}
+		fmt.Fprintf(bw, "    endloop\n  endfacet\n")
+	}
+	fmt.Fprintln(bw, "endsolid mesh")
+	err = bw.Flush()
+	return
+}
+
+// plyProperty is a scalar or list property of a PLY element
+type plyProperty struct {
+	name      string
+	typ       string
+	countType string // the type of the list length, empty for scalar properties
+}
+
+type plyElement struct {
+	name  string
+	count int
+	props []plyProperty
+}
+
+// plySizes maps the PLY scalar types to their sizes in bytes
+var plySizes = map[string]int{
+	"char": 1, "uchar": 1, "short": 2, "ushort": 2, "int": 4, "uint": 4, "float": 4, "double": 8,
+	"int8": 1, "uint8": 1, "int16": 2, "uint16": 2, "int32": 4, "uint32": 4, "float32": 4, "float64": 8,
+}
+
+// plyScanner reads the scalars of the body of a PLY file one at a time
+type plyScanner struct {
+	br    *bufio.Reader
+	words *bufio.Scanner // nil for binary files
+	order binary.ByteOrder
+}
+
+func (o *plyScanner) next(typ string) (x float64, err error) {
+	if o.words != nil {
+		if !o.words.Scan() {
+			err = errors.ErrFormat
+			return
+		}
+		if x, err = strconv.ParseFloat(o.words.Text(), 64); err != nil {
+			err = errors.ErrFormat
+		}
+		return
+	}
+	var buf [8]byte
+	b := buf[:plySizes[typ]]
+	if _, err = io.ReadFull(o.br, b); err != nil {
+		err = errors.ErrFormat
+		return
+	}
+	switch typ {
+	case "char", "int8":
+		x = float64(int8(b[0]))
+	case "uchar", "uint8":
+		x = float64(b[0])
+	case "short", "int16":
+		x = float64(int16(o.order.Uint16(b)))
+	case "ushort", "uint16":
+		x = float64(o.order.Uint16(b))
+	case "int", "int32":
+		x = float64(int32(o.order.Uint32(b)))
+	case "uint", "uint32":
+		x = float64(o.order.Uint32(b))
+	case "float", "float32":
+		x = float64(math.Float32frombits(o.order.Uint32(b)))
+	case "double", "float64":
+		x = math.Float64frombits(o.order.Uint64(b))
+	}
+	return
+}
+
+// ReadPLY reads a mesh from an ASCII or binary PLY file
+//
+// The x, y and z properties of the vertex element and the vertex_indices
+// (or vertex_index) list of the face element are read; polygonal faces are
+// split into triangle fans. Other elements and properties are skipped.
+//
+// Parameters:
+//
+//	r io.Reader - The input
+//
+// Returns:
+//
+//	mesh Mesh - The mesh
+//	err error - ErrFormat if the file is malformed, or the error of r
+func ReadPLY(r io.Reader) (mesh Mesh, err error) {
+	br := bufio.NewReader(r)
+	elements, sc, err := readPLYHeader(br)
+	if err != nil {
+		return
+	}
+	nVertices := 0
+	for _, el := range elements {
+		if el.name == "vertex" {
+			nVertices += el.count
+		}
+	}
+	for _, el := range elements {
+		for n := 0; n < el.count; n++ {
+			P := rn.MakeVec(3, 0)
+			var face []int
+			for _, prop := range el.props {
+				if prop.countType == "" {
+					var x float64
+					if x, err = sc.next(prop.typ); err != nil {
+						return
+					}
+					if k := strings.Index("xyz", prop.name); el.name == "vertex" && len(prop.name) == 1 && k >= 0 {
+						P.X[k] = x
+					}
+					continue
+				}
+				var cnt float64
+				if cnt, err = sc.next(prop.countType); err != nil {
+					return
+				}
+				if !(cnt >= 0 && cnt <= math.MaxInt32) || cnt != math.Trunc(cnt) {
+					err = errors.ErrFormat
+					return
+				}
+				indices := el.name == "face" && (prop.name == "vertex_indices" || prop.name == "vertex_index")
+				// the list grows as it is read, so a bogus count fails at the end
+				// of the data instead of allocating up front
+				var list []int
+				for k := 0; k < int(cnt); k++ {
+					var x float64
+					if x, err = sc.next(prop.typ); err != nil {
+						return
+					}
+					if !indices {
+						continue
+					}
+					if !(x >= 0 && x < float64(nVertices)) || x != math.Trunc(x) {
+						err = errors.ErrFormat
+						return
+					}
+					list = append(list, int(x))
+				}
+				if indices {
+					face = list
+				}
+			}
+			switch el.name {
+			case "vertex":
+				mesh.Vertices = append(mesh.Vertices, P)
+			case "face":
+				for k := 1; k+1 < len(face); k++ {
+					mesh.Faces = append(mesh.Faces, [3]int{face[0], face[k], face[k+1]})
+				}
+			}
+		}
+	}
+	for _, f := range mesh.Faces {
+		for _, i := range f {
+			if i < 0 || i >= len(mesh.Vertices) {
+				err = errors.ErrFormat
+				return
+			}
+		}
+	}
+	return
+}
+
+// readPLYHeader parses the header of a PLY file and returns a scanner positioned at the body
+func readPLYHeader(br *bufio.Reader) (elements []plyElement, sc plyScanner, err error) {
+	sc.br = br
+	line, err := br.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "ply" {
+		err = errors.ErrFormat
+		return
+	}
+	format := ""
+	for {
+		if line, err = br.ReadString('\n'); err != nil {
+			err = errors.ErrFormat
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				err = errors.ErrFormat
+				return
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				err = errors.ErrFormat
+				return
+			}
+			el := plyElement{name: fields[1]}
+			if el.count, err = strconv.Atoi(fields[2]); err != nil || el.count < 0 {
+				err = errors.ErrFormat
+				return
+			}
+			elements = append(elements, el)
+		case "property":
+			if len(elements) == 0 {
+				err = errors.ErrFormat
+				return
+			}
+			var prop plyProperty
+			switch {
+			case len(fields) == 3:
+				prop = plyProperty{name: fields[2], typ: fields[1]}
+			case len(fields) == 5 && fields[1] == "list":
+				prop = plyProperty{name: fields[4], typ: fields[3], countType: fields[2]}
+			default:
+				err = errors.ErrFormat
+				return
+			}
+			if plySizes[prop.typ] == 0 || prop.countType != "" && plySizes[prop.countType] == 0 {
+				err = errors.ErrFormat
+				return
+			}
+			el := &elements[len(elements)-1]
+			el.props = append(el.props, prop)
+		case "end_header":
+			switch format {
+			case "ascii":
+				sc.words = bufio.NewScanner(br)
+				sc.words.Split(bufio.ScanWords)
+			case "binary_little_endian":
+				sc.order = binary.LittleEndian
+			case "binary_big_endian":
+				sc.order = binary.BigEndian
+			default:
+				err = errors.ErrFormat
+			}
+			return
+		}
+	}
+}
+
+// WritePLY writes a mesh as a PLY file with double precision coordinates
+//
+// Parameters:
+//
+//	w io.Writer - The output
+//	mesh Mesh - The mesh
+//	binaryFormat bool - true for binary_little_endian, false for ascii
+//
+// Returns:
+//
+//	err error - The error of w, if any
+func WritePLY(w io.Writer, mesh Mesh, binaryFormat bool) (err error) {
+	bw := bufio.NewWriter(w)
+	format := "ascii"
+	if binaryFormat {
+		format = "binary_little_endian"
+	}
+	fmt.Fprintf(bw, "ply\nformat %s 1.0\n", format)
+	fmt.Fprintf(bw, "element vertex %d\nproperty double x\nproperty double y\nproperty double z\n", len(mesh.Vertices))
+	fmt.Fprintf(bw, "element face %d\nproperty list uchar int vertex_indices\nend_header\n", len(mesh.Faces))
+	if binaryFormat {
+		var buf [13]byte
+		for _, P := range mesh.Vertices {
+			for i := 0; i < 3; i++ {
+				binary.LittleEndian.PutUint64(buf[:8], math.Float64bits(P.X[i]))
+				bw.Write(buf[:8])
+			}
+		}
+		for _, f := range mesh.Faces {
+			buf[0] = 3
+			for k := 0; k < 3; k++ {
+				binary.LittleEndian.PutUint32(buf[1+4*k:], uint32(int32(f[k])))
+			}
+			bw.Write(buf[:13])
+		}
+		err = bw.Flush()
+		return
+	}
+	for _, P := range mesh.Vertices {
+		fmt.Fprintf(bw, "%s %s %s\n", formatFloat(P.X[0]), formatFloat(P.X[1]), formatFloat(P.X[2]))
+	}
+	for _, f := range mesh.Faces {
+		fmt.Fprintf(bw, "3 %d %d %d\n", f[0], f[1], f[2])
+	}
+	err = bw.Flush()
+	return
+}
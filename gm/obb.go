@@ -0,0 +1,408 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// OBB is an oriented bounding box with center C, orthonormal axes (the columns of Axes)
+// and half extents E along those axes
+type OBB struct {
+	C    rn.Vec
+	Axes rn.Mat
+	E    rn.Vec
+}
+
+func (o OBB) String() (str string) {
+	str += fmt.Sprintf("C = %v, E = %v, axes =\n%v", o.C, o.E, o.Axes)
+	return
+}
+
+// MakeOBB returns an OBB object with center C, axes Axes and half extents E
+//
+// Parameters:
+//
+//	C rn.Vec - The center
+//	Axes rn.Mat - The orthonormal box axes as columns
+//	E rn.Vec - The half extents along the axes
+//
+// Returns:
+//
+//	box OBB - The oriented box
+func MakeOBB(C rn.Vec, Axes rn.Mat, E rn.Vec) (box OBB) {
+	if Axes.M != C.N || Axes.N != C.N || E.N != C.N {
+		panic(errors.ErrShape)
+	}
+	box.C = C
+	box.Axes = Axes
+	box.E = E
+	return
+}
+
+// MakeOBBFromPoints fits an oriented box to a point set using its principal axes
+//
+// The axes are the eigenvectors of the covariance matrix of the points;
+// in 3D they form a right-handed frame.
+//
+// Parameters:
+//
+//	Ps []rn.Vec - The points, at least one
+//
+// Returns:
+//
+//	box OBB - A box that contains all points and is aligned with their principal axes
+func MakeOBBFromPoints(Ps []rn.Vec) (box OBB) {
+	if len(Ps) == 0 {
+		panic(errors.ErrZeroLengthVec)
+	}
+	mean, cov := covariance(Ps)
+	_, axes, _ := cov.SymEigen()
+	if axes.N == 3 && axes.Det() < 0 {
+		last := axes.GetCol(2)
+		axes.SetCol(2, last.Scale(-1))
+	}
+	axesT := axes.Transpose()
+	local := make([]rn.Vec, len(Ps))
+	for k, P := range Ps {
+		w := P.Sub(mean)
+		local[k] = axesT.MulVec(w)
+	}
+	bounds := MakeAABBFromPoints(local)
+	mid, E := bounds.Center(), bounds.Extents()
+	offset := axes.MulVec(mid)
+	box = MakeOBB(mean.Add(offset), axes, E)
+	return
+}
+
+// covariance returns the mean and the covariance matrix of a point set
+func covariance(Ps []rn.Vec) (mean rn.Vec, cov rn.Mat) {
+	n := Ps[0].N
+	mean = rn.MakeVec(n, 0)
+	for _, P := range Ps {
+		mean = mean.Add(P)
+	}
+	mean = mean.Scale(1 / float64(len(Ps)))
+	cov = rn.MakeMat(n, n, 0)
+	for _, P := range Ps {
+		w := P.Sub(mean)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				cov.Data[i+j*n] += w.X[i] * w.X[j]
+			}
+		}
+	}
+	for k := range cov.Data {
+		cov.Data[k] /= float64(len(Ps))
+	}
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *OBB - box to compare to q
+//	q OBB - box to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *OBB) Equal(q OBB) bool {
+	if !o.C.Equal(q.C) || !o.E.Equal(q.E) || o.Axes.M != q.Axes.M || o.Axes.N != q.Axes.N {
+		return false
+	}
+	for k := range o.Axes.Data {
+		if o.Axes.Data[k] != q.Axes.Data[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// Local returns the coordinates of P in the frame of the box
+//
+// Parameters:
+//
+//	P rn.Vec - A point in world coordinates
+//
+// Returns:
+//
+//	L rn.Vec - The coordinates of P along the box axes relative to the center
+func (o *OBB) Local(P rn.Vec) (L rn.Vec) {
+	axesT := o.Axes.Transpose()
+	w := P.Sub(o.C)
+	L = axesT.MulVec(w)
+	return
+}
+
+// World returns the world coordinates of a point given in the frame of the box
+//
+// Parameters:
+//
+//	L rn.Vec - A point in box coordinates
+//
+// Returns:
+//
+//	P rn.Vec - The point in world coordinates
+func (o *OBB) World(L rn.Vec) (P rn.Vec) {
+	P = o.Axes.MulVec(L)
+	P = o.C.Add(P)
+	return
+}
+
+func (o *OBB) localBox() AABB {
+	return AABB{Min: o.E.Scale(-1), Max: o.E}
+}
+
+// Volume returns the product of the side lengths
+//
+// Parameters:
+//
+//	o *OBB - The box
+//
+// Returns:
+//
+//	vol float64 - The volume (area in 2D)
+func (o *OBB) Volume() (vol float64) {
+	box := o.localBox()
+	vol = box.Volume()
+	return
+}
+
+// Corners returns the 2ⁿ corners of the box
+//
+// Parameters:
+//
+//	o *OBB - The box
+//
+// Returns:
+//
+//	Ps []rn.Vec - The corners, bit i of the index selects the sign along axis i
+func (o *OBB) Corners() (Ps []rn.Vec) {
+	n := o.C.N
+	Ps = make([]rn.Vec, 1<<n)
+	for k := range Ps {
+		L := rn.MakeVec(n, 0)
+		for i := 0; i < n; i++ {
+			if k&(1<<i) != 0 {
+				L.X[i] = o.E.X[i]
+			} else {
+				L.X[i] = -o.E.X[i]
+			}
+		}
+		Ps[k] = o.World(L)
+	}
+	return
+}
+
+// AABB returns the smallest axis-aligned box that contains the oriented box
+//
+// Parameters:
+//
+//	o *OBB - The box
+//
+// Returns:
+//
+//	box AABB - The axis-aligned bounds
+func (o *OBB) AABB() (box AABB) {
+	n := o.C.N
+	r := rn.MakeVec(n, 0)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			r.X[i] += math.Abs(o.Axes.Get(i, j)) * o.E.X[j]
+		}
+	}
+	box = AABB{Min: o.C.Sub(r), Max: o.C.Add(r)}
+	return
+}
+
+// Contains returns true if P lies inside or on the box
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if P lies in the box
+func (o *OBB) Contains(P rn.Vec) bool {
+	L := o.Local(P)
+	tol := tolerance(o.C, P)
+	for i := 0; i < L.N; i++ {
+		if math.Abs(L.X[i]) > o.E.X[i]+tol {
+			return false
+		}
+	}
+	return true
+}
+
+// ClosestPoint returns the point of the box closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	P1 rn.Vec - The closest point inside or on the box
+func (o *OBB) ClosestPoint(P rn.Vec) (P1 rn.Vec) {
+	box := o.localBox()
+	L := box.ClosestPoint(o.Local(P))
+	P1 = o.World(L)
+	return
+}
+
+// Dist returns the distance between P and the box
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance, 0 if P lies inside
+func (o *OBB) Dist(P rn.Vec) (dist float64) {
+	P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
+
+// slab clips p + t*d against the box in its local frame, the parameters are the same in both frames
+func (o *OBB) slab(p, d rn.Vec, tMin, tMax float64) (x, P1 rn.Vec, err error) {
+	axesT := o.Axes.Transpose()
+	box := o.localBox()
+	if x, _, err = box.slab(o.Local(p), axesT.MulVec(d), tMin, tMax); err != nil {
+		return
+	}
+	P1 = p.Add(d.Scale(x.X[0]))
+	return
+}
+
+// IntersectRay returns where a ray enters and leaves the box
+//
+// Parameters:
+//
+//	o *OBB - The box
+//	q Ray - The ray
+//
+// Returns:
+//
+//	x rn.Vec - The ray parameters at which the ray enters and leaves the box,
+//	the entry parameter is 0 if the ray starts inside
+//	P1 rn.Vec - The entry point
+//	err error - ErrNoIntersection if the ray misses the box
+func (o *OBB) IntersectRay(q Ray) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.slab(q.V1, q.V2, 0, math.Inf(1))
+	return
+}
+
+// IntersectLine returns where a line enters and leaves the box
+//
+// Parameters:
+//
+//	o *OBB - The box
+//	q Line - The line
+//
+// Returns:
+//
+//	x rn.Vec - The line parameters at which the line enters and leaves the box
+//	P1 rn.Vec - The entry point
+//	err error - ErrNoIntersection if the line misses the box
+func (o *OBB) IntersectLine(q Line) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.slab(q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectSegment returns where a segment enters and leaves the box
+//
+// Parameters:
+//
+//	o *OBB - The box
+//	q Segment - The segment
+//
+// Returns:
+//
+//	x rn.Vec - The segment parameters in [0, 1] of the part inside the box
+//	P1 rn.Vec - The entry point
+//	err error - ErrNoIntersection if the segment misses the box
+func (o *OBB) IntersectSegment(q Segment) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.slab(q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// ClassifyPlane returns on which side of a plane the box lies
+//
+// Parameters:
+//
+//	plane Plane - The plane
+//
+// Returns:
+//
+//	side Side - Front or Back if the box lies entirely on one side, Straddle otherwise
+func (o *OBB) ClassifyPlane(plane Plane) (side Side) {
+	n := plane.Normal()
+	var r float64
+	for j := 0; j < o.E.N; j++ {
+		axis := o.Axes.GetCol(j)
+		r += o.E.X[j] * math.Abs(n.Dot(axis))
+	}
+	side = classifyRadius(plane.SignedDist(o.C), r)
+	return
+}
+
+// Overlaps returns true if two 3D oriented boxes intersect (separating axis test)
+//
+// Parameters:
+//
+//	o *OBB - The first box
+//	q OBB - The second box
+//
+// Returns:
+//
+//	bool - true if the boxes overlap or touch
+func (o *OBB) Overlaps(q OBB) bool {
+	if o.C.N != 3 || q.C.N != 3 {
+		panic(errors.ErrShape)
+	}
+	// express q in the frame of o, see Ericson, Real-Time Collision Detection, 4.4.1
+	axesT := o.Axes.Transpose()
+	R := axesT.Mul(q.Axes)
+	t := o.Local(q.C)
+	var absR [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			// the epsilon counters arithmetic errors when two edges are parallel
+			absR[i][j] = math.Abs(R.Get(i, j)) + eps
+		}
+	}
+	a, b := o.E.X, q.E.X
+	for i := 0; i < 3; i++ {
+		ra := a[i]
+		rb := b[0]*absR[i][0] + b[1]*absR[i][1] + b[2]*absR[i][2]
+		if math.Abs(t.X[i]) > ra+rb {
+			return false
+		}
+	}
+	for j := 0; j < 3; j++ {
+		ra := a[0]*absR[0][j] + a[1]*absR[1][j] + a[2]*absR[2][j]
+		rb := b[j]
+		if math.Abs(t.X[0]*R.Get(0, j)+t.X[1]*R.Get(1, j)+t.X[2]*R.Get(2, j)) > ra+rb {
+			return false
+		}
+	}
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra := a[i1]*absR[i2][j] + a[i2]*absR[i1][j]
+			rb := b[j1]*absR[i][j2] + b[j2]*absR[i][j1]
+			if math.Abs(t.X[i2]*R.Get(i1, j)-t.X[i1]*R.Get(i2, j)) > ra+rb {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func TestMakeOBBFromPoints(t *testing.T) {
+	rot := MakeRotationEuler(v3(0.3, -0.5, 1.2), ZYX, Intrinsic)
+	want := MakeOBB(v3(1, 2, 3), rot, v3(4, 2, 1))
+	Ps := want.Corners()
+	box := MakeOBBFromPoints(Ps)
+	if math.Abs(box.Volume()-want.Volume()) > 1e-9 || !vecClose(box.C, want.C, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", box, want)
+	}
+	for _, P := range Ps {
+		if !box.Contains(P) {
+			t.Errorf("error:\n%v not in %v", P, box)
+		}
+	}
+	if box.Axes.Det() < 0 {
+		t.Errorf("error:\naxes are not right-handed")
+	}
+}
+
+func TestOBBQueries(t *testing.T) {
+	rot := MakeRotation(2, math.Pi/4)
+	box := MakeOBB(v3(0, 0, 0), rot, v3(1, 1, 1))
+	if !box.Contains(v3(1.4, 0, 0)) || box.Contains(v3(1, 1, 0)) {
+		t.Errorf("error:\nContains")
+	}
+	if got, want := box.ClosestPoint(v3(3, 0, 0)), v3(math.Sqrt2, 0, 0); !vecClose(got, want, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	bounds := box.AABB()
+	if want := MakeAABB(v3(-math.Sqrt2, -math.Sqrt2, -1), v3(math.Sqrt2, math.Sqrt2, 1)); !vecClose(bounds.Min, want.Min, 1e-12) || !vecClose(bounds.Max, want.Max, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", bounds, want)
+	}
+	x, _, err := box.IntersectRay(MakeRay(v3(-5, 0, 0), v3(1, 0, 0)))
+	if want := (rn.Vec{N: 2, X: []float64{5 - math.Sqrt2, 5 + math.Sqrt2}}); err != nil || !vecClose(x, want, 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", x, err, want)
+	}
+	if got := box.ClassifyPlane(MakePlane(v3(1.5, 0, 0), v3(0, 1, 0), v3(0, 0, 1))); got != Back {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, Back)
+	}
+}
+
+func TestOBBOverlaps(t *testing.T) {
+	a := MakeOBB(v3(0, 0, 0), rn.MakeIdentity(3), v3(1, 1, 1))
+	for _, test := range []struct {
+		b    OBB
+		want bool
+	}{
+		{MakeOBB(v3(1.5, 0, 0), rn.MakeIdentity(3), v3(1, 1, 1)), true},
+		{MakeOBB(v3(2.5, 0, 0), rn.MakeIdentity(3), v3(1, 1, 1)), false},
+		{MakeOBB(v3(2.3, 0, 0), MakeRotation(2, math.Pi/4), v3(1, 1, 1)), true},
+		{MakeOBB(v3(2.5, 0, 0), MakeRotation(2, math.Pi/4), v3(1, 1, 1)), false},
+		{MakeOBB(v3(2, 2, 0), MakeRotation(2, math.Pi/4), v3(1, 0.1, 1)), false},
+	} {
+		if got := a.Overlaps(test.b); got != test.want {
+			t.Errorf("error:\n%v:\ngot=%v\nwant=%v", test.b, got, test.want)
+		}
+	}
+}
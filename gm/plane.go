@@ -2,6 +2,7 @@ package gm
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/add1609/lin/rn"
 )
@@ -67,6 +68,11 @@ func (o *Plane) Equal(q Plane) bool {
 
 // IntersectLine returns the intersection point of a plane and a line (if it exists)
 //
+// Whether the line is parallel to the plane is decided exactly from the 3 x 3
+// minors of its direction and the plane's, not by the numerical rank that
+// Affine.Intersect uses. In dimensions other than 3 a line that is not
+// parallel to the plane meets it only if it passes within a relative tolerance.
+//
 // Parameters:
 //
 //	o *Plane - The plane
@@ -74,18 +80,74 @@ func (o *Plane) Equal(q Plane) bool {
 //
 // Returns:
 //
-//	x rn.Vec - The value of λ and μ that satisfy the equation
+//	x rn.Vec - The line parameter and the values of λ and μ of the plane at the intersection point
 //	P1 rn.Vec - The intersection point
-//	err error - An error if one occurred
+//	err error - ErrParallel if the line is parallel to the plane, ErrNoIntersection if it
+//	misses the plane
 func (o *Plane) IntersectLine(line Line) (x, P1 rn.Vec, err error) {
-	lgs := rn.MakeMat(3, 4, 0)
-	lgs.SetCol(0, line.V2)
-	lgs.SetCol(1, o.V2.Scale(-1))
-	lgs.SetCol(2, o.V3.Scale(-1))
-	lgs.SetCol(3, o.V1.Sub(line.V1))
-	_, x, err = lgs.GaussSolve()
-	if err != nil {
-		P1 = line.V1.Add(line.V2.Scale(x.Get(0)))
+	x, P1, err = intersectPlane(line.V1, line.V2, math.Inf(-1), math.Inf(1), *o)
+	return
+}
+
+// Normal returns the unit normal V2 × V3 / |V2 × V3| of the plane
+//
+// Parameters:
+//
+//	o *Plane - The plane
+//
+// Returns:
+//
+//	n rn.Vec - The unit normal
+func (o *Plane) Normal() (n rn.Vec) {
+	n = o.V2.Cross(o.V3)
+	n = n.Scale(1 / n.Norm())
+	return
+}
+
+// SignedDist returns the signed distance between P and the plane
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance, positive on the side the normal points to
+func (o *Plane) SignedDist(P rn.Vec) (dist float64) {
+	n := o.Normal()
+	w := P.Sub(o.V1)
+	dist = n.Dot(w)
+	return
+}
+
+// Side classifies an object relative to the normal of a plane
+type Side int
+
+const (
+	Back     Side = -1 // entirely on the side opposite to the normal
+	Straddle Side = 0  // touches or crosses the plane
+	Front    Side = 1  // entirely on the side the normal points to
+)
+
+// Side returns on which side of the plane P lies
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	side Side - Front, Back, or Straddle if P lies in the plane
+func (o *Plane) Side(P rn.Vec) (side Side) {
+	d := o.SignedDist(P)
+	tol := tolerance(o.V1, P)
+	switch {
+	case d > tol:
+		side = Front
+	case d < -tol:
+		side = Back
+	default:
+		side = Straddle
 	}
 	return
 }
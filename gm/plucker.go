@@ -0,0 +1,291 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Plucker is a 3D line in Plücker coordinates: the direction D and the moment M = P × D
+// of any point P on the line
+//
+// The coordinates are homogeneous, (s*D, s*M) is the same line for every
+// s ≠ 0, and a valid line satisfies D ≠ 0 and D · M = 0.
+type Plucker struct {
+	D, M rn.Vec
+}
+
+func (o Plucker) String() (str string) {
+	str += fmt.Sprintf("(%v : %v)", o.D, o.M)
+	return
+}
+
+// MakePlucker returns a Plucker object with direction D and moment M
+//
+// Parameters:
+//
+//	D rn.Vec - The direction
+//	M rn.Vec - The moment
+//
+// Returns:
+//
+//	pl Plucker - The line
+func MakePlucker(D, M rn.Vec) (pl Plucker) {
+	if D.N != 3 || M.N != 3 {
+		panic(errors.ErrShape)
+	}
+	pl.D = D
+	pl.M = M
+	return
+}
+
+// MakePluckerByPoints returns the line through P1 and P2 in Plücker coordinates
+//
+// Parameters:
+//
+//	P1 rn.Vec - The first point
+//	P2 rn.Vec - The second point
+//
+// Returns:
+//
+//	pl Plucker - The line with direction P2 - P1
+func MakePluckerByPoints(P1, P2 rn.Vec) (pl Plucker) {
+	pl = MakePlucker(P2.Sub(P1), P1.Cross(P2))
+	return
+}
+
+// Plucker returns the line in Plücker coordinates
+//
+// Parameters:
+//
+//	o *Line - The line
+//
+// Returns:
+//
+//	pl Plucker - The line with direction V2
+func (o *Line) Plucker() (pl Plucker) {
+	pl = MakePlucker(o.V2, o.V1.Cross(o.V2))
+	return
+}
+
+// Line returns the line in point-direction form
+//
+// Parameters:
+//
+//	o *Plucker - The line
+//
+// Returns:
+//
+//	line Line - The line through the point closest to the origin with direction D
+//	err error - ErrDegenerate if D is zero
+func (o *Plucker) Line() (line Line, err error) {
+	dd := o.D.Dot(o.D)
+	if dd == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	P1 := o.D.Cross(o.M)
+	line = MakeLine(P1.Scale(1/dd), o.D)
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Plucker - line to compare to q
+//	q Plucker - line to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q have the same coordinates
+func (o *Plucker) Equal(q Plucker) bool {
+	return o.D.Equal(q.D) && o.M.Equal(q.M)
+}
+
+// Normalize returns the same line scaled to a unit direction
+//
+// Parameters:
+//
+//	o *Plucker - The line
+//
+// Returns:
+//
+//	pl Plucker - The line with |D| = 1
+func (o *Plucker) Normalize() (pl Plucker) {
+	s := 1 / o.D.Norm()
+	pl = MakePlucker(o.D.Scale(s), o.M.Scale(s))
+	return
+}
+
+// Moment returns the moment of the line about a point
+//
+// Parameters:
+//
+//	P rn.Vec - The reference point
+//
+// Returns:
+//
+//	M1 rn.Vec - (Q - P) × D for any point Q on the line, zero if and only if P lies on the line
+func (o *Plucker) Moment(P rn.Vec) (M1 rn.Vec) {
+	PD := P.Cross(o.D)
+	M1 = o.M.Sub(PD)
+	return
+}
+
+// DistPoint returns the distance between P and the line
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance
+func (o *Plucker) DistPoint(P rn.Vec) (dist float64) {
+	M1 := o.Moment(P)
+	dist = M1.Norm() / o.D.Norm()
+	return
+}
+
+// Contains returns true if P lies on the line
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if the distance between P and the line is within a relative tolerance
+func (o *Plucker) Contains(P rn.Vec) bool {
+	return o.DistPoint(P) <= tolerance(P, o.M.Scale(1/o.D.Norm()))
+}
+
+// Reciprocal returns the reciprocal product D · q.M + q.D · M of two lines
+//
+// The product is zero if and only if the lines are coplanar, that is they
+// intersect or are parallel. For skew lines its sign tells on which side
+// one line passes the other.
+//
+// Parameters:
+//
+//	o *Plucker - The first line
+//	q Plucker - The second line
+//
+// Returns:
+//
+//	r float64 - The reciprocal product
+func (o *Plucker) Reciprocal(q Plucker) (r float64) {
+	r = o.D.Dot(q.M) + q.D.Dot(o.M)
+	return
+}
+
+// Coplanar returns true if two lines lie in a common plane
+//
+// Parameters:
+//
+//	o *Plucker - The first line
+//	q Plucker - The second line
+//
+// Returns:
+//
+//	bool - true if the reciprocal product vanishes within a relative tolerance
+func (o *Plucker) Coplanar(q Plucker) bool {
+	r := o.Reciprocal(q)
+	scale := o.D.Norm()*q.M.Norm() + q.D.Norm()*o.M.Norm() + o.D.Norm()*q.D.Norm()
+	return math.Abs(r) <= eps*scale
+}
+
+// Dist returns the distance between two lines
+//
+// Parameters:
+//
+//	o *Plucker - The first line
+//	q Plucker - The second line
+//
+// Returns:
+//
+//	dist float64 - The length of the common perpendicular, or the distance between parallel lines
+func (o *Plucker) Dist(q Plucker) (dist float64) {
+	n := o.D.Cross(q.D)
+	if !parallelDirs(o.D, q.D) {
+		dist = math.Abs(o.Reciprocal(q)) / n.Norm()
+		return
+	}
+	// parallel: the distance from a point of q to o
+	line, _ := q.Line()
+	dist = o.DistPoint(line.V1)
+	return
+}
+
+// Intersect returns the intersection point of two lines (if it exists)
+//
+// Parameters:
+//
+//	o *Plucker - The first line
+//	q Plucker - The second line
+//
+// Returns:
+//
+//	P1 rn.Vec - The intersection point
+//	err error - ErrParallel if the lines are parallel, ErrNoIntersection if they are skew
+func (o *Plucker) Intersect(q Plucker) (P1 rn.Vec, err error) {
+	if parallelDirs(o.D, q.D) {
+		err = errors.ErrParallel
+		return
+	}
+	if !o.Coplanar(q) {
+		err = errors.ErrNoIntersection
+		return
+	}
+	// P1 = (α D + β q.D + γ n) with n = D × q.D solves P1 × D = M and P1 × q.D = q.M;
+	// γ is averaged over both lines to absorb rounding in the reciprocal product
+	n := o.D.Cross(q.D)
+	nn := n.Dot(n)
+	alpha, beta := q.M.Dot(n)/nn, -o.M.Dot(n)/nn
+	gamma := (o.M.Dot(q.D) - q.M.Dot(o.D)) / (2 * nn)
+	P1 = o.D.Scale(alpha)
+	P1 = P1.Add(q.D.Scale(beta))
+	P1 = P1.Add(n.Scale(gamma))
+	return
+}
+
+// Transform returns the image of the line under a projective transformation
+//
+// The line is mapped through two of its points, the point closest to the
+// origin and its point at infinity, so T may be any invertible 4 x 4 matrix
+// acting on homogeneous coordinates (x, y, z, 1).
+//
+// Parameters:
+//
+//	T rn.Mat - The 4 x 4 transformation
+//
+// Returns:
+//
+//	pl Plucker - The transformed line, up to scale
+func (o *Plucker) Transform(T rn.Mat) (pl Plucker) {
+	if T.M != 4 || T.N != 4 {
+		panic(errors.ErrShape)
+	}
+	line, err := o.Line()
+	if err != nil {
+		panic(err)
+	}
+	X := rn.Vec{N: 4, X: []float64{line.V1.X[0], line.V1.X[1], line.V1.X[2], 1}}
+	Y := rn.Vec{N: 4, X: []float64{o.D.X[0], o.D.X[1], o.D.X[2], 0}}
+	X, Y = T.MulVec(X), T.MulVec(Y)
+	pl = makePluckerHomogeneous(X, Y)
+	return
+}
+
+// makePluckerHomogeneous returns the line through the homogeneous points X and Y
+func makePluckerHomogeneous(X, Y rn.Vec) (pl Plucker) {
+	x := rn.Vec{N: 3, X: X.X[:3]}
+	y := rn.Vec{N: 3, X: Y.X[:3]}
+	D := y.Scale(X.X[3])
+	D = D.Sub(x.Scale(Y.X[3]))
+	pl = MakePlucker(D, x.Cross(y))
+	return
+}
@@ -0,0 +1,88 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+func TestPlucker(t *testing.T) {
+	line := MakeLine(v3(1, 2, 3), v3(0, 0, 2))
+	pl := line.Plucker()
+	back, err := pl.Line()
+	if err != nil || !vecClose(back.V1, v3(1, 2, 0), 1e-15) || !vecClose(back.V2, v3(0, 0, 2), 0) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", back, err, "x = (1, 2, 0) + λ * (0, 0, 2)")
+	}
+	if !pl.Contains(v3(1, 2, -7)) || pl.Contains(v3(1, 2.1, 0)) {
+		t.Errorf("error:\ngot=%v\nwant=%v", false, true)
+	}
+	if got, want := pl.DistPoint(v3(4, 6, 1)), 5.0; math.Abs(got-want) > 1e-15 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+
+	tests := []struct {
+		q        Plucker
+		dist     float64
+		coplanar bool
+		P1       rn.Vec
+		err      error
+	}{
+		// crosses the first line at (1, 2, 5)
+		{MakePluckerByPoints(v3(0, 0, 5), v3(2, 4, 5)), 0, true, v3(1, 2, 5), nil},
+		// skew, the common perpendicular runs along x from (1, 2, 0) to (4, 2, 0)
+		{MakePluckerByPoints(v3(4, 0, 0), v3(4, 1, 0)), 3, false, rn.Vec{}, errors.ErrNoIntersection},
+		// parallel at distance 5
+		{MakePluckerByPoints(v3(4, 6, 0), v3(4, 6, 1)), 5, true, rn.Vec{}, errors.ErrParallel},
+	}
+	for _, test := range tests {
+		if got := pl.Dist(test.q); math.Abs(got-test.dist) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.dist)
+		}
+		if got := pl.Coplanar(test.q); got != test.coplanar {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.coplanar)
+		}
+		P1, err := pl.Intersect(test.q)
+		if err != test.err || err == nil && !vecClose(P1, test.P1, 1e-12) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", P1, err, test.P1, test.err)
+		}
+	}
+	// the sign of the reciprocal product tells how skew lines pass each other
+	a, b := MakePluckerByPoints(v3(4, 0, 0), v3(4, 1, 0)), MakePluckerByPoints(v3(4, 1, 0), v3(4, 0, 0))
+	if pl.Reciprocal(a)*pl.Reciprocal(b) >= 0 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", pl.Reciprocal(a), pl.Reciprocal(b), "opposite signs")
+	}
+}
+
+func TestPluckerTransform(t *testing.T) {
+	pl := MakePluckerByPoints(v3(1, 0, 0), v3(1, 1, 1))
+	rot := MakeRotationAxisAngle(v3(1, 1, 0), 0.7)
+	trans := v3(3, -1, 2)
+	T := rn.MakeIdentity(4)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			T.Set(i, j, rot.Get(i, j))
+		}
+		T.Set(i, 3, trans.X[i])
+	}
+	got := pl.Transform(T)
+	P1, P2 := rot.MulVec(v3(1, 0, 0)), rot.MulVec(v3(1, 1, 1))
+	want := MakePluckerByPoints(P1.Add(trans), P2.Add(trans))
+	got, want = got.Normalize(), want.Normalize()
+	if !vecClose(got.D, want.D, 1e-12) || !vecClose(got.M, want.M, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+
+	// a perspective division maps (x, y, z) to (x, y, z) / z
+	persp := rn.MakeIdentity(4)
+	persp.Set(3, 3, 0)
+	persp.Set(3, 2, 1)
+	pl = MakePluckerByPoints(v3(1, 0, 1), v3(1, 2, 2))
+	got = pl.Transform(persp)
+	for _, P := range []rn.Vec{v3(1, 0, 1), v3(0.5, 1, 1)} {
+		if !got.Contains(P) {
+			t.Errorf("error:\n%v does not contain %v", got, P)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package gm
+
+import (
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// ToHomogeneous returns the homogeneous coordinates of a point
+//
+// Parameters:
+//
+//	P rn.Vec - The point
+//
+// Returns:
+//
+//	X rn.Vec - The coordinates (P, 1), one longer than P
+func ToHomogeneous(P rn.Vec) (X rn.Vec) {
+	X = rn.MakeVec(P.N+1, 1)
+	copy(X.X, P.X)
+	return
+}
+
+// MakePointAtInfinity returns the homogeneous coordinates of the point at infinity in direction D
+//
+// Parameters:
+//
+//	D rn.Vec - The direction
+//
+// Returns:
+//
+//	X rn.Vec - The coordinates (D, 0), one longer than D
+func MakePointAtInfinity(D rn.Vec) (X rn.Vec) {
+	X = rn.MakeVec(D.N+1, 0)
+	copy(X.X, D.X)
+	return
+}
+
+// IsAtInfinity returns true if homogeneous coordinates describe a point at infinity
+//
+// Parameters:
+//
+//	X rn.Vec - The homogeneous coordinates
+//
+// Returns:
+//
+//	bool - true if the last coordinate is exactly zero
+func IsAtInfinity(X rn.Vec) bool {
+	return X.X[X.N-1] == 0
+}
+
+// IsNearInfinity returns true if homogeneous coordinates describe a point at or close to infinity
+//
+// Parameters:
+//
+//	X rn.Vec - The homogeneous coordinates
+//	tol float64 - The relative tolerance
+//
+// Returns:
+//
+//	bool - true if the magnitude of the last coordinate is at most tol times the largest
+//	magnitude of the others
+func IsNearInfinity(X rn.Vec, tol float64) bool {
+	w := math.Abs(X.X[X.N-1])
+	largest := 0.0
+	for _, x := range X.X[:X.N-1] {
+		largest = math.Max(largest, math.Abs(x))
+	}
+	return w <= tol*largest
+}
+
+// FromHomogeneous returns the point with the given homogeneous coordinates
+//
+// Parameters:
+//
+//	X rn.Vec - The homogeneous coordinates
+//
+// Returns:
+//
+//	P rn.Vec - The point X / w without the last coordinate w
+//	err error - ErrInfinity if w is zero or X / w overflows
+func FromHomogeneous(X rn.Vec) (P rn.Vec, err error) {
+	if IsAtInfinity(X) {
+		err = errors.ErrInfinity
+		return
+	}
+	w := X.X[X.N-1]
+	P = rn.MakeVec(X.N-1, 0)
+	for i := range P.X {
+		P.X[i] = X.X[i] / w
+		if math.IsInf(P.X[i], 0) {
+			err = errors.ErrInfinity
+			return
+		}
+	}
+	return
+}
+
+// NormalizeHomogeneous returns the canonical representative of homogeneous coordinates
+//
+// Parameters:
+//
+//	X rn.Vec - The homogeneous coordinates
+//
+// Returns:
+//
+//	X1 rn.Vec - X scaled to a last coordinate of 1, or to unit length for points at infinity
+func NormalizeHomogeneous(X rn.Vec) (X1 rn.Vec) {
+	if IsAtInfinity(X) {
+		X1 = X.Scale(1 / X.Norm())
+		X1.X[X1.N-1] = 0
+		return
+	}
+	X1 = X.Scale(1 / X.X[X.N-1])
+	return
+}
+
+// ApplyProjective maps a point through a projective transformation
+//
+// Parameters:
+//
+//	T rn.Mat - The (m+1) x (n+1) matrix acting on homogeneous coordinates
+//	P rn.Vec - The point of dimension n
+//
+// Returns:
+//
+//	P1 rn.Vec - The image of dimension m
+//	err error - ErrInfinity if P is mapped to a point at infinity
+func ApplyProjective(T rn.Mat, P rn.Vec) (P1 rn.Vec, err error) {
+	if T.N != P.N+1 {
+		panic(errors.ErrShape)
+	}
+	X := ToHomogeneous(P)
+	X = T.MulVec(X)
+	P1, err = FromHomogeneous(X)
+	return
+}
+
+// EstimateHomography returns the plane projective transformation that maps src onto dst
+//
+// The homography is estimated with the normalized direct linear
+// transformation: both point sets are translated and scaled to zero mean
+// and an average distance of √2 from the origin, and the homography is the
+// right singular vector of the smallest singular value of the stacked
+// correspondence equations. With more than 4 points it minimizes an
+// algebraic error.
+//
+// Parameters:
+//
+//	src []rn.Vec - The 2D source points
+//	dst []rn.Vec - The 2D target points, dst[k] corresponds to src[k]
+//
+// Returns:
+//
+//	H rn.Mat - The 3 x 3 homography, scaled such that H[2][2] = 1 unless it is zero
+//	err error - ErrDegenerate if there are less than 4 points or too many of them are collinear
+func EstimateHomography(src, dst []rn.Vec) (H rn.Mat, err error) {
+	if len(src) != len(dst) {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	checkDims(src, 2)
+	checkDims(dst, 2)
+	if len(src) < 4 {
+		err = errors.ErrDegenerate
+		return
+	}
+	Ts, okS := normalizing2D(src)
+	Td, okD := normalizing2D(dst)
+	if !okS || !okD {
+		err = errors.ErrDegenerate
+		return
+	}
+	// two equations per correspondence, padded with zero rows so that the SVD has 9 right singular vectors
+	rows := 2 * len(src)
+	if rows < 9 {
+		rows = 9
+	}
+	A := rn.MakeMat(rows, 9, 0)
+	for k := range src {
+		p, _ := ApplyProjective(Ts, src[k])
+		q, _ := ApplyProjective(Td, dst[k])
+		x, y, u, v := p.X[0], p.X[1], q.X[0], q.X[1]
+		A.SetRow(2*k, rn.Vec{N: 9, X: []float64{0, 0, 0, -x, -y, -1, v * x, v * y, v}})
+		A.SetRow(2*k+1, rn.Vec{N: 9, X: []float64{x, y, 1, 0, 0, 0, -u * x, -u * y, -u}})
+	}
+	_, S, V := A.SVD()
+	if S.X[7] <= eps*S.X[0] {
+		err = errors.ErrDegenerate
+		return
+	}
+	h := V.GetCol(8)
+	Hn := rn.MakeMat(3, 3, 0)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			Hn.Set(i, j, h.X[3*i+j])
+		}
+	}
+	// undo the normalization: H = Td⁻¹ * Hn * Ts
+	TdInv, err := Td.Inverse()
+	if err != nil {
+		return
+	}
+	H = TdInv.Mul(Hn)
+	H = H.Mul(Ts)
+	if w := H.Get(2, 2); w != 0 {
+		for k := range H.Data {
+			H.Data[k] /= w
+		}
+	}
+	return
+}
+
+// normalizing2D returns the similarity that moves the centroid of Ps to the origin
+// and scales their average distance from it to √2
+func normalizing2D(Ps []rn.Vec) (T rn.Mat, ok bool) {
+	mean := centroid(Ps)
+	var dist float64
+	for _, P := range Ps {
+		dist += P.Dist(mean)
+	}
+	dist /= float64(len(Ps))
+	if dist == 0 {
+		return
+	}
+	s := math.Sqrt2 / dist
+	T = rn.MakeIdentity(3)
+	T.Set(0, 0, s)
+	T.Set(1, 1, s)
+	T.Set(0, 2, -s*mean.X[0])
+	T.Set(1, 2, -s*mean.X[1])
+	ok = true
+	return
+}
@@ -0,0 +1,132 @@
+package gm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+func v2(x, y float64) rn.Vec {
+	return rn.Vec{N: 2, X: []float64{x, y}}
+}
+
+func TestHomogeneous(t *testing.T) {
+	X := ToHomogeneous(v2(1, 2))
+	X = X.Scale(-3)
+	if P, err := FromHomogeneous(X); err != nil || !vecClose(P, v2(1, 2), 1e-15) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", P, err, v2(1, 2))
+	}
+	if got := NormalizeHomogeneous(X); !vecClose(got, v3(1, 2, 1), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v3(1, 2, 1))
+	}
+	inf := MakePointAtInfinity(v2(3, 4))
+	if _, err := FromHomogeneous(inf); err != errors.ErrInfinity || !IsAtInfinity(inf) {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+	if got := NormalizeHomogeneous(inf); !vecClose(got, v3(0.6, 0.8, 0), 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, v3(0.6, 0.8, 0))
+	}
+
+	// large finite coordinates are not at infinity
+	far := v3(2e9, 3, 1)
+	if P, err := FromHomogeneous(far); err != nil || !P.Equal(v2(2e9, 3)) || IsAtInfinity(far) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", P, err, v2(2e9, 3))
+	}
+	if P, err := ApplyProjective(rn.MakeIdentity(3), v2(2e9, 3)); err != nil || !P.Equal(v2(2e9, 3)) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", P, err, v2(2e9, 3))
+	}
+	if !IsNearInfinity(far, 1e-9) || IsNearInfinity(far, 1e-10) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", IsNearInfinity(far, 1e-9), IsNearInfinity(far, 1e-10), true, false)
+	}
+	if _, err := FromHomogeneous(v3(1e300, 0, 1e-300)); err != errors.ErrInfinity {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+}
+
+func TestEstimateHomography(t *testing.T) {
+	want := rn.Mat{M: 3, N: 3, Data: []float64{1.2, 0.1, 1e-3, -0.2, 0.9, 2e-3, 5, -3, 1}}
+	rng := rand.New(rand.NewSource(1))
+	var src, dst []rn.Vec
+	for k := 0; k < 20; k++ {
+		P := v2(100*rng.Float64(), 100*rng.Float64())
+		Q, _ := ApplyProjective(want, P)
+		src, dst = append(src, P), append(dst, Q)
+	}
+	for _, n := range []int{4, 20} {
+		H, err := EstimateHomography(src[:n], dst[:n])
+		if err != nil || !matClose(H, want, 1e-9) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v", H, err, want)
+		}
+	}
+
+	// the corners of the unit square onto a quadrilateral
+	square := []rn.Vec{v2(0, 0), v2(1, 0), v2(1, 1), v2(0, 1)}
+	quad := []rn.Vec{v2(0, 0), v2(2, 0), v2(1.5, 1), v2(0.5, 1)}
+	H, err := EstimateHomography(square, quad)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	for k := range square {
+		if P, _ := ApplyProjective(H, square[k]); !vecClose(P, quad[k], 1e-12) {
+			t.Errorf("error:\ngot=%v\nwant=%v", P, quad[k])
+		}
+	}
+
+	collinear := []rn.Vec{v2(0, 0), v2(1, 1), v2(2, 2), v2(3, 3), v2(0, 1)}
+	if _, err := EstimateHomography(collinear, collinear); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
+
+func TestCamera(t *testing.T) {
+	K := MakeIntrinsics(800, 700, 320, 240, 0)
+	R := MakeRotationAxisAngle(v3(0, 1, 0), 0.3)
+	C := v3(1, -2, -10)
+	// T = -R * C puts the center at C
+	T := R.MulVec(C)
+	cam := MakeCamera(K, R, T.Scale(-1))
+	if got := cam.Center(); !vecClose(got, C, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, C)
+	}
+
+	P := v3(0.5, 0.25, 2)
+	p, err := cam.Project(P)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	ray, err := cam.BackProject(p)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if got := ray.At(cam.Depth(P)); !vecClose(got, P, 1e-9) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, P)
+	}
+	// the principal point looks along the optical axis
+	axis, _ := cam.BackProject(v2(320, 240))
+	want := R.GetRow(2)
+	if !vecClose(axis.V2, want, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", axis.V2, want)
+	}
+	M := cam.Matrix()
+	if q, _ := ApplyProjective(M, P); !vecClose(q, p, 1e-9) || math.Abs(cam.Depth(P)) < 1 {
+		t.Errorf("error:\ngot=%v\nwant=%v", q, p)
+	}
+
+	// a point in the plane of the camera center has no image, up to rounding of the rotation
+	side := cam.Center()
+	side = side.Add(R.GetRow(0))
+	if X := M.MulVec(ToHomogeneous(side)); !IsNearInfinity(X, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", X, "w = 0")
+	}
+	front := MakeCamera(MakeIntrinsics(1000, 1000, 0, 0, 0), rn.MakeIdentity(3), v3(0, 0, 0))
+	if _, err := front.Project(v3(4, -3, 0)); err != errors.ErrInfinity {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+	// far off the optical axis the image is finite however large it is
+	if p, err := front.Project(v3(1e7, 0, 1)); err != nil || !p.Equal(v2(1e10, 0)) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", p, err, v2(1e10, 0))
+	}
+}
@@ -0,0 +1,633 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Quadric is the set of points P of space with Xᵀ * A * X = 0 for X = (P, 1)
+type Quadric struct {
+	A rn.Mat // 4 x 4 symmetric matrix
+}
+
+func (o Quadric) String() (str string) {
+	str += fmt.Sprintf("xᵀ *\n%v\n* x = 0", o.A)
+	return
+}
+
+// QuadricType is the affine class of a quadric
+type QuadricType int
+
+const (
+	QuadricNone                        QuadricType = iota // no quadratic terms
+	QuadricEllipsoid                                      // real ellipsoid, including spheres
+	QuadricImaginaryEllipsoid                             // no real points
+	QuadricHyperboloidOneSheet                            // connected, saddle shaped everywhere
+	QuadricHyperboloidTwoSheets                           // two components
+	QuadricCone                                           // real elliptic cone
+	QuadricImaginaryCone                                  // a single real point
+	QuadricEllipticParaboloid                             // one bowl
+	QuadricHyperbolicParaboloid                           // saddle
+	QuadricEllipticCylinder                               // real elliptic cylinder
+	QuadricImaginaryCylinder                              // no real points
+	QuadricHyperbolicCylinder                             // two sheets along a common direction
+	QuadricParabolicCylinder                              // one sheet along a direction
+	QuadricIntersectingPlanes                             // two real planes that cross
+	QuadricImaginaryIntersectingPlanes                    // a single real line
+	QuadricParallelPlanes                                 // two distinct real parallel planes
+	QuadricImaginaryParallelPlanes                        // no real points
+	QuadricCoincidentPlanes                               // a double plane
+)
+
+var quadricNames = [...]string{
+	QuadricNone:                        "none",
+	QuadricEllipsoid:                   "ellipsoid",
+	QuadricImaginaryEllipsoid:          "imaginary ellipsoid",
+	QuadricHyperboloidOneSheet:         "hyperboloid of one sheet",
+	QuadricHyperboloidTwoSheets:        "hyperboloid of two sheets",
+	QuadricCone:                        "cone",
+	QuadricImaginaryCone:               "imaginary cone",
+	QuadricEllipticParaboloid:          "elliptic paraboloid",
+	QuadricHyperbolicParaboloid:        "hyperbolic paraboloid",
+	QuadricEllipticCylinder:            "elliptic cylinder",
+	QuadricImaginaryCylinder:           "imaginary cylinder",
+	QuadricHyperbolicCylinder:          "hyperbolic cylinder",
+	QuadricParabolicCylinder:           "parabolic cylinder",
+	QuadricIntersectingPlanes:          "intersecting planes",
+	QuadricImaginaryIntersectingPlanes: "imaginary intersecting planes",
+	QuadricParallelPlanes:              "parallel planes",
+	QuadricImaginaryParallelPlanes:     "imaginary parallel planes",
+	QuadricCoincidentPlanes:            "coincident planes",
+}
+
+func (o QuadricType) String() string {
+	if o < 0 || int(o) >= len(quadricNames) {
+		return "QuadricType(?)"
+	}
+	return quadricNames[o]
+}
+
+// quadricTypes maps the inertia of the quadratic part and of the whole matrix to the quadric type
+var quadricTypes = map[[4]int]QuadricType{
+	{3, 0, 3, 1}: QuadricEllipsoid,
+	{3, 0, 4, 0}: QuadricImaginaryEllipsoid,
+	{2, 1, 2, 2}: QuadricHyperboloidOneSheet,
+	{2, 1, 3, 1}: QuadricHyperboloidTwoSheets,
+	{2, 1, 2, 1}: QuadricCone,
+	{3, 0, 3, 0}: QuadricImaginaryCone,
+	{2, 0, 3, 1}: QuadricEllipticParaboloid,
+	{1, 1, 2, 2}: QuadricHyperbolicParaboloid,
+	{2, 0, 2, 1}: QuadricEllipticCylinder,
+	{2, 0, 3, 0}: QuadricImaginaryCylinder,
+	{1, 1, 2, 1}: QuadricHyperbolicCylinder,
+	{1, 0, 2, 1}: QuadricParabolicCylinder,
+	{1, 1, 1, 1}: QuadricIntersectingPlanes,
+	{2, 0, 2, 0}: QuadricImaginaryIntersectingPlanes,
+	{1, 0, 1, 1}: QuadricParallelPlanes,
+	{1, 0, 2, 0}: QuadricImaginaryParallelPlanes,
+	{1, 0, 1, 0}: QuadricCoincidentPlanes,
+}
+
+// MakeQuadric returns a Quadric object with the given matrix
+//
+// Parameters:
+//
+//	A rn.Mat - The 4 x 4 matrix, only its symmetric part (A + Aᵀ) / 2 is used
+//
+// Returns:
+//
+//	quadric Quadric - The quadric Xᵀ * A * X = 0
+func MakeQuadric(A rn.Mat) (quadric Quadric) {
+	if A.M != 4 || A.N != 4 {
+		panic(errors.ErrShape)
+	}
+	quadric.A = symmetricPart(A)
+	return
+}
+
+// MakeEllipsoid returns an axis-aligned ellipsoid
+//
+// Parameters:
+//
+//	C rn.Vec - The center
+//	radii rn.Vec - The semi-axes along x, y and z
+//
+// Returns:
+//
+//	quadric Quadric - The quadric Σ ((x_i - C_i) / radii_i)² = 1
+func MakeEllipsoid(C, radii rn.Vec) (quadric Quadric) {
+	if C.N != 3 || radii.N != 3 {
+		panic(errors.ErrShape)
+	}
+	M := rn.MakeMat(3, 3, 0)
+	for i, r := range radii.X {
+		if r == 0 {
+			panic(errors.ErrDegenerate)
+		}
+		M.Set(i, i, 1/(r*r))
+	}
+	quadric.A = centeredForm(C, M, rn.MakeVec(3, 0), -1)
+	return
+}
+
+// MakeCylinder returns a circular cylinder
+//
+// Parameters:
+//
+//	axis Line - The axis
+//	R float64 - The radius
+//
+// Returns:
+//
+//	quadric Quadric - The quadric of the points at distance R from the axis
+func MakeCylinder(axis Line, R float64) (quadric Quadric) {
+	M := axisProjector(axis)
+	quadric.A = centeredForm(axis.V1, M, rn.MakeVec(3, 0), -R*R)
+	return
+}
+
+// MakeCone returns a circular double cone
+//
+// Parameters:
+//
+//	apex rn.Vec - The apex
+//	axis rn.Vec - The direction of the axis
+//	angle float64 - The half opening angle in radians, between 0 and π / 2
+//
+// Returns:
+//
+//	quadric Quadric - The quadric of the points whose direction from apex makes the angle
+//	angle with the axis or its opposite
+func MakeCone(apex, axis rn.Vec, angle float64) (quadric Quadric) {
+	if apex.N != 3 || axis.N != 3 {
+		panic(errors.ErrShape)
+	}
+	// ((X - apex) · d)² = cos²(angle) * |X - apex|²
+	d := axis.Scale(1 / axis.Norm())
+	cos2 := math.Cos(angle) * math.Cos(angle)
+	M := rn.MakeIdentity(3)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			M.Set(i, j, cos2*M.Get(i, j)-d.X[i]*d.X[j])
+		}
+	}
+	quadric.A = centeredForm(apex, M, rn.MakeVec(3, 0), 0)
+	return
+}
+
+// MakeParaboloid returns a circular paraboloid
+//
+// Parameters:
+//
+//	axis Line - The axis, V1 is the vertex and V2 points into the bowl
+//	f float64 - The focal length, the focus lies at distance f from the vertex
+//
+// Returns:
+//
+//	quadric Quadric - The quadric r² = 4 * f * h, where h is the height along the axis and r
+//	the distance from the axis
+func MakeParaboloid(axis Line, f float64) (quadric Quadric) {
+	M := axisProjector(axis)
+	d := axis.V2.Scale(1 / axis.V2.Norm())
+	quadric.A = centeredForm(axis.V1, M, d.Scale(-2*f), 0)
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Quadric - quadric to compare to q
+//	q Quadric - quadric to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q have the same matrix
+func (o *Quadric) Equal(q Quadric) bool {
+	return matEqual(o.A, q.A)
+}
+
+// Eval returns the value of the quadratic form at P
+//
+// Parameters:
+//
+//	P rn.Vec - The 3D point
+//
+// Returns:
+//
+//	val float64 - Xᵀ * A * X for X = (P, 1), 0 on the surface and negative inside the
+//	ellipsoids, cylinders and paraboloids made by this package
+func (o *Quadric) Eval(P rn.Vec) (val float64) {
+	if P.N != 3 {
+		panic(errors.ErrShape)
+	}
+	val = evalForm(o.A, P)
+	return
+}
+
+// Classify returns the affine class of the quadric
+//
+// Zero eigenvalues and the sign of the constant after completing the square
+// are decided with a relative tolerance.
+//
+// Parameters:
+//
+//	o *Quadric - The quadric
+//
+// Returns:
+//
+//	t QuadricType - The type, QuadricNone if A has no quadratic terms
+func (o *Quadric) Classify() (t QuadricType) {
+	key, ok := formInertia(o.A)
+	if !ok {
+		return QuadricNone
+	}
+	t = quadricTypes[key]
+	return
+}
+
+// Transform returns the image of the quadric under a projective transformation
+//
+// Parameters:
+//
+//	T rn.Mat - The 4 x 4 matrix acting on homogeneous coordinates
+//
+// Returns:
+//
+//	quadric Quadric - The quadric with matrix T⁻ᵀ * A * T⁻¹
+//	err error - ErrSingular if T is singular
+func (o *Quadric) Transform(T rn.Mat) (quadric Quadric, err error) {
+	if T.M != 4 || T.N != 4 {
+		panic(errors.ErrShape)
+	}
+	quadric.A, err = transformForm(o.A, T)
+	return
+}
+
+// IntersectLine returns the intersection points of a quadric and a line
+//
+// Parameters:
+//
+//	o *Quadric - The quadric
+//	q Line - The line
+//
+// Returns:
+//
+//	ts []float64 - The line parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrParallel if the line lies in the surface,
+//	ErrNoIntersection if the line misses the surface
+func (o *Quadric) IntersectLine(q Line) (ts []float64, Ps []rn.Vec, err error) {
+	if q.V1.N != 3 {
+		panic(errors.ErrShape)
+	}
+	ts, Ps, err = intersectForm(o.A, q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectRay returns the intersection points of a quadric and a ray
+//
+// Parameters:
+//
+//	o *Quadric - The quadric
+//	q Ray - The ray
+//
+// Returns:
+//
+//	ts []float64 - The ray parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrParallel if the ray lies in the surface,
+//	ErrNoIntersection if the ray misses the surface
+func (o *Quadric) IntersectRay(q Ray) (ts []float64, Ps []rn.Vec, err error) {
+	if q.V1.N != 3 {
+		panic(errors.ErrShape)
+	}
+	ts, Ps, err = intersectForm(o.A, q.V1, q.V2, 0, math.Inf(1))
+	return
+}
+
+// IntersectSegment returns the intersection points of a quadric and a segment
+//
+// Parameters:
+//
+//	o *Quadric - The quadric
+//	q Segment - The segment
+//
+// Returns:
+//
+//	ts []float64 - The segment parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrParallel if the segment lies in the surface,
+//	ErrNoIntersection if the segment does not cross the surface
+func (o *Quadric) IntersectSegment(q Segment) (ts []float64, Ps []rn.Vec, err error) {
+	if q.V1.N != 3 {
+		panic(errors.ErrShape)
+	}
+	ts, Ps, err = intersectForm(o.A, q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// TangentPlane returns the tangent plane of the quadric at P
+//
+// Parameters:
+//
+//	P rn.Vec - A point on the surface
+//
+// Returns:
+//
+//	plane Plane - The plane through P orthogonal to the gradient of the quadratic form, with
+//	orthonormal directions whose cross product is the outward normal
+//	err error - ErrDegenerate if the gradient vanishes, as at the apex of a cone
+func (o *Quadric) TangentPlane(P rn.Vec) (plane Plane, err error) {
+	if P.N != 3 {
+		panic(errors.ErrShape)
+	}
+	g, err := formGradient(o.A, P)
+	if err != nil {
+		return
+	}
+	u, v := orthonormalBasis(g)
+	plane = MakePlane(P, u, v)
+	return
+}
+
+// Polar returns the polar plane of a point with respect to the quadric
+//
+// For a point on the surface the polar is the tangent plane, for a point
+// outside it contains the points of tangency of the cone of tangents from P.
+//
+// Parameters:
+//
+//	P rn.Vec - The pole
+//
+// Returns:
+//
+//	plane Plane - The plane with homogeneous coordinates A * (P, 1)
+//	err error - ErrInfinity if the polar is the plane at infinity, as for the center of an
+//	ellipsoid, ErrDegenerate if P is a singular point of a degenerate quadric
+func (o *Quadric) Polar(P rn.Vec) (plane Plane, err error) {
+	if P.N != 3 {
+		panic(errors.ErrShape)
+	}
+	p := o.A.MulVec(ToHomogeneous(P))
+	Q, n, err := hyperplaneFromHomogeneous(p)
+	if err != nil {
+		return
+	}
+	u, v := orthonormalBasis(n)
+	plane = MakePlane(Q, u, v)
+	return
+}
+
+// Pole returns the pole of a plane with respect to the quadric
+//
+// Parameters:
+//
+//	plane Plane - The polar plane
+//
+// Returns:
+//
+//	P rn.Vec - The point whose polar is plane
+//	err error - ErrSingular if the quadric is degenerate, ErrInfinity if the pole is a point
+//	at infinity, as for a plane through the center
+func (o *Quadric) Pole(plane Plane) (P rn.Vec, err error) {
+	n := plane.Normal()
+	P, err = poleOf(o.A, n, -n.Dot(plane.V1))
+	return
+}
+
+// symmetricPart returns (A + Aᵀ) / 2
+func symmetricPart(A rn.Mat) (S rn.Mat) {
+	S = rn.MakeMat(A.M, A.N, 0)
+	for i := 0; i < A.M; i++ {
+		for j := 0; j < A.N; j++ {
+			S.Set(i, j, (A.Get(i, j)+A.Get(j, i))/2)
+		}
+	}
+	return
+}
+
+// matEqual returns true if A and B have the same shape and entries
+func matEqual(A, B rn.Mat) bool {
+	if A.M != B.M || A.N != B.N {
+		return false
+	}
+	for i, a := range A.Data {
+		if a != B.Data[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// centeredForm returns the matrix of (X - C)ᵀ * M * (X - C) + 2 * lᵀ * (X - C) + c
+func centeredForm(C rn.Vec, M rn.Mat, l rn.Vec, c float64) (A rn.Mat) {
+	n := C.N
+	MC := M.MulVec(C)
+	b := l.Sub(MC)
+	A = rn.MakeMat(n+1, n+1, 0)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			A.Set(i, j, M.Get(i, j))
+		}
+		A.Set(i, n, b.X[i])
+		A.Set(n, i, b.X[i])
+	}
+	A.Set(n, n, C.Dot(MC)-2*l.Dot(C)+c)
+	return
+}
+
+// axisProjector returns I - d * dᵀ for the unit direction d of axis, which measures the squared distance from it
+func axisProjector(axis Line) (M rn.Mat) {
+	if axis.V1.N != 3 || axis.V2.N != 3 {
+		panic(errors.ErrShape)
+	}
+	d := axis.V2.Scale(1 / axis.V2.Norm())
+	M = rn.MakeIdentity(3)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			M.Set(i, j, M.Get(i, j)-d.X[i]*d.X[j])
+		}
+	}
+	return
+}
+
+// evalForm returns Xᵀ * A * X for X = (P, 1)
+func evalForm(A rn.Mat, P rn.Vec) (val float64) {
+	X := ToHomogeneous(P)
+	AX := A.MulVec(X)
+	val = X.Dot(AX)
+	return
+}
+
+// formGradient returns the gradient of Xᵀ * A * X at P, up to the factor 2
+func formGradient(A rn.Mat, P rn.Vec) (g rn.Vec, err error) {
+	X := ToHomogeneous(P)
+	AX := A.MulVec(X)
+	g = rn.Vec{N: P.N, X: AX.X[:P.N]}
+	if g.Norm() <= eps*formScale(A)*X.Norm() {
+		err = errors.ErrDegenerate
+	}
+	return
+}
+
+// formScale returns a bound of the norm of A
+func formScale(A rn.Mat) (nrm float64) {
+	for _, x := range A.Data {
+		nrm = math.Max(nrm, math.Abs(x))
+	}
+	nrm *= float64(A.M)
+	return
+}
+
+// formInertia returns the numbers of positive and negative eigenvalues of the quadratic part
+// of A and of A itself, normalized for the sign of A, or false if the quadratic part is 0
+//
+// The inertia of A is found by completing the square: every non-zero eigenvalue of the
+// quadratic part removes the linear term along its eigenvector, the linear terms along the
+// null space add one positive and one negative eigenvalue, and otherwise the remaining
+// constant adds one of its sign.
+func formInertia(A rn.Mat) (key [4]int, ok bool) {
+	n := A.M - 1
+	E := rn.MakeMat(n, n, 0)
+	b := rn.MakeVec(n, 0)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			E.Set(i, j, A.Get(i, j))
+		}
+		b.X[i] = A.Get(i, n)
+	}
+	vals, vecs, _ := E.SymEigen()
+	scale := math.Max(math.Abs(vals.X[0]), math.Abs(vals.X[n-1]))
+	if scale == 0 {
+		return
+	}
+	pE, nE := 0, 0
+	c, cScale, bNull := A.Get(n, n), math.Abs(A.Get(n, n)), 0.0
+	for i, val := range vals.X {
+		v := vecs.GetCol(i)
+		bv := v.Dot(b)
+		if math.Abs(val) <= eps*scale {
+			bNull += bv * bv
+			continue
+		}
+		if val > 0 {
+			pE++
+		} else {
+			nE++
+		}
+		c -= bv * bv / val
+		cScale = math.Max(cScale, math.Abs(bv*bv/val))
+	}
+	pA, nA := pE, nE
+	switch {
+	case math.Sqrt(bNull) > eps*math.Max(b.Norm(), scale):
+		pA++
+		nA++
+	case c > eps*cScale:
+		pA++
+	case c < -eps*cScale:
+		nA++
+	}
+	// A and -A describe the same set
+	if pE < nE || pE == nE && pA < nA {
+		pE, nE, pA, nA = nE, pE, nA, pA
+	}
+	key, ok = [4]int{pE, nE, pA, nA}, true
+	return
+}
+
+// transformForm returns T⁻ᵀ * A * T⁻¹
+func transformForm(A, T rn.Mat) (A1 rn.Mat, err error) {
+	TInv, err := T.Inverse()
+	if err != nil {
+		return
+	}
+	TInvT := TInv.Transpose()
+	A1 = TInvT.Mul(A)
+	A1 = A1.Mul(TInv)
+	A1 = symmetricPart(A1)
+	return
+}
+
+// hyperplaneFromHomogeneous returns a point and the normal of the hyperplane nᵀ * x + c = 0 for h = (n, c)
+func hyperplaneFromHomogeneous(h rn.Vec) (Q, n rn.Vec, err error) {
+	k := h.N - 1
+	n = rn.Vec{N: k, X: append([]float64{}, h.X[:k]...)}
+	nrm := n.Norm()
+	switch {
+	case nrm == 0 && h.X[k] == 0:
+		err = errors.ErrDegenerate
+		return
+	case nrm == 0:
+		err = errors.ErrInfinity
+		return
+	}
+	n = n.Scale(1 / nrm)
+	Q = n.Scale(-h.X[k] / nrm)
+	for _, x := range Q.X {
+		if math.IsInf(x, 0) {
+			err = errors.ErrInfinity
+			return
+		}
+	}
+	return
+}
+
+// poleOf returns the point X with A * X proportional to the hyperplane (n, c)
+func poleOf(A rn.Mat, n rn.Vec, c float64) (P rn.Vec, err error) {
+	AInv, err := A.Inverse()
+	if err != nil {
+		return
+	}
+	h := rn.MakeVec(n.N+1, c)
+	copy(h.X, n.X)
+	P, err = FromHomogeneous(AInv.MulVec(h))
+	return
+}
+
+// intersectForm returns the points p + t*d with Xᵀ * A * X = 0 for t in [tMin, tMax]
+func intersectForm(A rn.Mat, p, d rn.Vec, tMin, tMax float64) (ts []float64, Ps []rn.Vec, err error) {
+	P, D := ToHomogeneous(p), MakePointAtInfinity(d)
+	AP, AD := A.MulVec(P), A.MulVec(D)
+	// (P + t*D)ᵀ * A * (P + t*D) = a*t² + 2*b*t + c
+	a, b, c := D.Dot(AD), D.Dot(AP), P.Dot(AP)
+	nA, nP, nD := formScale(A), P.Norm(), D.Norm()
+	var roots []float64
+	switch {
+	case math.Abs(a) > eps*nA*nD*nD:
+		disc := b*b - a*c
+		tol := eps * (b*b + math.Abs(a*c))
+		switch {
+		case disc < -tol:
+		case disc <= tol:
+			roots = []float64{-b / a}
+		default:
+			q := -(b + math.Copysign(math.Sqrt(disc), b))
+			roots = []float64{q / a, c / q}
+			if roots[0] > roots[1] {
+				roots[0], roots[1] = roots[1], roots[0]
+			}
+		}
+	case math.Abs(b) > eps*nA*nD*nP:
+		roots = []float64{-c / (2 * b)}
+	case math.Abs(c) <= eps*nA*nP*nP:
+		err = errors.ErrParallel
+		return
+	}
+	for _, t := range roots {
+		if t < tMin-eps || tMax+eps < t {
+			continue
+		}
+		t = clamp(t, tMin, tMax)
+		ts = append(ts, t)
+		Ps = append(Ps, p.Add(d.Scale(t)))
+	}
+	if len(ts) == 0 {
+		err = errors.ErrNoIntersection
+	}
+	return
+}
@@ -0,0 +1,156 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// quadricCoeffs returns the quadric a*x² + b*y² + c*z² + d*z + e = 0
+func quadricCoeffs(a, b, c, d, e float64) Quadric {
+	return MakeQuadric(rn.Mat{M: 4, N: 4, Data: []float64{
+		a, 0, 0, 0,
+		0, b, 0, 0,
+		0, 0, c, d / 2,
+		0, 0, d / 2, e,
+	}})
+}
+
+func TestQuadricClassify(t *testing.T) {
+	zAxis := MakeLine(v3(0, 0, 0), v3(0, 0, 1))
+	tests := []struct {
+		quadric Quadric
+		want    QuadricType
+	}{
+		{MakeEllipsoid(v3(1, 2, 3), v3(1, 2, 3)), QuadricEllipsoid},
+		{MakeEllipsoid(v3(1e4, 0, 0), v3(1, 1, 1)), QuadricEllipsoid},
+		{MakeEllipsoid(v3(0, 0, 0), v3(1e-3, 1e-3, 1e-3)), QuadricEllipsoid},
+		{MakeCylinder(MakeLine(v3(1, 0, 0), v3(1, 1, 1)), 2), QuadricEllipticCylinder},
+		{MakeCone(v3(1, 1, 1), v3(0, 1, 0), 0.3), QuadricCone},
+		{MakeParaboloid(zAxis, 0.25), QuadricEllipticParaboloid},
+		{quadricCoeffs(1, 1, 1, 0, 1), QuadricImaginaryEllipsoid},
+		{quadricCoeffs(1, 1, -1, 0, -1), QuadricHyperboloidOneSheet},
+		{quadricCoeffs(1, 1, -1, 0, 1), QuadricHyperboloidTwoSheets},
+		{quadricCoeffs(1, 1, 1, 0, 0), QuadricImaginaryCone},
+		{quadricCoeffs(1, -1, 0, -1, 0), QuadricHyperbolicParaboloid},
+		{quadricCoeffs(1, 1, 0, 0, 1), QuadricImaginaryCylinder},
+		{quadricCoeffs(1, -1, 0, 0, -1), QuadricHyperbolicCylinder},
+		{quadricCoeffs(1, 0, 0, -1, 0), QuadricParabolicCylinder},
+		{quadricCoeffs(1, -1, 0, 0, 0), QuadricIntersectingPlanes},
+		{quadricCoeffs(1, 1, 0, 0, 0), QuadricImaginaryIntersectingPlanes},
+		{quadricCoeffs(1, 0, 0, 0, -1), QuadricParallelPlanes},
+		{quadricCoeffs(1, 0, 0, 0, 1), QuadricImaginaryParallelPlanes},
+		{quadricCoeffs(1, 0, 0, 0, 0), QuadricCoincidentPlanes},
+		{quadricCoeffs(0, 0, 0, 1, 0), QuadricNone},
+	}
+	for _, test := range tests {
+		if got := test.quadric.Classify(); got != test.want {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.want)
+		}
+	}
+
+	// the class is invariant under rigid motions
+	T := MakeRotationAxisAngle(v3(1, 2, 3), 0.7)
+	H := rn.MakeIdentity(4)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			H.Set(i, j, T.Get(i, j))
+		}
+	}
+	H.SetCol(3, rn.Vec{N: 4, X: []float64{5, -6, 7, 1}})
+	for _, test := range tests {
+		moved, err := test.quadric.Transform(H)
+		if err != nil || moved.Classify() != test.want {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v", moved.Classify(), err, test.want)
+		}
+	}
+}
+
+func TestQuadricIntersectLine(t *testing.T) {
+	zAxis := MakeLine(v3(0, 0, 0), v3(0, 0, 1))
+	ellipsoid := MakeEllipsoid(v3(1, 1, 1), v3(1, 2, 3))
+	cylinder := MakeCylinder(zAxis, 2)
+	cone := MakeCone(v3(0, 0, 0), v3(0, 0, 1), math.Pi/4)
+	paraboloid := MakeParaboloid(zAxis, 0.25)
+	tests := []struct {
+		quadric Quadric
+		line    Line
+		Ps      []rn.Vec
+		err     error
+	}{
+		{ellipsoid, MakeLine(v3(1, 1, 1), v3(0, 0, 1)), []rn.Vec{v3(1, 1, -2), v3(1, 1, 4)}, nil},
+		{ellipsoid, MakeLine(v3(2, -5, 1), v3(0, 1, 0)), []rn.Vec{v3(2, 1, 1)}, nil},
+		{ellipsoid, MakeLine(v3(3, 1, 1), v3(0, 1, 1)), nil, errors.ErrNoIntersection},
+		{cylinder, MakeLine(v3(0, 1, 5), v3(1, 0, 0)), []rn.Vec{v3(-math.Sqrt(3), 1, 5), v3(math.Sqrt(3), 1, 5)}, nil},
+		{cylinder, MakeLine(v3(2, 0, 0), v3(0, 0, 3)), nil, errors.ErrParallel},
+		{cylinder, MakeLine(v3(1, 0, 0), v3(0, 0, 3)), nil, errors.ErrNoIntersection},
+		{cone, MakeLine(v3(0, 0, 1), v3(1, 0, 0)), []rn.Vec{v3(-1, 0, 1), v3(1, 0, 1)}, nil},
+		{cone, MakeLine(v3(0, 0, 0), v3(1, 1, math.Sqrt(2))), nil, errors.ErrParallel},
+		{paraboloid, MakeLine(v3(1, 1, -3), v3(0, 0, 1)), []rn.Vec{v3(1, 1, 2)}, nil},
+		{paraboloid, MakeLine(v3(-2, 0, 1), v3(1, 0, 0)), []rn.Vec{v3(-1, 0, 1), v3(1, 0, 1)}, nil},
+	}
+	for _, test := range tests {
+		ts, Ps, err := test.quadric.IntersectLine(test.line)
+		if err != test.err || len(Ps) != len(test.Ps) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", Ps, err, test.Ps, test.err)
+			continue
+		}
+		for i := range Ps {
+			if !vecClose(Ps[i], test.Ps[i], 1e-12) || !vecClose(test.line.At(ts[i]), Ps[i], 1e-12) {
+				t.Errorf("error:\ngot=%v\nwant=%v", Ps[i], test.Ps[i])
+			}
+		}
+	}
+
+	if got := ellipsoid.Eval(v3(1, 1, 1)); got >= 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "< 0")
+	}
+	if _, Ps, err := ellipsoid.IntersectRay(MakeRay(v3(1, 1, 1), v3(1, 0, 0))); err != nil || len(Ps) != 1 || !vecClose(Ps[0], v3(2, 1, 1), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", Ps, err, v3(2, 1, 1))
+	}
+	if _, _, err := ellipsoid.IntersectSegment(MakeSegment(v3(1, 1, 1), v3(1.5, 1, 1))); err != errors.ErrNoIntersection {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrNoIntersection)
+	}
+}
+
+func TestQuadricPolar(t *testing.T) {
+	sphere := MakeEllipsoid(v3(0, 0, 0), v3(1, 1, 1))
+	plane, err := sphere.TangentPlane(v3(0, 0.6, 0.8))
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if n := plane.Normal(); !vecClose(n, v3(0, 0.6, 0.8), 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", n, v3(0, 0.6, 0.8))
+	}
+
+	// the polar of (0, 0, 2) is the plane z = 1/2 of the circle of tangency
+	polar, err := sphere.Polar(v3(0, 0, 2))
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if d := polar.SignedDist(v3(5, -3, 0.5)); math.Abs(d) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", d, 0)
+	}
+	if P, err := sphere.Pole(polar); err != nil || !vecClose(P, v3(0, 0, 2), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", P, err, v3(0, 0, 2))
+	}
+	// for a point on the surface the polar is the tangent plane
+	polar, _ = sphere.Polar(v3(0, 0.6, 0.8))
+	if n := polar.Normal(); !vecClose(n, v3(0, 0.6, 0.8), 1e-12) || math.Abs(polar.SignedDist(v3(0, 0.6, 0.8))) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", polar, plane)
+	}
+
+	if _, err := sphere.Polar(v3(0, 0, 0)); err != errors.ErrInfinity {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrInfinity)
+	}
+	cone := MakeCone(v3(1, 2, 3), v3(0, 0, 1), 0.5)
+	if _, err := cone.TangentPlane(v3(1, 2, 3)); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+	cylinder := MakeCylinder(MakeLine(v3(0, 0, 0), v3(0, 0, 1)), 1)
+	if _, err := cylinder.Pole(polar); err != errors.ErrSingular {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrSingular)
+	}
+}
@@ -0,0 +1,208 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/rn"
+)
+
+// Ray is the half line that starts at V1 and extends in direction V2
+type Ray struct {
+	V1, V2 rn.Vec
+}
+
+func (o Ray) String() (str string) {
+	str += fmt.Sprintf("x = %v + λ * %v, λ >= 0", o.V1, o.V2)
+	return
+}
+
+// MakeRay returns a Ray object that starts at P1 and has direction D1
+//
+// Parameters:
+//
+//	P1 rn.Vec - The origin
+//	D1 rn.Vec - The direction
+//
+// Returns:
+//
+//	ray Ray - The ray that starts at P1 and has direction D1
+func MakeRay(P1, D1 rn.Vec) (ray Ray) {
+	ray.V1 = P1
+	ray.V2 = D1
+	return
+}
+
+// MakeRayByPoints returns a Ray object that starts at P1 and passes through P2
+//
+// Parameters:
+//
+//	P1 rn.Vec - The origin
+//	P2 rn.Vec - A second point on the ray
+//
+// Returns:
+//
+//	ray Ray - The ray that starts at P1 and reaches P2 at λ = 1
+func MakeRayByPoints(P1, P2 rn.Vec) (ray Ray) {
+	ray.V1 = P1
+	ray.V2 = P2.Sub(P1)
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Ray - ray to compare to q
+//	q Ray - ray to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *Ray) Equal(q Ray) bool {
+	return o.V1.Equal(q.V1) && o.V2.Equal(q.V2)
+}
+
+// Line returns the line that contains the ray
+//
+// Parameters:
+//
+//	o *Ray - The ray
+//
+// Returns:
+//
+//	line Line - The line with the same origin and direction
+func (o *Ray) Line() (line Line) {
+	line = MakeLine(o.V1, o.V2)
+	return
+}
+
+// Clamp restricts a line parameter to the ray
+//
+// Parameters:
+//
+//	s float64 - A parameter of the line through the ray
+//
+// Returns:
+//
+//	float64 - s clamped to [0, ∞)
+func (o *Ray) Clamp(s float64) float64 {
+	return math.Max(s, 0)
+}
+
+// At returns the point V1 + s * V2
+//
+// Parameters:
+//
+//	s float64 - The parameter
+//
+// Returns:
+//
+//	P1 rn.Vec - The point at parameter s
+func (o *Ray) At(s float64) (P1 rn.Vec) {
+	P1 = o.V1.Add(o.V2.Scale(s))
+	return
+}
+
+// ClosestPoint returns the point on the ray closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	s float64 - The parameter of the closest point in [0, ∞)
+//	P1 rn.Vec - The closest point
+func (o *Ray) ClosestPoint(P rn.Vec) (s float64, P1 rn.Vec) {
+	if dd := o.V2.Dot(o.V2); dd > 0 {
+		w := P.Sub(o.V1)
+		s = math.Max(o.V2.Dot(w)/dd, 0)
+	}
+	P1 = o.At(s)
+	return
+}
+
+// Dist returns the distance between P and the ray
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance from P to its closest point on the ray
+func (o *Ray) Dist(P rn.Vec) (dist float64) {
+	_, P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
+
+// IntersectLine returns the intersection point of a ray and a line (if it exists)
+//
+// Parameters:
+//
+//	o *Ray - The ray
+//	q Line - The line
+//
+// Returns:
+//
+//	x rn.Vec - The parameter s of the ray and λ of the line
+//	P1 rn.Vec - The intersection point
+//	err error - ErrNoIntersection if they do not meet
+func (o *Ray) IntersectLine(q Line) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectParametric(o.V1, o.V2, 0, math.Inf(1), q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectPlane returns the intersection point of a ray and a plane (if it exists)
+//
+// Parameters:
+//
+//	o *Ray - The ray
+//	plane Plane - The plane
+//
+// Returns:
+//
+//	x rn.Vec - The parameter s of the ray and λ, μ of the plane
+//	P1 rn.Vec - The intersection point
+//	err error - ErrParallel if the ray is parallel to the plane,
+//	ErrNoIntersection if the plane lies behind the ray
+func (o *Ray) IntersectPlane(plane Plane) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectPlane(o.V1, o.V2, 0, math.Inf(1), plane)
+	return
+}
+
+// IntersectSegment returns the intersection point of a ray and a segment (if it exists)
+//
+// Parameters:
+//
+//	o *Ray - The ray
+//	q Segment - The segment
+//
+// Returns:
+//
+//	x rn.Vec - The parameters s of o and t of q
+//	P1 rn.Vec - The intersection point
+//	err error - ErrNoIntersection if they do not meet
+func (o *Ray) IntersectSegment(q Segment) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectParametric(o.V1, o.V2, 0, math.Inf(1), q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// IntersectRay returns the intersection point of two rays (if it exists)
+//
+// Parameters:
+//
+//	o *Ray - The first ray
+//	q Ray - The second ray
+//
+// Returns:
+//
+//	x rn.Vec - The parameters s of o and t of q
+//	P1 rn.Vec - The intersection point
+//	err error - ErrNoIntersection if they do not meet
+func (o *Ray) IntersectRay(q Ray) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectParametric(o.V1, o.V2, 0, math.Inf(1), q.V1, q.V2, 0, math.Inf(1))
+	return
+}
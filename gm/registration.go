@@ -0,0 +1,199 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+	"github.com/add1609/lin/spatial"
+)
+
+// Similarity is the transform P ↦ Scale * Rot * P + Trans
+type Similarity struct {
+	Rot   rn.Mat
+	Trans rn.Vec
+	Scale float64
+}
+
+func (o Similarity) String() (str string) {
+	str += fmt.Sprintf("scale = %v, trans = %v, rot =\n%v", o.Scale, o.Trans, o.Rot)
+	return
+}
+
+// MakeSimilarity returns the identity transform in n dimensions
+//
+// Parameters:
+//
+//	n int - The dimension
+//
+// Returns:
+//
+//	tf Similarity - The identity
+func MakeSimilarity(n int) (tf Similarity) {
+	tf.Rot = rn.MakeIdentity(n)
+	tf.Trans = rn.MakeVec(n, 0)
+	tf.Scale = 1
+	return
+}
+
+// Apply returns the image of P under the transform
+//
+// Parameters:
+//
+//	P rn.Vec - The point
+//
+// Returns:
+//
+//	P1 rn.Vec - Scale * Rot * P + Trans
+func (o *Similarity) Apply(P rn.Vec) (P1 rn.Vec) {
+	P1 = o.Rot.MulVec(P)
+	P1 = P1.Scale(o.Scale)
+	P1 = P1.Add(o.Trans)
+	return
+}
+
+// Align returns the transform that best maps src onto the corresponding points of dst
+//
+// The rotation, translation and optionally the scale minimize the sum of
+// squared distances between the transformed src and dst (Kabsch's algorithm,
+// with Umeyama's scale estimate). The rotation is proper, reflections are
+// never returned.
+//
+// Parameters:
+//
+//	src []rn.Vec - The points to move
+//	dst []rn.Vec - The target points, dst[k] corresponds to src[k]
+//	scale bool - true to estimate the scale, false to keep it at 1
+//
+// Returns:
+//
+//	tf Similarity - The transform
+//	rms float64 - The root mean square distance between the transformed src and dst
+//	err error - ErrDegenerate if src spans less than a hyperplane, so the rotation is not unique
+func Align(src, dst []rn.Vec, scale bool) (tf Similarity, rms float64, err error) {
+	if len(src) != len(dst) {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	if len(src) == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	n := src[0].N
+	checkDims(src, n)
+	checkDims(dst, n)
+	muS, muD := centroid(src), centroid(dst)
+	// cross-covariance of the centered point sets and the variance of src
+	cov := rn.MakeMat(n, n, 0)
+	var varS float64
+	for k := range src {
+		s, d := src[k].Sub(muS), dst[k].Sub(muD)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				cov.Data[i+j*n] += d.X[i] * s.X[j]
+			}
+		}
+		varS += s.Dot(s)
+	}
+	U, D, V := cov.SVD()
+	if n > 1 && D.X[n-2] <= eps*D.X[0] || D.X[0] == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	// flip the weakest direction if U * Vᵀ would be a reflection
+	if U.Det()*V.Det() < 0 {
+		last := U.GetCol(n - 1)
+		U.SetCol(n-1, last.Scale(-1))
+		D.X[n-1] = -D.X[n-1]
+	}
+	VT := V.Transpose()
+	tf.Rot = U.Mul(VT)
+	tf.Scale = 1
+	if scale {
+		var trace float64
+		for _, d := range D.X {
+			trace += d
+		}
+		tf.Scale = trace / varS
+	}
+	tf.Trans = rn.MakeVec(n, 0)
+	tf.Trans = muD.Sub(tf.Apply(muS))
+	rms = alignmentRMS(tf, src, dst)
+	return
+}
+
+// ICP aligns src to dst without known correspondences using the iterative closest point algorithm
+//
+// Every iteration pairs each transformed src point with its nearest point
+// in dst and re-estimates the transform with Align. The iteration stops
+// when the root mean square distance improves by less than tol. ICP only
+// converges to the correct alignment if src starts close to it.
+//
+// Parameters:
+//
+//	src []rn.Vec - The points to move
+//	dst []rn.Vec - The target points
+//	scale bool - true to estimate the scale, false to keep it at 1
+//	iterations int - The maximum number of iterations
+//	tol float64 - The minimum improvement of the residual per iteration
+//
+// Returns:
+//
+//	tf Similarity - The transform
+//	rms float64 - The root mean square distance between the transformed src and their nearest points in dst
+//	err error - ErrDegenerate if an alignment step fails
+func ICP(src, dst []rn.Vec, scale bool, iterations int, tol float64) (tf Similarity, rms float64, err error) {
+	if len(src) == 0 || len(dst) == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	tree := spatial.MakeKDTree(dst, spatial.L2)
+	tf = MakeSimilarity(src[0].N)
+	matched := make([]rn.Vec, len(src))
+	rms = nearestRMS(tf, src, &tree, matched)
+	for it := 0; it < iterations; it++ {
+		var next Similarity
+		if next, _, err = Align(src, matched, scale); err != nil {
+			return
+		}
+		prev := rms
+		tf = next
+		if rms = nearestRMS(tf, src, &tree, matched); prev-rms < tol {
+			break
+		}
+	}
+	return
+}
+
+// nearestRMS matches the transformed src points with their nearest points in tree and returns the residual
+func nearestRMS(tf Similarity, src []rn.Vec, tree *spatial.KDTree, matched []rn.Vec) (rms float64) {
+	for k, P := range src {
+		P1 := tf.Apply(P)
+		idx, dist := tree.Nearest(P1)
+		matched[k] = tree.Points[idx]
+		rms += dist * dist
+	}
+	rms = math.Sqrt(rms / float64(len(src)))
+	return
+}
+
+// alignmentRMS returns the root mean square distance between the transformed src and dst
+func alignmentRMS(tf Similarity, src, dst []rn.Vec) (rms float64) {
+	for k, P := range src {
+		P1 := tf.Apply(P)
+		d := P1.Dist(dst[k])
+		rms += d * d
+	}
+	rms = math.Sqrt(rms / float64(len(src)))
+	return
+}
+
+// centroid returns the mean of a non-empty point set
+func centroid(Ps []rn.Vec) (mean rn.Vec) {
+	mean = rn.MakeVec(Ps[0].N, 0)
+	for _, P := range Ps {
+		mean = mean.Add(P)
+	}
+	mean = mean.Scale(1 / float64(len(Ps)))
+	return
+}
@@ -0,0 +1,119 @@
+package gm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// randomCloud returns n points uniformly distributed in an anisotropic box
+func randomCloud(rng *rand.Rand, n int) (Ps []rn.Vec) {
+	for k := 0; k < n; k++ {
+		Ps = append(Ps, v3(4*rng.Float64(), 2*rng.Float64(), rng.Float64()))
+	}
+	return
+}
+
+func transformAll(tf Similarity, Ps []rn.Vec) (Qs []rn.Vec) {
+	for _, P := range Ps {
+		Qs = append(Qs, tf.Apply(P))
+	}
+	return
+}
+
+func TestAlign(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	src := randomCloud(rng, 50)
+	want := Similarity{Rot: MakeRotationAxisAngle(v3(1, 2, 3), 2), Trans: v3(1, -2, 0.5), Scale: 1}
+	tests := []struct {
+		scale bool
+		want  float64
+	}{
+		{false, 1},
+		{true, 2.5},
+	}
+	for _, test := range tests {
+		want.Scale = test.want
+		dst := transformAll(want, src)
+		tf, rms, err := Align(src, dst, test.scale)
+		if err != nil {
+			t.Fatalf("error:\n%v\n", err)
+		}
+		if !matClose(tf.Rot, want.Rot, 1e-12) || !vecClose(tf.Trans, want.Trans, 1e-12) ||
+			math.Abs(tf.Scale-want.Scale) > 1e-12 || rms > 1e-12 {
+			t.Errorf("error:\ngot=%v, rms=%v\nwant=%v", tf, rms, want)
+		}
+	}
+
+	// a mirror image is matched by the best proper rotation
+	var mirrored []rn.Vec
+	for _, P := range src {
+		mirrored = append(mirrored, v3(P.X[0], P.X[1], -P.X[2]))
+	}
+	tf, rms, err := Align(src, mirrored, false)
+	if err != nil || tf.Rot.Det() < 0.999 || rms == 0 {
+		t.Errorf("error:\ngot=%v, rms=%v, %v\nwant=%v", tf, rms, err, "a proper rotation")
+	}
+
+	// coplanar points determine the rotation, collinear points do not
+	square := []rn.Vec{v3(0, 0, 0), v3(1, 0, 0), v3(1, 1, 0), v3(0, 1, 0)}
+	want = Similarity{Rot: MakeRotationAxisAngle(v3(1, 0, 0), math.Pi/2), Trans: v3(0, 0, 1), Scale: 1}
+	if tf, _, err := Align(square, transformAll(want, square), false); err != nil || !matClose(tf.Rot, want.Rot, 1e-12) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", tf, err, want)
+	}
+	// points spanning a hyperplane in n >= 4 dimensions, the unit vectors on x_1 + ... + x_n = 1,
+	// determine a proper rotation
+	for n := 4; n <= 6; n++ {
+		var simplex []rn.Vec
+		for i := 0; i < n; i++ {
+			e := rn.MakeVec(n, 0)
+			e.X[i] = 1
+			simplex = append(simplex, e)
+		}
+		rot := rn.MakeIdentity(n)
+		c, s := math.Cos(0.7), math.Sin(0.7)
+		rot.Set(0, 0, c)
+		rot.Set(0, 2, -s)
+		rot.Set(2, 0, s)
+		rot.Set(2, 2, c)
+		for _, R := range []rn.Mat{rn.MakeIdentity(n), rot} {
+			want := Similarity{Rot: R, Trans: rn.MakeVec(n, 0), Scale: 1}
+			tf, rms, err := Align(simplex, transformAll(want, simplex), false)
+			if err != nil {
+				t.Fatalf("error:\n%v\n", err)
+			}
+			RT := tf.Rot.Transpose()
+			if prod := RT.Mul(tf.Rot); !matClose(prod, rn.MakeIdentity(n), 1e-12) || math.Abs(tf.Rot.Det()-1) > 1e-12 {
+				t.Errorf("error:\ngot=\n%v\nwant=%v", tf.Rot, "a proper rotation")
+			}
+			if !matClose(tf.Rot, R, 1e-12) || rms > 1e-12 {
+				t.Errorf("error:\ngot=\n%v, rms=%v\nwant=\n%v", tf.Rot, rms, R)
+			}
+		}
+	}
+	line := []rn.Vec{v3(0, 0, 0), v3(1, 1, 1), v3(2, 2, 2)}
+	if _, _, err := Align(line, line, false); err != errors.ErrDegenerate {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrDegenerate)
+	}
+}
+
+func TestICP(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	dst := randomCloud(rng, 400)
+	// src is a subset of dst moved by a small rigid motion
+	want := Similarity{Rot: MakeRotationAxisAngle(v3(0, 0, 1), 0.1), Trans: v3(0.05, -0.03, 0.02), Scale: 1}
+	inv := Similarity{Rot: want.Rot.Transpose(), Scale: 1}
+	inv.Trans = inv.Rot.MulVec(want.Trans)
+	inv.Trans = inv.Trans.Scale(-1)
+	src := transformAll(inv, dst[:200])
+	tf, rms, err := ICP(src, dst, false, 100, 1e-12)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	if !matClose(tf.Rot, want.Rot, 1e-9) || !vecClose(tf.Trans, want.Trans, 1e-9) || rms > 1e-9 {
+		t.Errorf("error:\ngot=%v, rms=%v\nwant=%v", tf, rms, want)
+	}
+}
@@ -0,0 +1,349 @@
+package gm
+
+import (
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// EulerOrder identifies the sequence of coordinate axes a set of Euler angles rotates about
+type EulerOrder int
+
+// The six Tait-Bryan orders (three distinct axes) followed by the six proper Euler orders
+// (first and last axis equal)
+const (
+	XYZ EulerOrder = iota
+	XZY
+	YXZ
+	YZX
+	ZXY
+	ZYX
+	XYX
+	XZX
+	YXY
+	YZY
+	ZXZ
+	ZYZ
+)
+
+var eulerAxes = [...][3]int{
+	XYZ: {0, 1, 2},
+	XZY: {0, 2, 1},
+	YXZ: {1, 0, 2},
+	YZX: {1, 2, 0},
+	ZXY: {2, 0, 1},
+	ZYX: {2, 1, 0},
+	XYX: {0, 1, 0},
+	XZX: {0, 2, 0},
+	YXY: {1, 0, 1},
+	YZY: {1, 2, 1},
+	ZXZ: {2, 0, 2},
+	ZYZ: {2, 1, 2},
+}
+
+func (o EulerOrder) String() (str string) {
+	if o < 0 || int(o) >= len(eulerAxes) {
+		return "EulerOrder(?)"
+	}
+	for _, axis := range eulerAxes[o] {
+		str += string(rune('X' + axis))
+	}
+	return
+}
+
+// EulerFrame selects whether Euler angles rotate about the moving or the fixed axes
+type EulerFrame int
+
+const (
+	Intrinsic EulerFrame = iota // each rotation is about an axis of the already rotated frame
+	Extrinsic                   // each rotation is about an axis of the fixed world frame
+)
+
+// gimbalTol is the value of the middle angle's sine/cosine below which
+// EulerAngles treats a rotation as gimbal locked
+const gimbalTol = 1e-10
+
+// MakeRotation returns the 3 x 3 matrix that rotates by angle about a coordinate axis
+//
+// Parameters:
+//
+//	axis int - The coordinate axis (0 = x, 1 = y, 2 = z)
+//	angle float64 - The rotation angle in radians
+//
+// Returns:
+//
+//	rot rn.Mat - The rotation matrix
+func MakeRotation(axis int, angle float64) (rot rn.Mat) {
+	if axis < 0 || 2 < axis {
+		panic(errors.ErrIndexOutOfRange)
+	}
+	i, j := (axis+1)%3, (axis+2)%3
+	c, s := math.Cos(angle), math.Sin(angle)
+	rot = rn.MakeIdentity(3)
+	rot.Set(i, i, c)
+	rot.Set(i, j, -s)
+	rot.Set(j, i, s)
+	rot.Set(j, j, c)
+	return
+}
+
+// MakeRotationEuler returns the rotation matrix described by a set of Euler angles
+//
+// For the intrinsic order XYZ the result is Rx(a) * Ry(b) * Rz(c), for the
+// extrinsic order XYZ it is Rz(c) * Ry(b) * Rx(a).
+//
+// Parameters:
+//
+//	angles rn.Vec - The three angles in radians, in the order the axes are listed
+//	order EulerOrder - The sequence of rotation axes
+//	frame EulerFrame - Whether the rotations are intrinsic or extrinsic
+//
+// Returns:
+//
+//	rot rn.Mat - The rotation matrix
+func MakeRotationEuler(angles rn.Vec, order EulerOrder, frame EulerFrame) (rot rn.Mat) {
+	if angles.N != 3 {
+		panic(errors.ErrShape)
+	}
+	axes := eulerAxes[order]
+	rot = rn.MakeIdentity(3)
+	for k := 0; k < 3; k++ {
+		r := MakeRotation(axes[k], angles.X[k])
+		if frame == Intrinsic {
+			rot = rot.Mul(r)
+		} else {
+			rot = r.Mul(rot)
+		}
+	}
+	return
+}
+
+// EulerAngles decomposes a rotation matrix into Euler angles
+//
+// The first and last angles lie in (-π, π]. The middle angle lies in
+// [-π/2, π/2] for Tait-Bryan orders and in [0, π] for proper Euler orders.
+// At gimbal lock only the sum or difference of the outer angles is defined;
+// in that case the last angle (in application order) is set to 0 and locked is true.
+//
+// Parameters:
+//
+//	rot rn.Mat - The 3 x 3 rotation matrix
+//	order EulerOrder - The sequence of rotation axes
+//	frame EulerFrame - Whether the rotations are intrinsic or extrinsic
+//
+// Returns:
+//
+//	angles rn.Vec - The three angles in radians, in the order the axes are listed
+//	locked bool - true if the rotation is at gimbal lock
+func EulerAngles(rot rn.Mat, order EulerOrder, frame EulerFrame) (angles rn.Vec, locked bool) {
+	if rot.M != 3 || rot.N != 3 {
+		panic(errors.ErrShape)
+	}
+	axes := eulerAxes[order]
+	if frame == Extrinsic {
+		// an extrinsic sequence equals the reversed intrinsic sequence
+		axes[0], axes[2] = axes[2], axes[0]
+	}
+	var a, b, c float64
+	if axes[0] == axes[2] {
+		a, b, c, locked = properEuler(rot, axes[0], axes[1])
+	} else {
+		a, b, c, locked = taitBryan(rot, axes[0], axes[1], axes[2])
+	}
+	if frame == Extrinsic {
+		a, c = c, a
+	}
+	angles = rn.Vec{N: 3, X: []float64{a, b, c}}
+	return
+}
+
+// axisParity returns 1 if (i, j, k) is a cyclic permutation of (0, 1, 2), -1 otherwise
+func axisParity(i, j, k int) float64 {
+	if (j-i+3)%3 == 1 && (k-j+3)%3 == 1 {
+		return 1
+	}
+	return -1
+}
+
+// taitBryan decomposes rot = R_i(a) * R_j(b) * R_k(c) for distinct axes i, j, k
+func taitBryan(rot rn.Mat, i, j, k int) (a, b, c float64, locked bool) {
+	s := axisParity(i, j, k)
+	cb := math.Hypot(rot.Get(i, i), rot.Get(i, j))
+	b = math.Atan2(s*rot.Get(i, k), cb)
+	if cb < gimbalTol {
+		// R * e_j = R_i(a) * e_j independently of b and c
+		a = math.Atan2(s*rot.Get(k, j), rot.Get(j, j))
+		locked = true
+		return
+	}
+	a = math.Atan2(-s*rot.Get(j, k), rot.Get(k, k))
+	c = math.Atan2(-s*rot.Get(i, j), rot.Get(i, i))
+	return
+}
+
+// properEuler decomposes rot = R_i(a) * R_j(b) * R_i(c) for distinct axes i, j
+func properEuler(rot rn.Mat, i, j int) (a, b, c float64, locked bool) {
+	m := 3 - i - j
+	s := axisParity(i, j, m)
+	sb := math.Hypot(rot.Get(i, j), rot.Get(i, m))
+	b = math.Atan2(sb, rot.Get(i, i))
+	if sb < gimbalTol {
+		a = math.Atan2(s*rot.Get(m, j), rot.Get(j, j))
+		locked = true
+		return
+	}
+	a = math.Atan2(rot.Get(j, i), -s*rot.Get(m, i))
+	c = math.Atan2(rot.Get(i, j), s*rot.Get(i, m))
+	return
+}
+
+// MakeRotationAxisAngle returns the matrix that rotates by angle about axis (Rodrigues' formula)
+//
+// Parameters:
+//
+//	axis rn.Vec - The rotation axis, need not be normalized
+//	angle float64 - The rotation angle in radians
+//
+// Returns:
+//
+//	rot rn.Mat - The rotation matrix
+func MakeRotationAxisAngle(axis rn.Vec, angle float64) (rot rn.Mat) {
+	if axis.N != 3 {
+		panic(errors.ErrShape)
+	}
+	rot = rn.MakeIdentity(3)
+	nrm := axis.Norm()
+	if nrm == 0 || angle == 0 {
+		return
+	}
+	x, y, z := axis.X[0]/nrm, axis.X[1]/nrm, axis.X[2]/nrm
+	c, s := math.Cos(angle), math.Sin(angle)
+	t := 1 - c
+	rot.Data = []float64{
+		t*x*x + c, t*x*y + s*z, t*x*z - s*y,
+		t*x*y - s*z, t*y*y + c, t*y*z + s*x,
+		t*x*z + s*y, t*y*z - s*x, t*z*z + c,
+	}
+	return
+}
+
+// AxisAngle returns the axis and angle of a rotation matrix
+//
+// Parameters:
+//
+//	rot rn.Mat - The 3 x 3 rotation matrix
+//
+// Returns:
+//
+//	axis rn.Vec - The unit rotation axis, (1, 0, 0) for the identity
+//	angle float64 - The rotation angle in [0, π]
+func AxisAngle(rot rn.Mat) (axis rn.Vec, angle float64) {
+	if rot.M != 3 || rot.N != 3 {
+		panic(errors.ErrShape)
+	}
+	w := rn.Vec{N: 3, X: []float64{
+		rot.Get(2, 1) - rot.Get(1, 2),
+		rot.Get(0, 2) - rot.Get(2, 0),
+		rot.Get(1, 0) - rot.Get(0, 1),
+	}}
+	cos := (rot.Get(0, 0) + rot.Get(1, 1) + rot.Get(2, 2) - 1) / 2
+	sin := w.Norm() / 2
+	angle = math.Atan2(sin, cos)
+	switch {
+	case sin == 0 && cos > 0:
+		axis = rn.Vec{N: 3, X: []float64{1, 0, 0}}
+		angle = 0
+	case cos >= 0:
+		axis = w.Scale(1 / w.Norm())
+	default:
+		// near π the antisymmetric part vanishes; recover the axis from
+		// the symmetric part (R + Rᵀ)/2 = cos * I + (1 - cos) * n * nᵀ
+		k := 0
+		for i := 1; i < 3; i++ {
+			if rot.Get(i, i) > rot.Get(k, k) {
+				k = i
+			}
+		}
+		axis = rn.MakeVec(3, 0)
+		for i := 0; i < 3; i++ {
+			axis.X[i] = (rot.Get(i, k) + rot.Get(k, i)) / 2
+		}
+		axis.X[k] -= cos
+		axis = axis.Scale(1 / axis.Norm())
+		if axis.Dot(w) < 0 {
+			axis = axis.Scale(-1)
+		}
+	}
+	return
+}
+
+// MakeRotationVec returns the rotation matrix of a rotation vector
+//
+// Parameters:
+//
+//	v rn.Vec - The rotation vector, its direction is the axis and its norm the angle
+//
+// Returns:
+//
+//	rot rn.Mat - The rotation matrix
+func MakeRotationVec(v rn.Vec) (rot rn.Mat) {
+	rot = MakeRotationAxisAngle(v, v.Norm())
+	return
+}
+
+// RotationVec returns the rotation vector of a rotation matrix
+//
+// Parameters:
+//
+//	rot rn.Mat - The 3 x 3 rotation matrix
+//
+// Returns:
+//
+//	v rn.Vec - The rotation vector, its direction is the axis and its norm the angle
+func RotationVec(rot rn.Mat) (v rn.Vec) {
+	axis, angle := AxisAngle(rot)
+	v = axis.Scale(angle)
+	return
+}
+
+// Orthonormalize returns the rotation matrix closest to a drifted rotation matrix
+//
+// The projection is the orthogonal factor of the polar decomposition,
+// computed by the Newton iteration X = (X + X⁻ᵀ) / 2.
+//
+// Parameters:
+//
+//	rot rn.Mat - A 3 x 3 matrix that is approximately a rotation
+//
+// Returns:
+//
+//	proj rn.Mat - The closest rotation matrix in the Frobenius norm
+//	err error - ErrRotation if rot has a non-positive determinant
+func Orthonormalize(rot rn.Mat) (proj rn.Mat, err error) {
+	if rot.M != 3 || rot.N != 3 {
+		panic(errors.ErrShape)
+	}
+	if rot.Det() <= 0 {
+		err = errors.ErrRotation
+		return
+	}
+	proj = rot.GetCopy()
+	for iter := 0; iter < 100; iter++ {
+		var inv rn.Mat
+		if inv, err = proj.Inverse(); err != nil {
+			return
+		}
+		invT := inv.Transpose()
+		delta := 0.0
+		for k := range proj.Data {
+			next := (proj.Data[k] + invT.Data[k]) / 2
+			delta = math.Max(delta, math.Abs(next-proj.Data[k]))
+			proj.Data[k] = next
+		}
+		if delta < 1e-14 {
+			break
+		}
+	}
+	return
+}
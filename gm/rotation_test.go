@@ -0,0 +1,137 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func matClose(a, b rn.Mat, tol float64) bool {
+	if a.M != b.M || a.N != b.N {
+		return false
+	}
+	for k := range a.Data {
+		if math.Abs(a.Data[k]-b.Data[k]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func vecClose(a, b rn.Vec, tol float64) bool {
+	if a.N != b.N {
+		return false
+	}
+	for k := range a.X {
+		if math.Abs(a.X[k]-b.X[k]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMakeRotationEuler(t *testing.T) {
+	for _, test := range []struct {
+		angles rn.Vec
+		order  EulerOrder
+		frame  EulerFrame
+		want   rn.Mat
+	}{
+		{
+			rn.Vec{N: 3, X: []float64{math.Pi / 2, 0, 0}}, XYZ, Intrinsic,
+			rn.Mat{M: 3, N: 3, Data: []float64{1, 0, 0, 0, 0, 1, 0, -1, 0}},
+		},
+		{
+			rn.Vec{N: 3, X: []float64{0, 0, math.Pi / 2}}, ZYX, Extrinsic,
+			rn.Mat{M: 3, N: 3, Data: []float64{1, 0, 0, 0, 0, 1, 0, -1, 0}},
+		},
+		{
+			rn.Vec{N: 3, X: []float64{math.Pi / 2, math.Pi / 2, 0}}, ZYX, Intrinsic,
+			rn.Mat{M: 3, N: 3, Data: []float64{0, 0, -1, -1, 0, 0, 0, 1, 0}},
+		},
+	} {
+		got := MakeRotationEuler(test.angles, test.order, test.frame)
+		if !matClose(got, test.want, 1e-15) {
+			t.Errorf("error:\n%v %v:\ngot=\n%v\nwant=\n%v", test.order, test.angles, got, test.want)
+		}
+	}
+}
+
+func TestEulerAnglesRoundTrip(t *testing.T) {
+	for order := XYZ; order <= ZYZ; order++ {
+		for _, frame := range []EulerFrame{Intrinsic, Extrinsic} {
+			for _, angles := range []rn.Vec{
+				{N: 3, X: []float64{0.1, 0.2, 0.3}},
+				{N: 3, X: []float64{-2.5, 1.2, 3}},
+				{N: 3, X: []float64{1, 0, -1}},
+				{N: 3, X: []float64{0.7, math.Pi / 2, 0}},
+				{N: 3, X: []float64{0.7, -math.Pi / 2, 0}},
+				{N: 3, X: []float64{-0.4, math.Pi, 0}},
+			} {
+				rot := MakeRotationEuler(angles, order, frame)
+				got, locked := EulerAngles(rot, order, frame)
+				back := MakeRotationEuler(got, order, frame)
+				if !matClose(rot, back, 1e-12) {
+					t.Errorf("error:\n%v %v %v:\ngot=%v (locked=%v)", order, frame, angles, got, locked)
+				}
+			}
+		}
+	}
+}
+
+func TestEulerAnglesGimbalLock(t *testing.T) {
+	rot := MakeRotationEuler(rn.Vec{N: 3, X: []float64{0.3, math.Pi / 2, 0.2}}, XYZ, Intrinsic)
+	got, locked := EulerAngles(rot, XYZ, Intrinsic)
+	if !locked {
+		t.Errorf("error:\nexpected gimbal lock")
+	}
+	if want := (rn.Vec{N: 3, X: []float64{0.5, math.Pi / 2, 0}}); !vecClose(got, want, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+}
+
+func TestAxisAngle(t *testing.T) {
+	for _, test := range []struct {
+		axis  rn.Vec
+		angle float64
+	}{
+		{rn.Vec{N: 3, X: []float64{0, 0, 1}}, 0.5},
+		{rn.Vec{N: 3, X: []float64{1, 2, 3}}, 2},
+		{rn.Vec{N: 3, X: []float64{-1, 1, 0}}, math.Pi},
+		{rn.Vec{N: 3, X: []float64{0.2, -0.3, 0.9}}, math.Pi - 1e-9},
+	} {
+		rot := MakeRotationAxisAngle(test.axis, test.angle)
+		axis, angle := AxisAngle(rot)
+		want := test.axis.Scale(1 / test.axis.Norm())
+		if math.Abs(angle-test.angle) > 1e-12 || !(vecClose(axis, want, 1e-7) || test.angle == math.Pi && vecClose(axis.Scale(-1), want, 1e-7)) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", axis, angle, want, test.angle)
+		}
+		v := RotationVec(rot)
+		if back := MakeRotationVec(v); !matClose(rot, back, 1e-7) {
+			t.Errorf("error:\ngot=\n%v\nwant=\n%v", back, rot)
+		}
+	}
+}
+
+func TestOrthonormalize(t *testing.T) {
+	rot := MakeRotationEuler(rn.Vec{N: 3, X: []float64{0.3, -0.2, 1.1}}, ZYX, Intrinsic)
+	drift := rot.GetCopy()
+	for k := range drift.Data {
+		drift.Data[k] += 1e-4 * math.Sin(float64(7*k))
+	}
+	got, err := Orthonormalize(drift)
+	if err != nil {
+		t.Fatalf("error:\n%v\n", err)
+	}
+	gotT := got.Transpose()
+	if prod := gotT.Mul(got); !matClose(prod, rn.MakeIdentity(3), 1e-14) {
+		t.Errorf("error:\nRᵀR=\n%v", prod)
+	}
+	if !matClose(got, rot, 1e-3) {
+		t.Errorf("error:\ngot=\n%v\nwant=\n%v", got, rot)
+	}
+	if _, err := Orthonormalize(rn.MakeMat(3, 3, 0)); err == nil {
+		t.Errorf("error:\nexpected ErrRotation")
+	}
+}
@@ -0,0 +1,262 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/rn"
+)
+
+// Segment is the part of the line through V1 and V2 between the two points
+type Segment struct {
+	V1, V2 rn.Vec
+}
+
+func (o Segment) String() (str string) {
+	str += fmt.Sprintf("[%v, %v]", o.V1, o.V2)
+	return
+}
+
+// MakeSegment returns a Segment object with endpoints P1 and P2
+//
+// Parameters:
+//
+//	P1 rn.Vec - The first endpoint
+//	P2 rn.Vec - The second endpoint
+//
+// Returns:
+//
+//	seg Segment - The segment from P1 to P2
+func MakeSegment(P1, P2 rn.Vec) (seg Segment) {
+	seg.V1 = P1
+	seg.V2 = P2
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Segment - segment to compare to q
+//	q Segment - segment to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *Segment) Equal(q Segment) bool {
+	return o.V1.Equal(q.V1) && o.V2.Equal(q.V2)
+}
+
+// Line returns the line that contains the segment
+//
+// The line is built with MakeLineByPoints, so the segment covers the
+// parameters 0 <= λ <= 1 of the returned line.
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//
+// Returns:
+//
+//	line Line - The line through V1 and V2
+func (o *Segment) Line() (line Line) {
+	line = MakeLineByPoints(o.V1, o.V2)
+	return
+}
+
+// Dir returns the vector from V1 to V2
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//
+// Returns:
+//
+//	D1 rn.Vec - The vector V2 - V1
+func (o *Segment) Dir() (D1 rn.Vec) {
+	D1 = o.V2.Sub(o.V1)
+	return
+}
+
+// Length returns the distance between the endpoints
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//
+// Returns:
+//
+//	l float64 - The length of the segment
+func (o *Segment) Length() (l float64) {
+	l = o.V1.Dist(o.V2)
+	return
+}
+
+// Midpoint returns the point halfway between the endpoints
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//
+// Returns:
+//
+//	P1 rn.Vec - The midpoint
+func (o *Segment) Midpoint() (P1 rn.Vec) {
+	P1 = o.At(0.5)
+	return
+}
+
+// Clamp restricts a line parameter to the segment
+//
+// Parameters:
+//
+//	s float64 - A parameter of the line through the segment
+//
+// Returns:
+//
+//	float64 - s clamped to [0, 1]
+func (o *Segment) Clamp(s float64) float64 {
+	return clamp(s, 0, 1)
+}
+
+// At returns the point V1 + s * (V2 - V1)
+//
+// Parameters:
+//
+//	s float64 - The parameter, 0 at V1 and 1 at V2
+//
+// Returns:
+//
+//	P1 rn.Vec - The point at parameter s
+func (o *Segment) At(s float64) (P1 rn.Vec) {
+	D1 := o.Dir()
+	P1 = o.V1.Add(D1.Scale(s))
+	return
+}
+
+// ClosestPoint returns the point on the segment closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	s float64 - The parameter of the closest point in [0, 1]
+//	P1 rn.Vec - The closest point
+func (o *Segment) ClosestPoint(P rn.Vec) (s float64, P1 rn.Vec) {
+	D1 := o.Dir()
+	if dd := D1.Dot(D1); dd > 0 {
+		w := P.Sub(o.V1)
+		s = clamp(D1.Dot(w)/dd, 0, 1)
+	}
+	P1 = o.V1.Add(D1.Scale(s))
+	return
+}
+
+// Dist returns the distance between P and the segment
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance from P to its closest point on the segment
+func (o *Segment) Dist(P rn.Vec) (dist float64) {
+	_, P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
+
+// ClosestSegment returns the closest pair of points between two segments
+//
+// Parameters:
+//
+//	o *Segment - The first segment
+//	q Segment - The second segment
+//
+// Returns:
+//
+//	x rn.Vec - The parameters s and t of the closest points in [0, 1]
+//	P1 rn.Vec - The closest point on o
+//	P2 rn.Vec - The closest point on q
+func (o *Segment) ClosestSegment(q Segment) (x, P1, P2 rn.Vec) {
+	D1, D2 := o.Dir(), q.Dir()
+	s, t := closestParams(o.V1, D1, 0, 1, q.V1, D2, 0, 1)
+	x = rn.Vec{N: 2, X: []float64{s, t}}
+	P1 = o.V1.Add(D1.Scale(s))
+	P2 = q.V1.Add(D2.Scale(t))
+	return
+}
+
+// IntersectLine returns the intersection point of a segment and a line (if it exists)
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//	q Line - The line
+//
+// Returns:
+//
+//	x rn.Vec - The parameter s of the segment and λ of the line
+//	P1 rn.Vec - The intersection point
+//	err error - ErrNoIntersection if they do not meet
+func (o *Segment) IntersectLine(q Line) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectParametric(o.V1, o.Dir(), 0, 1, q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectPlane returns the intersection point of a segment and a plane (if it exists)
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//	plane Plane - The plane
+//
+// Returns:
+//
+//	x rn.Vec - The parameter s of the segment and λ, μ of the plane
+//	P1 rn.Vec - The intersection point
+//	err error - ErrParallel if the segment is parallel to the plane,
+//	ErrNoIntersection if it ends before reaching the plane
+func (o *Segment) IntersectPlane(plane Plane) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectPlane(o.V1, o.Dir(), 0, 1, plane)
+	return
+}
+
+// IntersectSegment returns the intersection point of two segments (if it exists)
+//
+// For collinear overlapping segments one of the common points is returned.
+//
+// Parameters:
+//
+//	o *Segment - The first segment
+//	q Segment - The second segment
+//
+// Returns:
+//
+//	x rn.Vec - The parameters s of o and t of q
+//	P1 rn.Vec - The intersection point
+//	err error - ErrNoIntersection if they do not meet
+func (o *Segment) IntersectSegment(q Segment) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectParametric(o.V1, o.Dir(), 0, 1, q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// IntersectRay returns the intersection point of a segment and a ray (if it exists)
+//
+// Parameters:
+//
+//	o *Segment - The segment
+//	q Ray - The ray
+//
+// Returns:
+//
+//	x rn.Vec - The parameters s of o and t of q
+//	P1 rn.Vec - The intersection point
+//	err error - ErrNoIntersection if they do not meet
+func (o *Segment) IntersectRay(q Ray) (x, P1 rn.Vec, err error) {
+	x, P1, err = intersectParametric(o.V1, o.Dir(), 0, 1, q.V1, q.V2, 0, math.Inf(1))
+	return
+}
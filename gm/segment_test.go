@@ -0,0 +1,97 @@
+package gm
+
+import (
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func v3(x, y, z float64) rn.Vec {
+	return rn.Vec{N: 3, X: []float64{x, y, z}}
+}
+
+func TestSegmentBasics(t *testing.T) {
+	seg := MakeSegment(v3(0, 0, 0), v3(2, 0, 0))
+	if got := seg.Length(); got != 2 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 2)
+	}
+	if got, want := seg.Midpoint(), v3(1, 0, 0); !got.Equal(want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	if got := seg.Clamp(1.5); got != 1 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 1)
+	}
+	s, P1 := seg.ClosestPoint(v3(3, 1, 0))
+	if want := v3(2, 0, 0); s != 1 || !P1.Equal(want) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", s, P1, 1, want)
+	}
+	line := seg.Line()
+	if want := MakeLineByPoints(seg.V1, seg.V2); !line.Equal(want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", line, want)
+	}
+}
+
+func TestSegmentClosestSegment(t *testing.T) {
+	for _, test := range []struct {
+		a, b   Segment
+		wantX  rn.Vec
+		P1, P2 rn.Vec
+	}{
+		{
+			MakeSegment(v3(0, 0, 0), v3(2, 0, 0)), MakeSegment(v3(1, -1, 1), v3(1, 1, 1)),
+			rn.Vec{N: 2, X: []float64{0.5, 0.5}}, v3(1, 0, 0), v3(1, 0, 1),
+		},
+		{
+			MakeSegment(v3(0, 0, 0), v3(1, 0, 0)), MakeSegment(v3(3, 1, 0), v3(3, 2, 0)),
+			rn.Vec{N: 2, X: []float64{1, 0}}, v3(1, 0, 0), v3(3, 1, 0),
+		},
+		{
+			MakeSegment(v3(0, 0, 0), v3(2, 0, 0)), MakeSegment(v3(1, 1, 0), v3(3, 1, 0)),
+			rn.Vec{N: 2, X: []float64{0.5, 0}}, v3(1, 0, 0), v3(1, 1, 0),
+		},
+	} {
+		x, P1, P2 := test.a.ClosestSegment(test.b)
+		if !vecClose(x, test.wantX, 1e-12) || !vecClose(P1, test.P1, 1e-12) || !vecClose(P2, test.P2, 1e-12) {
+			t.Errorf("error:\ngot=%v %v %v\nwant=%v %v %v", x, P1, P2, test.wantX, test.P1, test.P2)
+		}
+	}
+}
+
+func TestSegmentRayIntersect(t *testing.T) {
+	seg := MakeSegment(v3(0, 0, 0), v3(2, 2, 0))
+	plane := MakePlane(v3(0, 0, 1), v3(1, 0, 0), v3(0, 1, 0))
+
+	if _, P1, err := seg.IntersectSegment(MakeSegment(v3(0, 2, 0), v3(2, 0, 0))); err != nil || !vecClose(P1, v3(1, 1, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v", P1, err)
+	}
+	if _, _, err := seg.IntersectSegment(MakeSegment(v3(0, 2, 0), v3(0.5, 1.5, 0))); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+	if x, P1, err := seg.IntersectLine(MakeLine(v3(0, 1, 0), v3(1, 0, 0))); err != nil || !vecClose(P1, v3(1, 1, 0), 1e-12) || !vecClose(x, rn.Vec{N: 2, X: []float64{0.5, 1}}, 1e-12) {
+		t.Errorf("error:\ngot=%v, %v, %v", x, P1, err)
+	}
+	if _, _, err := seg.IntersectPlane(plane); err == nil {
+		t.Errorf("error:\nexpected ErrParallel")
+	}
+
+	ray := MakeRayByPoints(v3(1, 1, -1), v3(1, 1, 0))
+	if x, P1, err := ray.IntersectPlane(plane); err != nil || !vecClose(P1, v3(1, 1, 1), 1e-12) || !vecClose(x, v3(2, 1, 1), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v, %v", x, P1, err)
+	}
+	back := MakeRay(v3(1, 1, -1), v3(0, 0, -1))
+	if _, _, err := back.IntersectPlane(plane); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+	if _, P1, err := ray.IntersectSegment(seg); err != nil || !vecClose(P1, v3(1, 1, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v", P1, err)
+	}
+	if _, P1, err := seg.IntersectRay(ray); err != nil || !vecClose(P1, v3(1, 1, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v", P1, err)
+	}
+	if _, _, err := ray.IntersectRay(MakeRay(v3(0, 0, -2), v3(1, 1, 0))); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+	if _, P1, err := ray.IntersectRay(MakeRay(v3(0, 0, 0), v3(1, 1, 0))); err != nil || !vecClose(P1, v3(1, 1, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v", P1, err)
+	}
+}
@@ -0,0 +1,258 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Sphere is the set of points at distance R from the center C
+type Sphere struct {
+	C rn.Vec
+	R float64
+}
+
+func (o Sphere) String() (str string) {
+	str += fmt.Sprintf("|x - %v| = %v", o.C, o.R)
+	return
+}
+
+// MakeSphere returns a Sphere object with center C and radius R
+//
+// Parameters:
+//
+//	C rn.Vec - The center
+//	R float64 - The radius
+//
+// Returns:
+//
+//	sphere Sphere - The sphere with center C and radius R
+func MakeSphere(C rn.Vec, R float64) (sphere Sphere) {
+	sphere.C = C
+	sphere.R = math.Abs(R)
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Sphere - sphere to compare to q
+//	q Sphere - sphere to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *Sphere) Equal(q Sphere) bool {
+	return o.C.Equal(q.C) && o.R == q.R
+}
+
+// Contains returns true if P lies inside or on the sphere
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if |P - C| <= R
+func (o *Sphere) Contains(P rn.Vec) bool {
+	return o.Dist(P) <= o.tolerance(P)
+}
+
+// Dist returns the signed distance between P and the surface of the sphere
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance to the surface, negative if P lies inside
+func (o *Sphere) Dist(P rn.Vec) (dist float64) {
+	dist = o.C.Dist(P) - o.R
+	return
+}
+
+// ClosestPoint returns the point on the surface of the sphere closest to P
+//
+// If P is the center every surface point is equally close and the one in
+// the direction of the first coordinate axis is returned.
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	P1 rn.Vec - The closest surface point
+func (o *Sphere) ClosestPoint(P rn.Vec) (P1 rn.Vec) {
+	w := P.Sub(o.C)
+	nrm := w.Norm()
+	if nrm == 0 {
+		w = rn.MakeVec(o.C.N, 0)
+		w.X[0], nrm = 1, 1
+	}
+	P1 = o.C.Add(w.Scale(o.R / nrm))
+	return
+}
+
+func (o *Sphere) tolerance(vs ...rn.Vec) float64 {
+	return tolerance(append(vs, o.C)...) * math.Max(1, o.R)
+}
+
+// intersectParams returns the parameters at which p + t*d meets the sphere, in ascending order
+func (o *Sphere) intersectParams(p, d rn.Vec) (ts []float64) {
+	a := d.Dot(d)
+	if a == 0 {
+		return
+	}
+	w := p.Sub(o.C)
+	t0 := -d.Dot(w) / a
+	foot := p.Add(d.Scale(t0))
+	h := foot.Dist(o.C)
+	switch tol := o.tolerance(p); {
+	case h > o.R+tol:
+	case h >= o.R-tol:
+		ts = []float64{t0}
+	default:
+		dt := math.Sqrt((o.R-h)*(o.R+h)) / math.Sqrt(a)
+		ts = []float64{t0 - dt, t0 + dt}
+	}
+	return
+}
+
+// intersectBounded keeps the intersections of p + t*d with the sphere for t in [tMin, tMax]
+func (o *Sphere) intersectBounded(p, d rn.Vec, tMin, tMax float64) (ts []float64, Ps []rn.Vec, err error) {
+	for _, t := range o.intersectParams(p, d) {
+		if t < tMin-eps || tMax+eps < t {
+			continue
+		}
+		t = clamp(t, tMin, tMax)
+		ts = append(ts, t)
+		Ps = append(Ps, p.Add(d.Scale(t)))
+	}
+	if len(ts) == 0 {
+		err = errors.ErrNoIntersection
+	}
+	return
+}
+
+// IntersectLine returns the intersection points of a sphere and a line
+//
+// Parameters:
+//
+//	o *Sphere - The sphere
+//	q Line - The line
+//
+// Returns:
+//
+//	ts []float64 - The line parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The one (tangent) or two intersection points
+//	err error - ErrNoIntersection if the line misses the sphere
+func (o *Sphere) IntersectLine(q Line) (ts []float64, Ps []rn.Vec, err error) {
+	ts, Ps, err = o.intersectBounded(q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectRay returns the intersection points of a sphere and a ray
+//
+// Parameters:
+//
+//	o *Sphere - The sphere
+//	q Ray - The ray
+//
+// Returns:
+//
+//	ts []float64 - The ray parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrNoIntersection if the ray misses the sphere
+func (o *Sphere) IntersectRay(q Ray) (ts []float64, Ps []rn.Vec, err error) {
+	ts, Ps, err = o.intersectBounded(q.V1, q.V2, 0, math.Inf(1))
+	return
+}
+
+// IntersectSegment returns the intersection points of a sphere and a segment
+//
+// Parameters:
+//
+//	o *Sphere - The sphere
+//	q Segment - The segment
+//
+// Returns:
+//
+//	ts []float64 - The segment parameters of the intersection points in ascending order
+//	Ps []rn.Vec - The zero, one or two intersection points
+//	err error - ErrNoIntersection if the segment does not cross the surface
+func (o *Sphere) IntersectSegment(q Segment) (ts []float64, Ps []rn.Vec, err error) {
+	ts, Ps, err = o.intersectBounded(q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// IntersectPlane returns the circle in which a sphere and a plane meet
+//
+// A tangent plane yields a circle of radius 0.
+//
+// Parameters:
+//
+//	o *Sphere - The sphere
+//	plane Plane - The plane
+//
+// Returns:
+//
+//	circle Circle - The intersection circle
+//	err error - ErrNoIntersection if the plane misses the sphere
+func (o *Sphere) IntersectPlane(plane Plane) (circle Circle, err error) {
+	n := plane.Normal()
+	w := o.C.Sub(plane.V1)
+	d := n.Dot(w)
+	tol := o.tolerance(plane.V1)
+	if math.Abs(d) > o.R+tol {
+		err = errors.ErrNoIntersection
+		return
+	}
+	circle.C = o.C.Sub(n.Scale(d))
+	circle.Normal = n
+	if math.Abs(d) < o.R-tol {
+		circle.R = math.Sqrt((o.R - d) * (o.R + d))
+	}
+	return
+}
+
+// IntersectSphere returns the circle in which two spheres meet
+//
+// Touching spheres yield a circle of radius 0.
+//
+// Parameters:
+//
+//	o *Sphere - The first sphere
+//	q Sphere - The second sphere
+//
+// Returns:
+//
+//	circle Circle - The intersection circle, its normal points from o.C to q.C
+//	err error - ErrParallel if the spheres coincide,
+//	ErrNoIntersection if they are disjoint or one lies inside the other
+func (o *Sphere) IntersectSphere(q Sphere) (circle Circle, err error) {
+	w := q.C.Sub(o.C)
+	d := w.Norm()
+	tol := o.tolerance(q.C) * math.Max(1, q.R)
+	switch {
+	case d <= tol && math.Abs(o.R-q.R) <= tol:
+		err = errors.ErrParallel
+		return
+	case d > o.R+q.R+tol || d < math.Abs(o.R-q.R)-tol:
+		err = errors.ErrNoIntersection
+		return
+	}
+	n := w.Scale(1 / d)
+	a := (d*d + o.R*o.R - q.R*q.R) / (2 * d)
+	circle.C = o.C.Add(n.Scale(a))
+	circle.Normal = n
+	if math.Abs(o.R-q.R)+tol < d && d < o.R+q.R-tol {
+		circle.R = math.Sqrt(math.Max(o.R*o.R-a*a, 0))
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func TestSphereIntersectLine(t *testing.T) {
+	sphere := MakeSphere(v3(0, 0, 0), 1)
+	for _, test := range []struct {
+		line Line
+		want []rn.Vec
+	}{
+		{MakeLine(v3(-2, 0, 0), v3(1, 0, 0)), []rn.Vec{v3(-1, 0, 0), v3(1, 0, 0)}},
+		{MakeLine(v3(-2, 1, 0), v3(1, 0, 0)), []rn.Vec{v3(0, 1, 0)}},
+		{MakeLine(v3(-2, 2, 0), v3(1, 0, 0)), nil},
+	} {
+		_, Ps, err := sphere.IntersectLine(test.line)
+		if len(Ps) != len(test.want) || (len(Ps) == 0) != (err != nil) {
+			t.Errorf("error:\ngot=%v, %v\nwant=%v", Ps, err, test.want)
+			continue
+		}
+		for i := range Ps {
+			if !vecClose(Ps[i], test.want[i], 1e-12) {
+				t.Errorf("error:\ngot=%v\nwant=%v", Ps, test.want)
+			}
+		}
+	}
+
+	ray := MakeRay(v3(0, 0, 0), v3(0, 0, 2))
+	if ts, Ps, err := sphere.IntersectRay(ray); err != nil || len(Ps) != 1 || ts[0] != 0.5 {
+		t.Errorf("error:\ngot=%v, %v, %v", ts, Ps, err)
+	}
+	seg := MakeSegment(v3(0, 0, 0), v3(0, 0, 0.5))
+	if _, _, err := sphere.IntersectSegment(seg); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+}
+
+func TestSphereIntersectPlaneSphere(t *testing.T) {
+	sphere := MakeSphere(v3(0, 0, 0), 2)
+	circle, err := sphere.IntersectPlane(MakePlane(v3(0, 0, 1), v3(1, 0, 0), v3(0, 1, 0)))
+	if err != nil || !vecClose(circle.C, v3(0, 0, 1), 1e-12) || math.Abs(circle.R-math.Sqrt(3)) > 1e-12 {
+		t.Errorf("error:\ngot=%v, %v", circle, err)
+	}
+	if _, err := sphere.IntersectPlane(MakePlane(v3(0, 0, 3), v3(1, 0, 0), v3(0, 1, 0))); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+
+	circle, err = sphere.IntersectSphere(MakeSphere(v3(2, 0, 0), 2))
+	if err != nil || !vecClose(circle.C, v3(1, 0, 0), 1e-12) || math.Abs(circle.R-math.Sqrt(3)) > 1e-12 || !vecClose(circle.Normal, v3(1, 0, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v", circle, err)
+	}
+	if circle, err = sphere.IntersectSphere(MakeSphere(v3(0, 3, 0), 1)); err != nil || circle.R != 0 || !vecClose(circle.C, v3(0, 2, 0), 1e-12) {
+		t.Errorf("error:\ngot=%v, %v", circle, err)
+	}
+	if _, err := sphere.IntersectSphere(MakeSphere(v3(0, 0, 0), 1)); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+	if _, err := sphere.IntersectSphere(sphere); err == nil {
+		t.Errorf("error:\nexpected ErrParallel")
+	}
+}
+
+func TestCircle(t *testing.T) {
+	circle := MakeCircle(v3(1, 1, 1), v3(0, 0, 2), 1)
+	if !circle.Contains(v3(2, 1, 1)) || circle.Contains(v3(1, 1, 1)) || circle.Contains(v3(2, 1, 1.5)) {
+		t.Errorf("error:\nContains")
+	}
+	if got, want := circle.ClosestPoint(v3(3, 1, 5)), v3(2, 1, 1); !vecClose(got, want, 1e-12) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	if got := circle.Dist(v3(1, 1, 2)); math.Abs(got-math.Sqrt(2)) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, math.Sqrt(2))
+	}
+	for _, theta := range []float64{0, 1, 2, 4} {
+		if P := circle.At(theta); !circle.Contains(P) {
+			t.Errorf("error:\n%v not on circle", P)
+		}
+	}
+}
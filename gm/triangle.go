@@ -0,0 +1,343 @@
+package gm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+	"github.com/add1609/lin/robust"
+)
+
+// Triangle is the convex hull of the three vertices V1, V2 and V3
+type Triangle struct {
+	V1, V2, V3 rn.Vec
+}
+
+func (o Triangle) String() (str string) {
+	str += fmt.Sprintf("△(%v, %v, %v)", o.V1, o.V2, o.V3)
+	return
+}
+
+// MakeTriangle returns a Triangle object with the given vertices
+//
+// Parameters:
+//
+//	P1 rn.Vec - The first vertex
+//	P2 rn.Vec - The second vertex
+//	P3 rn.Vec - The third vertex
+//
+// Returns:
+//
+//	tri Triangle - The triangle with vertices P1, P2 and P3
+func MakeTriangle(P1, P2, P3 rn.Vec) (tri Triangle) {
+	tri.V1 = P1
+	tri.V2 = P2
+	tri.V3 = P3
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	o *Triangle - triangle to compare to q
+//	q Triangle - triangle to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o *Triangle) Equal(q Triangle) bool {
+	return o.V1.Equal(q.V1) && o.V2.Equal(q.V2) && o.V3.Equal(q.V3)
+}
+
+// Plane returns the plane that contains the triangle
+//
+// The plane is built with MakePlaneByPoints, so its parameters λ and μ
+// are the barycentric weights of V2 and V3.
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//
+// Returns:
+//
+//	plane Plane - The plane through V1, V2 and V3
+func (o *Triangle) Plane() (plane Plane) {
+	plane = MakePlaneByPoints(o.V1, o.V2, o.V3)
+	return
+}
+
+// Edges returns the edges V1V2, V2V3 and V3V1
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//
+// Returns:
+//
+//	edges [3]Segment - The three edges in vertex order
+func (o *Triangle) Edges() (edges [3]Segment) {
+	edges[0] = MakeSegment(o.V1, o.V2)
+	edges[1] = MakeSegment(o.V2, o.V3)
+	edges[2] = MakeSegment(o.V3, o.V1)
+	return
+}
+
+// Normal returns the unit normal (V2 - V1) × (V3 - V1) / |(V2 - V1) × (V3 - V1)|
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//
+// Returns:
+//
+//	n rn.Vec - The unit normal, zero for a degenerate triangle
+func (o *Triangle) Normal() (n rn.Vec) {
+	e1, e2 := o.V2.Sub(o.V1), o.V3.Sub(o.V1)
+	n = e1.Cross(e2)
+	if nrm := n.Norm(); nrm > 0 {
+		n = n.Scale(1 / nrm)
+	}
+	return
+}
+
+// Area returns the area of the triangle
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//
+// Returns:
+//
+//	area float64 - The area
+func (o *Triangle) Area() (area float64) {
+	e1, e2 := o.V2.Sub(o.V1), o.V3.Sub(o.V1)
+	d11, d12, d22 := e1.Dot(e1), e1.Dot(e2), e2.Dot(e2)
+	area = math.Sqrt(math.Max(d11*d22-d12*d12, 0)) / 2
+	return
+}
+
+// Centroid returns the arithmetic mean of the vertices
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//
+// Returns:
+//
+//	P1 rn.Vec - The centroid
+func (o *Triangle) Centroid() (P1 rn.Vec) {
+	P1 = o.V1.Add(o.V2)
+	P1 = P1.Add(o.V3)
+	P1 = P1.Scale(1.0 / 3)
+	return
+}
+
+// Barycentric returns the barycentric coordinates of P with respect to the triangle
+//
+// Points off the triangle's plane are projected onto it first.
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bc rn.Vec - The weights (u, v, w) with u + v + w = 1 and P = u*V1 + v*V2 + w*V3
+//	err error - ErrDegenerate if the vertices are collinear
+func (o *Triangle) Barycentric(P rn.Vec) (bc rn.Vec, err error) {
+	e1, e2, w := o.V2.Sub(o.V1), o.V3.Sub(o.V1), P.Sub(o.V1)
+	d11, d12, d22 := e1.Dot(e1), e1.Dot(e2), e2.Dot(e2)
+	dw1, dw2 := w.Dot(e1), w.Dot(e2)
+	denom := d11*d22 - d12*d12
+	if denom <= eps*d11*d22 {
+		err = errors.ErrDegenerate
+		return
+	}
+	b2 := (d22*dw1 - d12*dw2) / denom
+	b3 := (d11*dw2 - d12*dw1) / denom
+	bc = rn.Vec{N: 3, X: []float64{1 - b2 - b3, b2, b3}}
+	return
+}
+
+// Contains returns true if P lies in the triangle
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	bool - true if P lies in the triangle's plane and inside or on its boundary
+func (o *Triangle) Contains(P rn.Vec) bool {
+	P1 := o.ClosestPoint(P)
+	return P1.Dist(P) <= tolerance(o.V1, o.V2, o.V3, P)
+}
+
+// ClosestPoint returns the point of the triangle closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	P1 rn.Vec - The closest point inside or on the boundary of the triangle
+func (o *Triangle) ClosestPoint(P rn.Vec) (P1 rn.Vec) {
+	// Voronoi region classification, see Ericson, Real-Time Collision Detection, 5.1.5
+	ab, ac, ap := o.V2.Sub(o.V1), o.V3.Sub(o.V1), P.Sub(o.V1)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return o.V1
+	}
+	bp := P.Sub(o.V2)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return o.V2
+	}
+	if vc := d1*d4 - d3*d2; vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return o.V1.Add(ab.Scale(d1 / (d1 - d3)))
+	}
+	cp := P.Sub(o.V3)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return o.V3
+	}
+	if vb := d5*d2 - d1*d6; vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return o.V1.Add(ac.Scale(d2 / (d2 - d6)))
+	}
+	if va := d3*d6 - d5*d4; va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		bc := o.V3.Sub(o.V2)
+		return o.V2.Add(bc.Scale((d4 - d3) / ((d4 - d3) + (d5 - d6))))
+	}
+	va, vb, vc := d3*d6-d5*d4, d5*d2-d1*d6, d1*d4-d3*d2
+	denom := 1 / (va + vb + vc)
+	P1 = o.V1.Add(ab.Scale(vb * denom))
+	P1 = P1.Add(ac.Scale(vc * denom))
+	return
+}
+
+// Dist returns the distance between P and the triangle
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	dist float64 - The distance from P to its closest point on the triangle
+func (o *Triangle) Dist(P rn.Vec) (dist float64) {
+	P1 := o.ClosestPoint(P)
+	dist = P1.Dist(P)
+	return
+}
+
+// intersect is the Möller–Trumbore intersection of p + t*d, t in [tMin, tMax], with the triangle
+func (o *Triangle) intersect(p, d rn.Vec, tMin, tMax float64) (x, P1 rn.Vec, err error) {
+	e1, e2 := o.V2.Sub(o.V1), o.V3.Sub(o.V1)
+	// the edges and the direction scaled by powers of two to order one, so that tiny
+	// or huge triangles neither underflow nor overflow
+	k1, k2, kd := binaryExponent(e1), binaryExponent(e2), binaryExponent(d)
+	e1, e2, dk := ldexpVec(e1, -k1), ldexpVec(e2, -k2), ldexpVec(d, -kd)
+	pv := dk.Cross(e2)
+	// e1 · (d × e2) with the exact sign, so that only exactly parallel directions are rejected
+	det := robust.Orient3D(vec3(e1), vec3(dk), vec3(e2), [3]float64{})
+	if det == 0 {
+		err = errors.ErrParallel
+		return
+	}
+	inv := 1 / det
+	tv := p.Sub(o.V1)
+	u := math.Ldexp(tv.Dot(pv)*inv, -k1)
+	if u < -eps || 1+eps < u {
+		err = errors.ErrNoIntersection
+		return
+	}
+	qv := tv.Cross(e1)
+	v := math.Ldexp(dk.Dot(qv)*inv, -k2)
+	if v < -eps || 1+eps < u+v {
+		err = errors.ErrNoIntersection
+		return
+	}
+	t := math.Ldexp(e2.Dot(qv)*inv, -kd)
+	if t < tMin-eps || tMax+eps < t {
+		err = errors.ErrNoIntersection
+		return
+	}
+	t = clamp(t, tMin, tMax)
+	x = rn.Vec{N: 3, X: []float64{t, u, v}}
+	P1 = p.Add(d.Scale(t))
+	return
+}
+
+// IntersectRay returns the intersection point of a triangle and a ray (if it exists)
+//
+// Both sides of the triangle are hit; use the sign of Normal() · ray.V2 to cull back faces.
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//	q Ray - The ray
+//
+// Returns:
+//
+//	x rn.Vec - The ray parameter t and the barycentric weights u, v of V2 and V3
+//	P1 rn.Vec - The intersection point
+//	err error - ErrParallel if the ray is parallel to the triangle's plane,
+//	ErrNoIntersection if it misses the triangle
+func (o *Triangle) IntersectRay(q Ray) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.intersect(q.V1, q.V2, 0, math.Inf(1))
+	return
+}
+
+// IntersectLine returns the intersection point of a triangle and a line (if it exists)
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//	q Line - The line
+//
+// Returns:
+//
+//	x rn.Vec - The line parameter λ and the barycentric weights u, v of V2 and V3
+//	P1 rn.Vec - The intersection point
+//	err error - ErrParallel if the line is parallel to the triangle's plane,
+//	ErrNoIntersection if it misses the triangle
+func (o *Triangle) IntersectLine(q Line) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.intersect(q.V1, q.V2, math.Inf(-1), math.Inf(1))
+	return
+}
+
+// IntersectSegment returns the intersection point of a triangle and a segment (if it exists)
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//	q Segment - The segment
+//
+// Returns:
+//
+//	x rn.Vec - The segment parameter s and the barycentric weights u, v of V2 and V3
+//	P1 rn.Vec - The intersection point
+//	err error - ErrParallel if the segment is parallel to the triangle's plane,
+//	ErrNoIntersection if it misses the triangle
+func (o *Triangle) IntersectSegment(q Segment) (x, P1 rn.Vec, err error) {
+	x, P1, err = o.intersect(q.V1, q.Dir(), 0, 1)
+	return
+}
+
+// AABB returns the axis-aligned bounding box of the triangle
+//
+// Parameters:
+//
+//	o *Triangle - The triangle
+//
+// Returns:
+//
+//	box AABB - The bounding box of the vertices
+func (o *Triangle) AABB() (box AABB) {
+	box = MakeAABB(o.V1, o.V2)
+	box.Extend(o.V3)
+	return
+}
@@ -0,0 +1,93 @@
+package gm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func TestTriangleBasics(t *testing.T) {
+	tri := MakeTriangle(v3(0, 0, 0), v3(2, 0, 0), v3(0, 2, 0))
+	if got := tri.Area(); got != 2 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 2)
+	}
+	if got, want := tri.Normal(), v3(0, 0, 1); !got.Equal(want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	if got, want := tri.Centroid(), v3(2.0/3, 2.0/3, 0); !vecClose(got, want, 1e-15) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	bc, err := tri.Barycentric(v3(0.5, 1, 7))
+	if want := v3(0.25, 0.25, 0.5); err != nil || !vecClose(bc, want, 1e-15) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", bc, err, want)
+	}
+	flat := MakeTriangle(v3(0, 0, 0), v3(1, 1, 1), v3(2, 2, 2))
+	if _, err := flat.Barycentric(v3(0, 0, 0)); err == nil {
+		t.Errorf("error:\nexpected ErrDegenerate")
+	}
+	if !tri.Contains(v3(1, 1, 0)) || tri.Contains(v3(1.1, 1, 0)) || tri.Contains(v3(0.5, 0.5, 0.1)) {
+		t.Errorf("error:\nContains")
+	}
+}
+
+func TestTriangleClosestPoint(t *testing.T) {
+	tri := MakeTriangle(v3(0, 0, 0), v3(2, 0, 0), v3(0, 2, 0))
+	for _, test := range []struct {
+		P, want rn.Vec
+	}{
+		{v3(-1, -1, 1), v3(0, 0, 0)},
+		{v3(3, -1, 0), v3(2, 0, 0)},
+		{v3(-1, 3, 0), v3(0, 2, 0)},
+		{v3(1, -1, 5), v3(1, 0, 0)},
+		{v3(-1, 1, 0), v3(0, 1, 0)},
+		{v3(2, 2, 0), v3(1, 1, 0)},
+		{v3(0.5, 0.5, -3), v3(0.5, 0.5, 0)},
+	} {
+		got := tri.ClosestPoint(test.P)
+		if !vecClose(got, test.want, 1e-15) {
+			t.Errorf("error:\n%v:\ngot=%v\nwant=%v", test.P, got, test.want)
+		}
+	}
+}
+
+func TestTriangleIntersectRay(t *testing.T) {
+	tri := MakeTriangle(v3(0, 0, 0), v3(2, 0, 0), v3(0, 2, 0))
+	for _, test := range []struct {
+		ray  Ray
+		want rn.Vec
+		err  bool
+	}{
+		{MakeRay(v3(0.5, 0.5, 1), v3(0, 0, -1)), v3(1, 0.25, 0.25), false},
+		{MakeRay(v3(0.5, 0.5, -1), v3(0, 0, 2)), v3(0.5, 0.25, 0.25), false},
+		{MakeRay(v3(0.5, 0.5, 1), v3(0, 0, 1)), rn.Vec{}, true},
+		{MakeRay(v3(2, 2, 1), v3(0, 0, -1)), rn.Vec{}, true},
+		{MakeRay(v3(0, 0, 1), v3(1, 0, 0)), rn.Vec{}, true},
+		{MakeRay(v3(2, 0, 1), v3(0, 0, -1)), v3(1, 1, 0), false},
+	} {
+		x, _, err := tri.IntersectRay(test.ray)
+		if test.err {
+			if err == nil {
+				t.Errorf("error:\n%v: expected no intersection, got %v", test.ray, x)
+			}
+			continue
+		}
+		if err != nil || !vecClose(x, test.want, 1e-15) {
+			t.Errorf("error:\n%v:\ngot=%v, %v\nwant=%v", test.ray, x, err, test.want)
+		}
+	}
+	if _, P1, err := tri.IntersectSegment(MakeSegment(v3(1, 0.5, -1), v3(1, 0.5, 1))); err != nil || !vecClose(P1, v3(1, 0.5, 0), 1e-15) {
+		t.Errorf("error:\ngot=%v, %v", P1, err)
+	}
+	if _, _, err := tri.IntersectSegment(MakeSegment(v3(1, 0.5, 1), v3(1, 0.5, 2))); err == nil {
+		t.Errorf("error:\nexpected ErrNoIntersection")
+	}
+	if _, P1, err := tri.IntersectLine(MakeLine(v3(1, 0.5, 1), v3(0, 0, 1))); err != nil || math.Abs(P1.X[2]) > 1e-15 {
+		t.Errorf("error:\ngot=%v, %v", P1, err)
+	}
+	// a triangle so small that the products of its edges underflow
+	tiny := MakeTriangle(v3(0, 0, 0), v3(2e-200, 0, 0), v3(0, 2e-200, 0))
+	if x, _, err := tiny.IntersectRay(MakeRay(v3(0.5e-200, 0.5e-200, 1), v3(0, 0, -1))); err != nil || !vecClose(x, v3(1, 0.25, 0.25), 1e-15) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", x, err, v3(1, 0.25, 0.25))
+	}
+}
@@ -0,0 +1,213 @@
+package gm
+
+import (
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+	"github.com/add1609/lin/robust"
+)
+
+// eps is the relative tolerance used for geometric comparisons
+const eps = 1e-9
+
+// tolerance returns an absolute tolerance scaled by the magnitude of the given vectors
+func tolerance(vs ...rn.Vec) (tol float64) {
+	scale := 1.0
+	for _, v := range vs {
+		for _, x := range v.X {
+			scale = math.Max(scale, math.Abs(x))
+		}
+	}
+	tol = eps * scale
+	return
+}
+
+func clamp(x, lo, hi float64) float64 {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
+
+// closestParams returns the parameters s and t that minimize |(p1 + s*d1) - (p2 + t*d2)|
+// subject to s in [sMin, sMax] and t in [tMin, tMax] (bounds may be infinite)
+func closestParams(p1, d1 rn.Vec, sMin, sMax float64, p2, d2 rn.Vec, tMin, tMax float64) (s, t float64) {
+	r := p1.Sub(p2)
+	a, e := d1.Dot(d1), d2.Dot(d2)
+	b, c, f := d1.Dot(d2), d1.Dot(r), d2.Dot(r)
+	switch {
+	case a == 0 && e == 0:
+		s, t = clamp(0, sMin, sMax), clamp(0, tMin, tMax)
+		return
+	case a == 0:
+		s = clamp(0, sMin, sMax)
+		t = clamp(f/e, tMin, tMax)
+		return
+	case e == 0:
+		t = clamp(0, tMin, tMax)
+		s = clamp(-c/a, sMin, sMax)
+		return
+	}
+	if denom := a*e - b*b; denom > eps*a*e {
+		s = clamp((b*f-c*e)/denom, sMin, sMax)
+	} else {
+		// parallel: any s works, start from the one closest to 0
+		s = clamp(0, sMin, sMax)
+	}
+	t = (b*s + f) / e
+	if t < tMin || tMax < t {
+		t = clamp(t, tMin, tMax)
+		s = clamp((b*t-c)/a, sMin, sMax)
+	}
+	return
+}
+
+// binaryExponent returns k such that the largest component of v * 2⁻ᵏ lies in [1/2, 1), 0 for a
+// zero or non-finite v
+func binaryExponent(v rn.Vec) (k int) {
+	var largest float64
+	for _, x := range v.X {
+		largest = math.Max(largest, math.Abs(x))
+	}
+	if largest == 0 || math.IsInf(largest, 0) || math.IsNaN(largest) {
+		return
+	}
+	_, k = math.Frexp(largest)
+	return
+}
+
+// ldexpVec returns v * 2ᵏ, which is exact unless a component underflows or overflows
+func ldexpVec(v rn.Vec, k int) (u rn.Vec) {
+	u = rn.MakeVec(v.N, 0)
+	for i, x := range v.X {
+		u.X[i] = math.Ldexp(x, k)
+	}
+	return
+}
+
+// intersectParametric intersects p1 + s*d1 and p2 + t*d2 with the given parameter bounds
+func intersectParametric(p1, d1 rn.Vec, sMin, sMax float64, p2, d2 rn.Vec, tMin, tMax float64) (x, P1 rn.Vec, err error) {
+	// solve for directions scaled by powers of two to order one, so that tiny or
+	// huge directions neither underflow nor overflow in the normal equations
+	k1, k2 := binaryExponent(d1), binaryExponent(d2)
+	s, t := closestParams(p1, ldexpVec(d1, -k1), math.Ldexp(sMin, k1), math.Ldexp(sMax, k1),
+		p2, ldexpVec(d2, -k2), math.Ldexp(tMin, k2), math.Ldexp(tMax, k2))
+	s, t = math.Ldexp(s, -k1), math.Ldexp(t, -k2)
+	P1 = p1.Add(d1.Scale(s))
+	P2 := p2.Add(d2.Scale(t))
+	if P1.Dist(P2) > tolerance(p1, d1, p2, d2) {
+		err = errors.ErrNoIntersection
+		return
+	}
+	x = rn.Vec{N: 2, X: []float64{s, t}}
+	return
+}
+
+// intersectPlane intersects p + s*d, s in [sMin, sMax], with a plane
+func intersectPlane(p, d rn.Vec, sMin, sMax float64, plane Plane) (x, P1 rn.Vec, err error) {
+	// the directions scaled by powers of two to order one, which keeps the sign of the
+	// determinants and avoids underflow and overflow in the products
+	k := binaryExponent(d)
+	dk := ldexpVec(d, -k)
+	u, v := ldexpVec(plane.V2, -binaryExponent(plane.V2)), ldexpVec(plane.V3, -binaryExponent(plane.V3))
+	// the largest 3 x 3 minor of [d V2 V3] with the exact sign, d · (V2 × V3) in 3D;
+	// d is parallel to the plane if and only if every minor is 0
+	var denom float64
+	var rows [3]int
+	for i := 0; i < d.N; i++ {
+		for j := i + 1; j < d.N; j++ {
+			for l := j + 1; l < d.N; l++ {
+				r := [3]int{i, j, l}
+				if m := robust.Orient3D(vec3(pick3(dk, r)), vec3(pick3(u, r)), vec3(pick3(v, r)), [3]float64{}); math.Abs(m) > math.Abs(denom) {
+					denom, rows = m, r
+				}
+			}
+		}
+	}
+	if denom == 0 {
+		err = errors.ErrParallel
+		return
+	}
+	// Cramer's rule on the rows of the minor
+	u3, v3 := pick3(u, rows), pick3(v, rows)
+	n := u3.Cross(v3)
+	w := plane.V1.Sub(p)
+	w = pick3(w, rows)
+	s := math.Ldexp(n.Dot(w)/denom, -k)
+	if math.IsInf(s, 0) || s < sMin-eps || sMax+eps < s {
+		err = errors.ErrNoIntersection
+		return
+	}
+	s = clamp(s, sMin, sMax)
+	P1 = p.Add(d.Scale(s))
+	lambda, mu := planeParams(plane, P1)
+	if d.N != 3 {
+		// the other rows may still miss the plane
+		Q := plane.V1.Add(plane.V2.Scale(lambda))
+		Q = Q.Add(plane.V3.Scale(mu))
+		if P1.Dist(Q) > tolerance(p, d, plane.V1, plane.V2, plane.V3) {
+			err = errors.ErrNoIntersection
+			return
+		}
+	}
+	x = rn.Vec{N: 3, X: []float64{s, lambda, mu}}
+	return
+}
+
+// planeParams returns λ and μ such that plane.V1 + λ*plane.V2 + μ*plane.V3 is closest to P
+func planeParams(plane Plane, P rn.Vec) (lambda, mu float64) {
+	w := P.Sub(plane.V1)
+	k2, k3 := binaryExponent(plane.V2), binaryExponent(plane.V3)
+	u, v := ldexpVec(plane.V2, -k2), ldexpVec(plane.V3, -k3)
+	g11, g12, g22 := u.Dot(u), u.Dot(v), v.Dot(v)
+	r1, r2 := u.Dot(w), v.Dot(w)
+	det := g11*g22 - g12*g12
+	lambda = math.Ldexp((g22*r1-g12*r2)/det, -k2)
+	mu = math.Ldexp((g11*r2-g12*r1)/det, -k3)
+	return
+}
+
+// orthonormalBasis returns two unit vectors that together with n form an orthogonal basis of R³
+func orthonormalBasis(n rn.Vec) (u, v rn.Vec) {
+	a := rn.Vec{N: 3, X: []float64{1, 0, 0}}
+	if math.Abs(n.X[0]) > math.Abs(n.X[1]) {
+		a = rn.Vec{N: 3, X: []float64{0, 1, 0}}
+	}
+	u = n.Cross(a)
+	u = u.Scale(1 / u.Norm())
+	v = n.Cross(u)
+	v = v.Scale(1 / v.Norm())
+	return
+}
+
+// parallelDirs returns true if the directions a and b are linearly dependent, decided exactly
+func parallelDirs(a, b rn.Vec) bool {
+	if a.N != b.N {
+		panic(errors.ErrShape)
+	}
+	for i := 0; i < a.N; i++ {
+		for j := i + 1; j < a.N; j++ {
+			// the 2 x 2 minors of [a b], the components of a × b in 3D
+			if robust.Orient2D([2]float64{a.X[i], a.X[j]}, [2]float64{b.X[i], b.X[j]}, [2]float64{}) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// pick3 returns the 3D vector of the components of v at the given indices
+func pick3(v rn.Vec, idx [3]int) rn.Vec {
+	return rn.Vec{N: 3, X: []float64{v.X[idx[0]], v.X[idx[1]], v.X[idx[2]]}}
+}
+
+func vec3(v rn.Vec) [3]float64 {
+	if v.N != 3 {
+		panic(errors.ErrShape)
+	}
+	return [3]float64{v.X[0], v.X[1], v.X[2]}
+}
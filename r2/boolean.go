@@ -0,0 +1,281 @@
+package r2
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Op is a boolean operation on regions
+type Op int
+
+const (
+	OpIntersection Op = iota
+	OpUnion
+	OpDifference
+	OpXor
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpIntersection:
+		return "Intersection"
+	case OpUnion:
+		return "Union"
+	case OpDifference:
+		return "Difference"
+	case OpXor:
+		return "Xor"
+	}
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+// fragment classes of a piece of one boundary relative to the other region
+const (
+	fragInside = iota
+	fragOutside
+	fragSame     // lies on an edge of the other region with the same direction
+	fragOpposite // lies on an edge of the other region with the opposite direction
+)
+
+type fragment struct {
+	a, b  Point
+	class int
+}
+
+// Intersection returns the area common to o and q
+//
+// Parameters:
+//
+//	q Region - The second region
+//
+// Returns:
+//
+//	r Region - o ∩ q
+func (o Region) Intersection(q Region) (r Region) {
+	r = o.Boolean(q, OpIntersection)
+	return
+}
+
+// Union returns the area covered by o or q
+//
+// Parameters:
+//
+//	q Region - The second region
+//
+// Returns:
+//
+//	r Region - o ∪ q
+func (o Region) Union(q Region) (r Region) {
+	r = o.Boolean(q, OpUnion)
+	return
+}
+
+// Difference returns the area of o that is not covered by q
+//
+// Parameters:
+//
+//	q Region - The region to subtract
+//
+// Returns:
+//
+//	r Region - o \ q
+func (o Region) Difference(q Region) (r Region) {
+	r = o.Boolean(q, OpDifference)
+	return
+}
+
+// Boolean combines two regions (Weiler's graph based polygon comparison)
+//
+// The boundaries of both regions are split at all points where they meet,
+// each resulting fragment is classified as inside, outside or on the
+// boundary of the other region, and the fragments the operation keeps are
+// linked into rings. Holes, shared edges, touching vertices and identical
+// inputs need no special treatment. Where rings of the result touch in a
+// single vertex they are returned as separate rings.
+//
+// Parameters:
+//
+//	q Region - The second region
+//	op Op - The operation
+//
+// Returns:
+//
+//	r Region - The result, outer boundaries counterclockwise and holes clockwise
+func (o Region) Boolean(q Region, op Op) (r Region) {
+	fa, fb := splitBoundaries(o, q)
+	classify(fa, q, fb)
+	classify(fb, o, fa)
+	var keep []fragment
+	for _, f := range fa {
+		switch {
+		case f.class == fragInside && op == OpIntersection,
+			f.class == fragOutside && op != OpIntersection,
+			f.class == fragSame && (op == OpIntersection || op == OpUnion),
+			f.class == fragOpposite && op == OpDifference:
+			keep = append(keep, f)
+		case f.class == fragInside && op == OpXor:
+			keep = append(keep, fragment{a: f.b, b: f.a})
+		}
+	}
+	for _, f := range fb {
+		switch {
+		case f.class == fragInside && op == OpIntersection,
+			f.class == fragOutside && (op == OpUnion || op == OpXor):
+			keep = append(keep, f)
+		case f.class == fragInside && (op == OpDifference || op == OpXor):
+			keep = append(keep, fragment{a: f.b, b: f.a})
+		}
+	}
+	r = linkRings(keep)
+	return
+}
+
+// splitBoundaries cuts the edges of both regions at every point where they meet the other region
+func splitBoundaries(o, q Region) (fa, fb []fragment) {
+	var ea, eb []Segment
+	for _, ring := range o {
+		for i := range ring {
+			ea = append(ea, ring.Edge(i))
+		}
+	}
+	for _, ring := range q {
+		for i := range ring {
+			eb = append(eb, ring.Edge(i))
+		}
+	}
+	cutsA, cutsB := make([][]Point, len(ea)), make([][]Point, len(eb))
+	for i, s := range ea {
+		for j, t := range eb {
+			// a crossing point is computed once and shared by both edges, so the fragments meet exactly
+			P1, P2, err := s.Intersect(t)
+			if err != nil {
+				continue
+			}
+			cutsA[i] = append(cutsA[i], P1, P2)
+			cutsB[j] = append(cutsB[j], P1, P2)
+		}
+	}
+	fa, fb = cutEdges(ea, cutsA), cutEdges(eb, cutsB)
+	return
+}
+
+// cutEdges splits every edge at its cut points
+func cutEdges(edges []Segment, cuts [][]Point) (frags []fragment) {
+	for i, s := range edges {
+		if s.A.Equal(s.B) {
+			continue
+		}
+		d := s.B.Sub(s.A)
+		param := func(P Point) float64 {
+			w := P.Sub(s.A)
+			return w.Dot(d)
+		}
+		Ps := append([]Point{s.A, s.B}, cuts[i]...)
+		sort.Slice(Ps, func(a, b int) bool { return param(Ps[a]) < param(Ps[b]) })
+		prev := s.A
+		for _, P := range Ps {
+			if P.Equal(prev) || param(P) <= param(prev) || param(P) > param(s.B) {
+				continue
+			}
+			frags = append(frags, fragment{a: prev, b: P})
+			prev = P
+		}
+		if !prev.Equal(s.B) {
+			frags = append(frags, fragment{a: prev, b: s.B})
+		}
+	}
+	return
+}
+
+// classify sets the class of the fragments of one region relative to the other region q with fragments fq
+func classify(frags []fragment, q Region, fq []fragment) {
+	edges := map[[2]Point]bool{}
+	for _, f := range fq {
+		edges[[2]Point{f.a, f.b}] = true
+	}
+	for k := range frags {
+		f := &frags[k]
+		switch {
+		case edges[[2]Point{f.a, f.b}]:
+			f.class = fragSame
+		case edges[[2]Point{f.b, f.a}]:
+			f.class = fragOpposite
+		case q.Locate(Point{(f.a.X + f.b.X) / 2, (f.a.Y + f.b.Y) / 2}) == Inside:
+			f.class = fragInside
+		default:
+			f.class = fragOutside
+		}
+	}
+}
+
+// linkRings joins directed fragments into closed rings, turning as far left as possible at
+// every vertex so that rings which touch in a vertex come out separately
+func linkRings(frags []fragment) (rings Region) {
+	out := map[Point][]int{}
+	for k, f := range frags {
+		out[f.a] = append(out[f.a], k)
+	}
+	used := make([]bool, len(frags))
+	for start := range frags {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		ring := Polygon{frags[start].a}
+		cur := frags[start]
+		closed := false
+		for len(ring) <= len(frags) {
+			if cur.b.Equal(frags[start].a) {
+				closed = true
+				break
+			}
+			ring = append(ring, cur.b)
+			next, best := -1, math.Inf(-1)
+			din := cur.b.Sub(cur.a)
+			for _, k := range out[cur.b] {
+				if used[k] {
+					continue
+				}
+				dout := frags[k].b.Sub(frags[k].a)
+				turn := math.Atan2(din.Cross(dout), din.Dot(dout))
+				if frags[k].b.Equal(cur.a) {
+					// going straight back is the last resort
+					turn = -math.Pi
+				}
+				if turn > best {
+					next, best = k, turn
+				}
+			}
+			if next < 0 {
+				break
+			}
+			used[next] = true
+			cur = frags[next]
+		}
+		if !closed {
+			continue
+		}
+		ring = dropCollinear(ring)
+		if len(ring) >= 3 && ring.Area() != 0 {
+			rings = append(rings, ring)
+		}
+	}
+	return
+}
+
+// dropCollinear removes vertices that lie on the straight edge between their neighbours
+func dropCollinear(ring Polygon) Polygon {
+	for changed := true; changed && len(ring) >= 3; {
+		changed = false
+		for i := range ring {
+			prev, next := ring[(i+len(ring)-1)%len(ring)], ring[(i+1)%len(ring)]
+			if (Segment{prev, next}).Contains(ring[i]) {
+				ring = append(ring[:i], ring[i+1:]...)
+				changed = true
+				break
+			}
+		}
+	}
+	return ring
+}
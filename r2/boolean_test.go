@@ -0,0 +1,83 @@
+package r2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRegionBoolean(t *testing.T) {
+	a := MakeRegion(Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}})
+	b := MakeRegion(Polygon{{1, 1}, {3, 1}, {3, 3}, {1, 3}})
+	adjacent := MakeRegion(Polygon{{2, 0}, {4, 0}, {4, 2}, {2, 2}})
+	corner := MakeRegion(Polygon{{2, 2}, {3, 2}, {3, 3}, {2, 3}})
+	frame := MakeRegion(Polygon{{0, 0}, {4, 0}, {4, 4}, {0, 4}}, Polygon{{1, 1}, {3, 1}, {3, 3}, {1, 3}})
+	inner := MakeRegion(Polygon{{1, 1}, {3, 1}, {3, 3}, {1, 3}})
+	tests := []struct {
+		a, b  Region
+		op    Op
+		area  float64
+		rings int
+	}{
+		{a, b, OpIntersection, 1, 1},
+		{a, b, OpUnion, 7, 1},
+		{a, b, OpDifference, 3, 1},
+		{a, b, OpXor, 6, 2},
+		{a, a, OpIntersection, 4, 1},
+		{a, a, OpUnion, 4, 1},
+		{a, a, OpDifference, 0, 0},
+		{a, adjacent, OpUnion, 8, 1},
+		{a, adjacent, OpIntersection, 0, 0},
+		{a, adjacent, OpDifference, 4, 1},
+		{a, corner, OpUnion, 5, 2},
+		{a, corner, OpIntersection, 0, 0},
+		{frame, inner, OpUnion, 16, 1},
+		{frame, inner, OpIntersection, 0, 0},
+		{frame, a, OpIntersection, 3, 1},
+		{frame, a, OpDifference, 9, 1},
+		{a, frame, OpDifference, 1, 1},
+		{frame, MakeRegion(Polygon{{2, -1}, {5, -1}, {5, 5}, {2, 5}}), OpDifference, 6, 1},
+	}
+	for _, tt := range tests {
+		got := tt.a.Boolean(tt.b, tt.op)
+		if math.Abs(got.Area()-tt.area) > 1e-12 || len(got) != tt.rings {
+			t.Errorf("error: %v\ngot=%v (%v rings)\nwant=%v (%v rings)", tt.op, got.Area(), len(got), tt.area, tt.rings)
+		}
+	}
+}
+
+func TestRegionBooleanRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	star := func(cx, cy float64, n int) Polygon {
+		p := make(Polygon, n)
+		for i := range p {
+			phi := 2 * math.Pi * float64(i) / float64(n)
+			r := 0.5 + rng.Float64()
+			p[i] = Point{cx + r*math.Cos(phi), cy + r*math.Sin(phi)}
+		}
+		return p
+	}
+	for trial := 0; trial < 20; trial++ {
+		a := MakeRegion(star(0, 0, 12), star(0, 0, 7).ClipConvex(Polygon{{-0.4, -0.4}, {0.4, -0.4}, {0.4, 0.4}, {-0.4, 0.4}}))
+		b := MakeRegion(star(0.7, 0.3, 9))
+		want := map[Op]func(x, y bool) bool{
+			OpIntersection: func(x, y bool) bool { return x && y },
+			OpUnion:        func(x, y bool) bool { return x || y },
+			OpDifference:   func(x, y bool) bool { return x && !y },
+			OpXor:          func(x, y bool) bool { return x != y },
+		}
+		for op, f := range want {
+			r := a.Boolean(b, op)
+			for k := 0; k < 200; k++ {
+				P := Point{4*rng.Float64() - 2, 4*rng.Float64() - 2}
+				la, lb, lr := a.Locate(P), b.Locate(P), r.Locate(P)
+				if la == Boundary || lb == Boundary || lr == Boundary {
+					continue
+				}
+				if got := lr == Inside; got != f(la == Inside, lb == Inside) {
+					t.Errorf("error: %v at %v\ngot=%v\nwant=%v", op, P, got, !got)
+				}
+			}
+		}
+	}
+}
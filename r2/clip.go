@@ -0,0 +1,53 @@
+package r2
+
+// ClipConvex clips the polygon against a convex polygon (Sutherland–Hodgman)
+//
+// The subject may be concave; where the result would fall apart into
+// several pieces they stay connected by edges along the clip boundary.
+// Use Region.Intersection when separate pieces are needed.
+//
+// Parameters:
+//
+//	clip Polygon - The convex clip polygon, in either winding
+//
+// Returns:
+//
+//	out Polygon - The part of o inside clip, with the winding of o; empty if they do not overlap
+func (o Polygon) ClipConvex(clip Polygon) (out Polygon) {
+	if clip.Winding() < 0 {
+		clip = clip.Reverse()
+	}
+	out = append(Polygon{}, o...)
+	for i := range clip {
+		if len(out) == 0 {
+			break
+		}
+		a, b := clip[i], clip[(i+1)%len(clip)]
+		in := out
+		out = Polygon{}
+		for k := range in {
+			P, Q := in[k], in[(k+1)%len(in)]
+			sp, sq := Orient(a, b, P), Orient(a, b, Q)
+			if sp >= 0 {
+				out = append(out, P)
+			}
+			if sp*sq < 0 {
+				out = append(out, lineCrossing(a, b, P, Q))
+			}
+		}
+	}
+	if len(out) < 3 {
+		out = Polygon{}
+	}
+	return
+}
+
+// lineCrossing returns where the segment PQ crosses the line through a and b
+func lineCrossing(a, b, P, Q Point) Point {
+	ab := b.Sub(a)
+	ap, aq := P.Sub(a), Q.Sub(a)
+	dp, dq := ab.Cross(ap), ab.Cross(aq)
+	t := dp / (dp - dq)
+	pq := Q.Sub(P)
+	return P.Add(pq.Scale(t))
+}
@@ -0,0 +1,30 @@
+package r2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClipConvex(t *testing.T) {
+	square := Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	tests := []struct {
+		subject, clip Polygon
+		area          float64
+	}{
+		{square, Polygon{{1, 1}, {3, 1}, {3, 3}, {1, 3}}, 1},
+		{square, Polygon{{1, 3}, {3, 3}, {3, 1}, {1, 1}}, 1},
+		{square, Polygon{{-1, -1}, {3, -1}, {3, 3}, {-1, 3}}, 4},
+		{square, Polygon{{3, 3}, {4, 3}, {4, 4}}, 0},
+		{square.Reverse(), Polygon{{0, 0}, {2, 0}, {0, 2}}, -2},
+		// a concave subject stays connected along the clip boundary
+		{Polygon{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}, Polygon{{0, 0}, {3, 0}, {3, 2}, {0, 2}}, 5},
+		// a shared edge
+		{square, Polygon{{2, 0}, {4, 0}, {4, 2}, {2, 2}}, 0},
+	}
+	for _, tt := range tests {
+		got := tt.subject.ClipConvex(tt.clip)
+		if math.Abs(got.Area()-tt.area) > 1e-12 {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", got, got.Area(), tt.area)
+		}
+	}
+}
@@ -0,0 +1,520 @@
+package r2
+
+import "github.com/add1609/lin/errors"
+
+// ghost is the vertex at infinity that closes the triangulation around its convex hull
+const ghost = -1
+
+// dtri is a triangle of the triangulation, ghost triangles contain the vertex at infinity
+type dtri struct {
+	v    [3]int // counterclockwise
+	nb   [3]int // nb[k] is the triangle across the edge opposite v[k]
+	dead bool
+}
+
+// Triangulation is a (constrained) Delaunay triangulation of a 2D point set
+type Triangulation struct {
+	Points      []Point
+	Triangles   [][3]int // vertex indices into Points, counterclockwise
+	Neighbors   [][3]int // Neighbors[t][k] is the triangle across the edge opposite Triangles[t][k], -1 on the hull
+	tris        []dtri   // Triangles followed by the ghost triangles
+	vertTri     []int    // a triangle at every vertex, -1 for duplicate points that are not used
+	constrained map[[2]int]bool
+}
+
+// MakeDelaunay returns the Delaunay triangulation of a point set (Bowyer–Watson)
+//
+// The points are inserted one at a time; the triangles whose circumcircle
+// contains the new point are removed and the cavity is filled with triangles
+// fanning out from it. The convex hull is closed with ghost triangles that
+// share a vertex at infinity, so points outside the current hull need no
+// super triangle. All decisions use the exact predicates Orient and InCircle.
+// Duplicate points are not used as vertices.
+//
+// Parameters:
+//
+//	points []Point - The points
+//
+// Returns:
+//
+//	tri Triangulation - The triangulation
+//	err error - ErrDegenerate if fewer than three points are not collinear
+func MakeDelaunay(points []Point) (tri Triangulation, err error) {
+	tri.Points = points
+	tri.constrained = map[[2]int]bool{}
+	a, b, c := 0, -1, -1
+	for k := 1; k < len(points); k++ {
+		if !points[k].Equal(points[a]) {
+			b = k
+			break
+		}
+	}
+	for k := b + 1; b >= 0 && k < len(points); k++ {
+		if Orient(points[a], points[b], points[k]) != 0 {
+			c = k
+			break
+		}
+	}
+	if c < 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	if Orient(points[a], points[b], points[c]) < 0 {
+		b, c = c, b
+	}
+	tri.tris = []dtri{{v: [3]int{a, b, c}}, {v: [3]int{b, a, ghost}}, {v: [3]int{c, b, ghost}}, {v: [3]int{a, c, ghost}}}
+	tri.link([]int{0, 1, 2, 3}, nil)
+	last := 0
+	for k := range points {
+		if k != a && k != b && k != c {
+			last = tri.insert(k, last)
+		}
+	}
+	tri.compact()
+	return
+}
+
+// link sets the neighbors of the new triangles ts, edges not shared among them are
+// looked up in outer, which maps a directed edge to the triangle on its left
+func (o *Triangulation) link(ts []int, outer map[[2]int]int) {
+	type slot struct{ t, k int }
+	edges := map[[2]int]slot{}
+	for _, t := range ts {
+		v := o.tris[t].v
+		for k := 0; k < 3; k++ {
+			edges[[2]int{v[(k+1)%3], v[(k+2)%3]}] = slot{t, k}
+		}
+	}
+	for _, t := range ts {
+		v := o.tris[t].v
+		for k := 0; k < 3; k++ {
+			twin := [2]int{v[(k+2)%3], v[(k+1)%3]}
+			if s, ok := edges[twin]; ok {
+				o.tris[t].nb[k] = s.t
+				continue
+			}
+			u := outer[twin]
+			o.tris[t].nb[k] = u
+			w := o.tris[u].v
+			for kk := 0; kk < 3; kk++ {
+				if w[(kk+1)%3] == twin[0] && w[(kk+2)%3] == twin[1] {
+					o.tris[u].nb[kk] = t
+				}
+			}
+		}
+	}
+}
+
+func (o *Triangulation) isGhost(t int) bool {
+	v := o.tris[t].v
+	return v[0] == ghost || v[1] == ghost || v[2] == ghost
+}
+
+// conflict returns true if P lies strictly inside the circumcircle of triangle t,
+// for a ghost triangle the circumcircle degenerates to the open half plane beyond its hull edge
+func (o *Triangulation) conflict(t int, P Point) bool {
+	v := o.tris[t].v
+	for k := 0; k < 3; k++ {
+		if v[k] == ghost {
+			a, b := o.Points[v[(k+1)%3]], o.Points[v[(k+2)%3]]
+			s := Orient(a, b, P)
+			return s > 0 || s == 0 && (Segment{a, b}).Contains(P) && !P.Equal(a) && !P.Equal(b)
+		}
+	}
+	return InCircle(o.Points[v[0]], o.Points[v[1]], o.Points[v[2]], P) > 0
+}
+
+// walk returns the triangle that contains P, or a ghost triangle whose hull edge P lies beyond
+func (o *Triangulation) walk(P Point, start int) (t int, dup bool) {
+	t = start
+	if o.isGhost(t) {
+		for k := 0; k < 3; k++ {
+			if o.tris[t].v[k] == ghost {
+				t = o.tris[t].nb[k]
+				break
+			}
+		}
+	}
+	for step := 0; step <= len(o.tris); step++ {
+		if o.isGhost(t) {
+			return
+		}
+		v := o.tris[t].v
+		for k := 0; k < 3; k++ {
+			if P.Equal(o.Points[v[k]]) {
+				dup = true
+				return
+			}
+		}
+		moved := false
+		// rotating the first edge tested keeps the walk from cycling
+		for j := 0; j < 3 && !moved; j++ {
+			k := (j + step) % 3
+			if Orient(o.Points[v[(k+1)%3]], o.Points[v[(k+2)%3]], P) < 0 {
+				t, moved = o.tris[t].nb[k], true
+			}
+		}
+		if !moved {
+			return
+		}
+	}
+	t, dup = o.scan(P)
+	return
+}
+
+// scan finds the triangle that contains P by testing every triangle
+func (o *Triangulation) scan(P Point) (t int, dup bool) {
+	t = -1
+	for u := range o.tris {
+		if o.tris[u].dead {
+			continue
+		}
+		v := o.tris[u].v
+		inside := true
+		if o.isGhost(u) {
+			for k := 0; k < 3; k++ {
+				if v[k] == ghost {
+					inside = Orient(o.Points[v[(k+1)%3]], o.Points[v[(k+2)%3]], P) > 0
+				}
+			}
+		} else {
+			for k := 0; k < 3; k++ {
+				if P.Equal(o.Points[v[k]]) {
+					return u, true
+				}
+				inside = inside && Orient(o.Points[v[(k+1)%3]], o.Points[v[(k+2)%3]], P) >= 0
+			}
+		}
+		if inside && (t < 0 || o.isGhost(t)) {
+			t = u
+		}
+	}
+	return
+}
+
+// insert adds point p to the triangulation and returns a new triangle at p
+func (o *Triangulation) insert(p, start int) (last int) {
+	P := o.Points[p]
+	t, dup := o.walk(P, start)
+	if dup {
+		return start
+	}
+	bad := map[int]bool{t: true}
+	cavity := []int{t}
+	for q := 0; q < len(cavity); q++ {
+		for _, u := range o.tris[cavity[q]].nb {
+			if !bad[u] && o.conflict(u, P) {
+				bad[u] = true
+				cavity = append(cavity, u)
+			}
+		}
+	}
+	outer := map[[2]int]int{}
+	var ts []int
+	for _, u := range cavity {
+		o.tris[u].dead = true
+		for k := 0; k < 3; k++ {
+			if w := o.tris[u].nb[k]; !bad[w] {
+				e0, e1 := o.tris[u].v[(k+1)%3], o.tris[u].v[(k+2)%3]
+				outer[[2]int{e1, e0}] = w
+				o.tris = append(o.tris, dtri{v: [3]int{e0, e1, p}})
+				ts = append(ts, len(o.tris)-1)
+			}
+		}
+	}
+	o.link(ts, outer)
+	for _, u := range ts {
+		if !o.isGhost(u) {
+			last = u
+		}
+	}
+	return
+}
+
+// compact drops dead triangles, moves the ghost triangles to the end and updates the exported fields
+func (o *Triangulation) compact() {
+	remap := make([]int, len(o.tris))
+	var tris []dtri
+	for pass := 0; pass < 2; pass++ {
+		for t, d := range o.tris {
+			if !d.dead && o.isGhost(t) == (pass == 1) {
+				remap[t] = len(tris)
+				tris = append(tris, d)
+			}
+		}
+	}
+	nReal := 0
+	for t := range tris {
+		for k := 0; k < 3; k++ {
+			tris[t].nb[k] = remap[tris[t].nb[k]]
+		}
+		if tris[t].v[0] != ghost && tris[t].v[1] != ghost && tris[t].v[2] != ghost {
+			nReal++
+		}
+	}
+	o.tris = tris
+	o.Triangles = make([][3]int, nReal)
+	o.Neighbors = make([][3]int, nReal)
+	o.vertTri = make([]int, len(o.Points))
+	for i := range o.vertTri {
+		o.vertTri[i] = -1
+	}
+	for t := 0; t < nReal; t++ {
+		o.Triangles[t] = tris[t].v
+		for k := 0; k < 3; k++ {
+			o.Neighbors[t][k] = tris[t].nb[k]
+			if o.Neighbors[t][k] >= nReal {
+				o.Neighbors[t][k] = -1
+			}
+			o.vertTri[tris[t].v[k]] = t
+		}
+	}
+}
+
+func edgeKey(i, j int) [2]int {
+	if j < i {
+		i, j = j, i
+	}
+	return [2]int{i, j}
+}
+
+// Constrain forces the edge between two vertices into the triangulation
+//
+// The triangles crossed by the edge are removed and the two holes on either
+// side of it are retriangulated as constrained Delaunay triangulations
+// (Anglada, An improved incremental algorithm for constructing restricted
+// Delaunay triangulations, 1997). Vertices that lie on the edge split it
+// into several constrained edges.
+//
+// Parameters:
+//
+//	i int - The index of the first vertex
+//	j int - The index of the second vertex
+//
+// Returns:
+//
+//	err error - ErrConstraint if the edge crosses a constrained edge,
+//	ErrDegenerate if i or j is a duplicate point that is not a vertex
+func (o *Triangulation) Constrain(i, j int) (err error) {
+	if i < 0 || j < 0 || i >= len(o.Points) || j >= len(o.Points) || i == j {
+		panic(errors.ErrIndexOutOfRange)
+	}
+	if o.vertTri[i] < 0 || o.vertTri[j] < 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	err = o.constrain(i, j)
+	o.compact()
+	return
+}
+
+func (o *Triangulation) constrain(i, j int) (err error) {
+	Pi, Pj := o.Points[i], o.Points[j]
+	// the triangle at i whose angle contains the direction to j
+	start, a, b := -1, -1, -1
+	for t, d := range o.tris {
+		if d.dead || o.isGhost(t) {
+			continue
+		}
+		for k := 0; k < 3; k++ {
+			if d.v[k] != i {
+				continue
+			}
+			u, w := d.v[(k+1)%3], d.v[(k+2)%3]
+			for _, x := range []int{u, w} {
+				if x == j {
+					o.constrained[edgeKey(i, j)] = true
+					return
+				}
+				if (Segment{Pi, Pj}).Contains(o.Points[x]) {
+					if err = o.constrain(i, x); err == nil {
+						err = o.constrain(x, j)
+					}
+					return
+				}
+			}
+			if Orient(Pi, o.Points[u], Pj) > 0 && Orient(Pi, o.Points[w], Pj) < 0 {
+				start, a, b = t, u, w
+			}
+		}
+	}
+	if start < 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	// walk along the edge, a is right and b is left of i→j
+	removed := []int{start}
+	left, right := []int{b}, []int{a}
+	for t := start; ; {
+		if o.constrained[edgeKey(a, b)] {
+			err = errors.ErrConstraint
+			return
+		}
+		var u int
+		for k := 0; k < 3; k++ {
+			if v := o.tris[t].v[k]; v != a && v != b {
+				u = o.tris[t].nb[k]
+			}
+		}
+		var w int
+		for _, v := range o.tris[u].v {
+			if v != a && v != b {
+				w = v
+			}
+		}
+		removed = append(removed, u)
+		if w == j {
+			break
+		}
+		switch Orient(Pi, Pj, o.Points[w]) {
+		case 0:
+			if err = o.constrain(i, w); err == nil {
+				err = o.constrain(w, j)
+			}
+			return
+		case 1:
+			left, b = append(left, w), w
+		default:
+			right, a = append(right, w), w
+		}
+		t = u
+	}
+	gone := map[int]bool{}
+	for _, t := range removed {
+		gone[t] = true
+	}
+	outer := map[[2]int]int{}
+	for _, t := range removed {
+		o.tris[t].dead = true
+		for k := 0; k < 3; k++ {
+			if u := o.tris[t].nb[k]; !gone[u] {
+				e0, e1 := o.tris[t].v[(k+1)%3], o.tris[t].v[(k+2)%3]
+				outer[[2]int{e1, e0}] = u
+			}
+		}
+	}
+	for l, r := 0, len(right)-1; l < r; l, r = l+1, r-1 {
+		right[l], right[r] = right[r], right[l]
+	}
+	var ts []int
+	o.fillCavity(i, j, left, &ts)
+	o.fillCavity(j, i, right, &ts)
+	o.link(ts, outer)
+	o.constrained[edgeKey(i, j)] = true
+	return
+}
+
+// fillCavity triangulates the polygon formed by the edge a→b and the chain of vertices
+// to its left, ordered from a to b
+func (o *Triangulation) fillCavity(a, b int, chain []int, ts *[]int) {
+	if len(chain) == 0 {
+		return
+	}
+	Pa, Pb := o.Points[a], o.Points[b]
+	c := 0
+	for k := 1; k < len(chain); k++ {
+		if InCircle(Pa, Pb, o.Points[chain[c]], o.Points[chain[k]]) > 0 {
+			c = k
+		}
+	}
+	o.tris = append(o.tris, dtri{v: [3]int{a, b, chain[c]}})
+	*ts = append(*ts, len(o.tris)-1)
+	o.fillCavity(a, chain[c], chain[:c], ts)
+	o.fillCavity(chain[c], b, chain[c+1:], ts)
+}
+
+// IsConstrained returns true if the edge between two vertices has been constrained
+//
+// Parameters:
+//
+//	i int - The index of the first vertex
+//	j int - The index of the second vertex
+//
+// Returns:
+//
+//	bool - true if Constrain added the edge, in either direction
+func (o *Triangulation) IsConstrained(i, j int) bool {
+	return o.constrained[edgeKey(i, j)]
+}
+
+// Edges returns the edges of the triangulation
+//
+// Parameters:
+//
+//	o *Triangulation - The triangulation
+//
+// Returns:
+//
+//	edges [][2]int - Every edge once, with the smaller vertex index first
+func (o *Triangulation) Edges() (edges [][2]int) {
+	for t, v := range o.Triangles {
+		for k := 0; k < 3; k++ {
+			i, j := v[(k+1)%3], v[(k+2)%3]
+			if u := o.Neighbors[t][k]; u < 0 || t < u {
+				edges = append(edges, edgeKey(i, j))
+			}
+		}
+	}
+	return
+}
+
+// Locate returns the triangle that contains P
+//
+// Parameters:
+//
+//	P Point - The query point
+//
+// Returns:
+//
+//	t int - The index into Triangles of a triangle that contains P, -1 if P is outside
+//	err error - ErrNoIntersection if P lies outside the convex hull
+func (o *Triangulation) Locate(P Point) (t int, err error) {
+	t, _ = o.walk(P, 0)
+	if t < 0 || t >= len(o.Triangles) {
+		t, err = -1, errors.ErrNoIntersection
+	}
+	return
+}
+
+// TrianglesAround returns the triangles that share vertex i
+//
+// Parameters:
+//
+//	i int - The index of the vertex
+//
+// Returns:
+//
+//	ts []int - The indices into Triangles in counterclockwise order around the vertex,
+//	starting after the hull if i lies on it; empty for duplicate points
+func (o *Triangulation) TrianglesAround(i int) (ts []int) {
+	start := o.vertTri[i]
+	if start < 0 {
+		return
+	}
+	// position of i in triangle t
+	at := func(t int) int {
+		for k := 0; k < 3; k++ {
+			if o.Triangles[t][k] == i {
+				return k
+			}
+		}
+		return -1
+	}
+	// turn clockwise until the hull or back at the start
+	first := start
+	for {
+		prev := o.Neighbors[first][(at(first)+2)%3]
+		if prev < 0 || prev == start {
+			break
+		}
+		first = prev
+	}
+	for t := first; ; {
+		ts = append(ts, t)
+		t = o.Neighbors[t][(at(t)+1)%3]
+		if t < 0 || t == first {
+			break
+		}
+	}
+	return
+}
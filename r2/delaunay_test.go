@@ -0,0 +1,179 @@
+package r2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// checkTriangulation verifies orientation, neighbor symmetry and that the triangles tile the convex hull
+func checkTriangulation(t *testing.T, tri Triangulation) {
+	t.Helper()
+	var area float64
+	for u, v := range tri.Triangles {
+		a, b, c := tri.Points[v[0]], tri.Points[v[1]], tri.Points[v[2]]
+		if Orient(a, b, c) <= 0 {
+			t.Errorf("error: triangle %v\ngot=%v\nwant=%v", v, Orient(a, b, c), 1)
+		}
+		area += Area2(a, b, c) / 2
+		for k, w := range tri.Neighbors[u] {
+			if w < 0 {
+				continue
+			}
+			back := false
+			for _, x := range tri.Neighbors[w] {
+				back = back || x == u
+			}
+			i, j := v[(k+1)%3], v[(k+2)%3]
+			shared := 0
+			for _, x := range tri.Triangles[w] {
+				if x == i || x == j {
+					shared++
+				}
+			}
+			if !back || shared != 2 {
+				t.Errorf("error: neighbors %v %v\ngot=%v %v\nwant=%v %v", u, w, back, shared, true, 2)
+			}
+		}
+	}
+	hull := ConvexHull(tri.Points)
+	if math.Abs(area-hull.Area()) > 1e-9*math.Abs(hull.Area()) {
+		t.Errorf("error:\ngot=%v\nwant=%v", area, hull.Area())
+	}
+}
+
+func TestDelaunayRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([]Point, 300)
+	for i := range points {
+		points[i] = Point{rng.Float64(), rng.Float64()}
+	}
+	tri, err := MakeDelaunay(points)
+	if err != nil {
+		t.Fatalf("error:\ngot=%v\nwant=%v", err, nil)
+	}
+	checkTriangulation(t, tri)
+	// empty circumcircles
+	for _, v := range tri.Triangles {
+		for i, P := range points {
+			if i != v[0] && i != v[1] && i != v[2] && InCircle(points[v[0]], points[v[1]], points[v[2]], P) > 0 {
+				t.Errorf("error: triangle %v\ngot=%v\nwant=%v", v, i, "no point in circumcircle")
+			}
+		}
+	}
+	// Euler: 2n - 2 - h triangles for n points with h on the hull
+	if want := 2*len(points) - 2 - len(ConvexHull(points)); len(tri.Triangles) != want {
+		t.Errorf("error:\ngot=%v\nwant=%v", len(tri.Triangles), want)
+	}
+	P := Point{0.5, 0.5}
+	if u, err := tri.Locate(P); err != nil || !(Polygon{points[tri.Triangles[u][0]], points[tri.Triangles[u][1]], points[tri.Triangles[u][2]]}).Contains(P) {
+		t.Errorf("error:\ngot=%v (%v)\nwant=%v", u, err, "a triangle containing P")
+	}
+	if _, err := tri.Locate(Point{2, 2}); err == nil {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, "ErrNoIntersection")
+	}
+}
+
+func TestDelaunayDegenerate(t *testing.T) {
+	// a grid is full of cocircular and collinear points, with duplicates
+	var points []Point
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 6; j++ {
+			points = append(points, Point{float64(i), float64(j)})
+		}
+	}
+	points = append(points, Point{2, 3}, Point{0, 0})
+	tri, err := MakeDelaunay(points)
+	if err != nil {
+		t.Fatalf("error:\ngot=%v\nwant=%v", err, nil)
+	}
+	checkTriangulation(t, tri)
+	if len(tri.Triangles) != 50 {
+		t.Errorf("error:\ngot=%v\nwant=%v", len(tri.Triangles), 50)
+	}
+	if ts := tri.TrianglesAround(len(points) - 1); len(ts) != 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", ts, "no triangles at a duplicate")
+	}
+	// an interior grid vertex is surrounded by 6 or 8 triangles, a corner by 1 or 2
+	if ts := tri.TrianglesAround(14); len(ts) != 6 && len(ts) != 8 {
+		t.Errorf("error:\ngot=%v\nwant=%v", len(ts), "6 or 8")
+	}
+	for _, points := range [][]Point{nil, {{0, 0}, {1, 1}, {2, 2}, {3, 3}}, {{1, 1}, {1, 1}, {1, 1}}} {
+		if _, err := MakeDelaunay(points); err == nil {
+			t.Errorf("error:\ngot=%v\nwant=%v", err, "ErrDegenerate")
+		}
+	}
+}
+
+func TestDelaunayConstrain(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	points := []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {1, 4}, {9, 6}, {5, 5}}
+	for i := 0; i < 200; i++ {
+		points = append(points, Point{10 * rng.Float64(), 10 * rng.Float64()})
+	}
+	tri, err := MakeDelaunay(points)
+	if err != nil {
+		t.Fatalf("error:\ngot=%v\nwant=%v", err, nil)
+	}
+	// 4 → 6 → 5 is a straight line, so the constraint is split at 6
+	if err := tri.Constrain(4, 5); err != nil {
+		t.Fatalf("error:\ngot=%v\nwant=%v", err, nil)
+	}
+	checkTriangulation(t, tri)
+	if !tri.IsConstrained(4, 6) || !tri.IsConstrained(5, 6) || tri.IsConstrained(4, 5) {
+		t.Errorf("error:\ngot=%v %v %v\nwant=%v %v %v", tri.IsConstrained(4, 6), tri.IsConstrained(5, 6), tri.IsConstrained(4, 5), true, true, false)
+	}
+	found := 0
+	for _, e := range tri.Edges() {
+		if e == [2]int{4, 6} || e == [2]int{5, 6} {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("error:\ngot=%v\nwant=%v", found, 2)
+	}
+	// the diagonal passes through 6 as well
+	if err := tri.Constrain(0, 2); err != nil {
+		t.Fatalf("error:\ngot=%v\nwant=%v", err, nil)
+	}
+	checkTriangulation(t, tri)
+	if err := tri.Constrain(1, 4); err == nil {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, "ErrConstraint")
+	}
+}
+
+func TestVoronoi(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	points := make([]Point, 100)
+	for i := range points {
+		points[i] = Point{rng.Float64(), rng.Float64()}
+	}
+	tri, err := MakeDelaunay(points)
+	if err != nil {
+		t.Fatalf("error:\ngot=%v\nwant=%v", err, nil)
+	}
+	vor := tri.Voronoi()
+	bounds := Polygon{{-1, -1}, {2, -1}, {2, 2}, {-1, 2}}
+	cells := make([]Polygon, len(points))
+	var area float64
+	for i := range points {
+		cells[i] = vor.Cell(i, bounds)
+		area += cells[i].Area()
+	}
+	if math.Abs(area-9) > 1e-9 {
+		t.Errorf("error:\ngot=%v\nwant=%v", area, 9)
+	}
+	// every point lies in the cell of its nearest site
+	for k := 0; k < 200; k++ {
+		P := Point{3*rng.Float64() - 1, 3*rng.Float64() - 1}
+		near := 0
+		for i := range points {
+			if P.Dist(points[i]) < P.Dist(points[near]) {
+				near = i
+			}
+		}
+		if !cells[near].Contains(P) {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", P, cells[near].Locate(P), "Inside")
+		}
+	}
+}
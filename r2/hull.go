@@ -0,0 +1,50 @@
+package r2
+
+import "sort"
+
+// ConvexHull returns the convex hull of a point set (Andrew's monotone chain)
+//
+// Parameters:
+//
+//	points []Point - The points, they are not modified
+//
+// Returns:
+//
+//	hull Polygon - The corners of the hull in counterclockwise order starting at the
+//	lowest-leftmost point; collinear points and duplicates are dropped, so fewer than
+//	three points are returned if the input is degenerate
+func ConvexHull(points []Point) (hull Polygon) {
+	Ps := make([]Point, len(points))
+	copy(Ps, points)
+	sort.Slice(Ps, func(a, b int) bool { return Ps[a].less(Ps[b]) })
+	uniq := Ps[:0]
+	for i, P := range Ps {
+		if i == 0 || !P.Equal(Ps[i-1]) {
+			uniq = append(uniq, P)
+		}
+	}
+	Ps = uniq
+	if len(Ps) < 3 {
+		hull = Polygon(Ps)
+		return
+	}
+	hull = make(Polygon, 0, 2*len(Ps))
+	// lower chain left to right, then upper chain right to left
+	for _, P := range Ps {
+		for len(hull) >= 2 && Orient(hull[len(hull)-2], hull[len(hull)-1], P) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, P)
+	}
+	lower := len(hull) + 1
+	for i := len(Ps) - 2; i >= 0; i-- {
+		P := Ps[i]
+		for len(hull) >= lower && Orient(hull[len(hull)-2], hull[len(hull)-1], P) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, P)
+	}
+	// the last point repeats the first
+	hull = hull[:len(hull)-1]
+	return
+}
@@ -0,0 +1,51 @@
+package r2
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	tests := []struct {
+		points []Point
+		want   Polygon
+	}{
+		{
+			[]Point{{1, 1}, {0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 0}, {0, 2}, {1, 1.5}},
+			Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+		},
+		{[]Point{{0, 0}, {1, 1}, {2, 2}, {1, 1}}, Polygon{{0, 0}, {2, 2}}},
+		{[]Point{{3, 4}, {3, 4}}, Polygon{{3, 4}}},
+		{nil, Polygon{}},
+	}
+	for _, tt := range tests {
+		got := ConvexHull(tt.points)
+		if len(got) != len(tt.want) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("error:\ngot=%v\nwant=%v", got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestConvexHullRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([]Point, 500)
+	for i := range points {
+		points[i] = Point{rng.NormFloat64(), rng.NormFloat64()}
+	}
+	hull := ConvexHull(points)
+	if !hull.IsConvex() || hull.Winding() != 1 {
+		t.Errorf("error:\ngot=%v\nwant=%v", hull, "a counterclockwise convex polygon")
+	}
+	for _, P := range points {
+		if !hull.Contains(P) {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", P, Outside, "Inside or Boundary")
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package r2
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+	"github.com/add1609/lin/scalar"
+)
+
+// Point is a point or vector in the plane
+type Point struct {
+	X, Y float64
+}
+
+func (o Point) String() (str string) {
+	str += fmt.Sprintf("(%v, %v)", scalar.RoundTo(o.X, 4), scalar.RoundTo(o.Y, 4))
+	return
+}
+
+// MakePointFromVec returns the Point with the coordinates of a 2D vector
+//
+// Parameters:
+//
+//	v rn.Vec - A vector with N = 2
+//
+// Returns:
+//
+//	P Point - The point (v[0], v[1])
+func MakePointFromVec(v rn.Vec) (P Point) {
+	if v.N != 2 {
+		panic(errors.ErrShape)
+	}
+	P = Point{v.X[0], v.X[1]}
+	return
+}
+
+// Vec returns the point as a 2D vector
+//
+// Parameters:
+//
+//	o Point - The point
+//
+// Returns:
+//
+//	v rn.Vec - The vector (X, Y)
+func (o Point) Vec() (v rn.Vec) {
+	v = rn.Vec{N: 2, X: []float64{o.X, o.Y}}
+	return
+}
+
+// Add returns the vector sum of o and q
+//
+// Parameters:
+//
+//	q Point - The vector to add
+//
+// Returns:
+//
+//	P Point - o + q
+func (o Point) Add(q Point) (P Point) {
+	P = Point{o.X + q.X, o.Y + q.Y}
+	return
+}
+
+// Sub returns the vector difference of o and q
+//
+// Parameters:
+//
+//	q Point - The vector to subtract
+//
+// Returns:
+//
+//	P Point - o - q
+func (o Point) Sub(q Point) (P Point) {
+	P = Point{o.X - q.X, o.Y - q.Y}
+	return
+}
+
+// Scale returns o scaled by r
+//
+// Parameters:
+//
+//	r float64 - The scalar
+//
+// Returns:
+//
+//	P Point - r * o
+func (o Point) Scale(r float64) (P Point) {
+	P = Point{r * o.X, r * o.Y}
+	return
+}
+
+// Dot returns the dot product of o and q
+//
+// Parameters:
+//
+//	q Point - The second vector
+//
+// Returns:
+//
+//	d float64 - o · q
+func (o Point) Dot(q Point) (d float64) {
+	d = o.X*q.X + o.Y*q.Y
+	return
+}
+
+// Cross returns the z component of the cross product of o and q
+//
+// Parameters:
+//
+//	q Point - The second vector
+//
+// Returns:
+//
+//	c float64 - o.X * q.Y - o.Y * q.X
+func (o Point) Cross(q Point) (c float64) {
+	c = o.X*q.Y - o.Y*q.X
+	return
+}
+
+// Norm returns the length of o
+//
+// Parameters:
+//
+//	o Point - The vector
+//
+// Returns:
+//
+//	nrm float64 - |o|
+func (o Point) Norm() (nrm float64) {
+	nrm = math.Hypot(o.X, o.Y)
+	return
+}
+
+// Dist returns the distance between o and q
+//
+// Parameters:
+//
+//	q Point - The second point
+//
+// Returns:
+//
+//	dist float64 - |q - o|
+func (o Point) Dist(q Point) (dist float64) {
+	dist = math.Hypot(q.X-o.X, q.Y-o.Y)
+	return
+}
+
+// Equal returns true if o and q are equal
+//
+// Parameters:
+//
+//	q Point - point to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o Point) Equal(q Point) bool {
+	return o.X == q.X && o.Y == q.Y
+}
+
+// less orders points lexicographically by X, then Y
+func (o Point) less(q Point) bool {
+	return o.X < q.X || o.X == q.X && o.Y < q.Y
+}
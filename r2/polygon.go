@@ -0,0 +1,283 @@
+package r2
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/add1609/lin/errors"
+)
+
+// Polygon is a closed polygon given by its vertices, the last vertex connects back to the first
+type Polygon []Point
+
+func (o Polygon) String() (str string) {
+	str += fmt.Sprintf("%v", []Point(o))
+	return
+}
+
+// Location describes where a point lies relative to a polygon
+type Location int
+
+const (
+	Outside  Location = -1
+	Boundary Location = 0
+	Inside   Location = 1
+)
+
+func (o Location) String() string {
+	switch o {
+	case Outside:
+		return "Outside"
+	case Boundary:
+		return "Boundary"
+	case Inside:
+		return "Inside"
+	}
+	return fmt.Sprintf("Location(%d)", int(o))
+}
+
+// Edge returns the i-th edge of the polygon
+//
+// Parameters:
+//
+//	i int - The index of the edge, from vertex i to vertex i+1
+//
+// Returns:
+//
+//	s Segment - The edge
+func (o Polygon) Edge(i int) (s Segment) {
+	if i < 0 || i >= len(o) {
+		panic(errors.ErrIndexOutOfRange)
+	}
+	s = Segment{o[i], o[(i+1)%len(o)]}
+	return
+}
+
+// Area returns the signed area of the polygon (shoelace formula)
+//
+// Parameters:
+//
+//	o Polygon - The polygon
+//
+// Returns:
+//
+//	area float64 - Positive if the vertices run counterclockwise, negative if clockwise
+func (o Polygon) Area() (area float64) {
+	if len(o) < 3 {
+		return
+	}
+	// summing relative to the first vertex avoids cancellation far from the origin
+	for i := 1; i < len(o)-1; i++ {
+		area += Area2(o[0], o[i], o[i+1])
+	}
+	area /= 2
+	return
+}
+
+// Centroid returns the center of mass of the polygon area
+//
+// Parameters:
+//
+//	o Polygon - The polygon
+//
+// Returns:
+//
+//	C Point - The centroid
+//	err error - ErrDegenerate if the polygon has zero area
+func (o Polygon) Centroid() (C Point, err error) {
+	var area float64
+	for i := 1; i < len(o)-1; i++ {
+		a := Area2(o[0], o[i], o[i+1])
+		area += a
+		C.X += a * (o[i].X + o[i+1].X - 2*o[0].X)
+		C.Y += a * (o[i].Y + o[i+1].Y - 2*o[0].Y)
+	}
+	if area == 0 {
+		err = errors.ErrDegenerate
+		return
+	}
+	C = Point{o[0].X + C.X/(3*area), o[0].Y + C.Y/(3*area)}
+	return
+}
+
+// Winding returns the orientation of the polygon
+//
+// Parameters:
+//
+//	o Polygon - The polygon
+//
+// Returns:
+//
+//	w int - 1 if the vertices run counterclockwise, -1 if clockwise, 0 if the area is zero
+func (o Polygon) Winding() (w int) {
+	w = sign(o.Area())
+	return
+}
+
+// Reverse returns the polygon with its vertices in the opposite order
+//
+// Parameters:
+//
+//	o Polygon - The polygon
+//
+// Returns:
+//
+//	p Polygon - A new polygon with the opposite winding
+func (o Polygon) Reverse() (p Polygon) {
+	p = make(Polygon, len(o))
+	for i, P := range o {
+		p[len(o)-1-i] = P
+	}
+	return
+}
+
+// Locate returns whether P lies inside, outside or on the boundary of the polygon
+//
+// Points are classified with the winding number, so the result does not
+// depend on the winding of the polygon; for self-intersecting polygons
+// regions with a nonzero winding number are inside. All decisions are exact.
+//
+// Parameters:
+//
+//	P Point - The query point
+//
+// Returns:
+//
+//	loc Location - Inside, Outside or Boundary
+func (o Polygon) Locate(P Point) (loc Location) {
+	wn, onBoundary := o.winding(P)
+	switch {
+	case onBoundary:
+		loc = Boundary
+	case wn != 0:
+		loc = Inside
+	default:
+		loc = Outside
+	}
+	return
+}
+
+// winding returns the winding number of the polygon around P, or onBoundary if P lies on an edge
+func (o Polygon) winding(P Point) (wn int, onBoundary bool) {
+	for i := range o {
+		a, b := o[i], o[(i+1)%len(o)]
+		if (Segment{a, b}).Contains(P) {
+			onBoundary = true
+			return
+		}
+		if a.Y <= P.Y {
+			if b.Y > P.Y && Orient(a, b, P) > 0 {
+				wn++
+			}
+		} else if b.Y <= P.Y && Orient(a, b, P) < 0 {
+			wn--
+		}
+	}
+	return
+}
+
+// Contains returns true if P lies inside or on the boundary of the polygon
+//
+// Parameters:
+//
+//	P Point - The query point
+//
+// Returns:
+//
+//	bool - true if Locate(P) is not Outside
+func (o Polygon) Contains(P Point) bool {
+	return o.Locate(P) != Outside
+}
+
+// IsConvex returns true if the polygon is convex
+//
+// Parameters:
+//
+//	o Polygon - The polygon
+//
+// Returns:
+//
+//	bool - true if the polygon is simple and all turns have the same direction,
+//	collinear vertices are allowed
+func (o Polygon) IsConvex() bool {
+	turn := 0
+	for i := range o {
+		s := Orient(o[i], o[(i+1)%len(o)], o[(i+2)%len(o)])
+		if s == 0 {
+			continue
+		}
+		if turn != 0 && s != turn {
+			return false
+		}
+		turn = s
+	}
+	return turn != 0 && o.IsSimple()
+}
+
+// IsSimple returns true if the boundary of the polygon does not touch or cross itself
+//
+// Edges are swept in order of their leftmost point so that only edges whose
+// x ranges overlap are compared. All decisions are exact.
+//
+// Parameters:
+//
+//	o Polygon - The polygon
+//
+// Returns:
+//
+//	bool - true if only consecutive edges meet, and only at their shared vertex
+func (o Polygon) IsSimple() bool {
+	n := len(o)
+	if n < 3 {
+		return false
+	}
+	for i := range o {
+		if o[i].Equal(o[(i+1)%n]) {
+			return false
+		}
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	minX := func(i int) float64 {
+		e := o.Edge(i)
+		if e.B.X < e.A.X {
+			return e.B.X
+		}
+		return e.A.X
+	}
+	maxX := func(i int) float64 {
+		e := o.Edge(i)
+		if e.B.X > e.A.X {
+			return e.B.X
+		}
+		return e.A.X
+	}
+	sort.Slice(order, func(a, b int) bool { return minX(order[a]) < minX(order[b]) })
+	for a, i := range order {
+		ei := o.Edge(i)
+		for _, j := range order[a+1:] {
+			if minX(j) > maxX(i) {
+				break
+			}
+			if !o.edgesValid(i, j, ei, o.Edge(j)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// edgesValid returns true if edges i and j of a simple polygon may meet the way they do
+func (o Polygon) edgesValid(i, j int, ei, ej Segment) bool {
+	n := len(o)
+	switch {
+	case (i+1)%n == j:
+		// ej starts where ei ends, they may only share that vertex
+		return !(Orient(ei.A, ei.B, ej.B) == 0 && (ei.Contains(ej.B) || ej.Contains(ei.A)))
+	case (j+1)%n == i:
+		return !(Orient(ej.A, ej.B, ei.B) == 0 && (ej.Contains(ei.B) || ei.Contains(ej.A)))
+	}
+	return !ei.Intersects(ej)
+}
@@ -0,0 +1,172 @@
+package r2
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOrient(t *testing.T) {
+	tests := []struct {
+		a, b, c Point
+		want    int
+	}{
+		{Point{0, 0}, Point{1, 0}, Point{0, 1}, 1},
+		{Point{0, 0}, Point{0, 1}, Point{1, 0}, -1},
+		{Point{0, 0}, Point{1, 1}, Point{2, 2}, 0},
+		// nearly collinear points where the naive determinant rounds to the wrong sign
+		{Point{0.5, 0.5}, Point{12, 12}, Point{24, 24}, 0},
+		{Point{0.5, math.Nextafter(0.5, 1)}, Point{12, 12}, Point{24, 24}, 1},
+		{Point{1e-300, 0}, Point{0, 0}, Point{0, 1e-300}, -1},
+		{Point{1e300, 0}, Point{0, 0}, Point{0, 1e300}, -1},
+	}
+	for _, tt := range tests {
+		if got := Orient(tt.a, tt.b, tt.c); got != tt.want {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, tt.want)
+		}
+	}
+	// every rotation of a collinear triple is collinear, and swapping flips the sign
+	a, b, c := Point{0.1, 0.1}, Point{0.3, 0.3}, Point{0.7, 0.7}
+	for _, s := range []int{Orient(a, b, c), Orient(b, c, a), Orient(c, a, b)} {
+		if s != Orient(a, b, c) {
+			t.Errorf("error:\ngot=%v\nwant=%v", s, Orient(a, b, c))
+		}
+	}
+	if Orient(a, b, c) != -Orient(b, a, c) {
+		t.Errorf("error:\ngot=%v\nwant=%v", Orient(a, b, c), -Orient(b, a, c))
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	square := Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	lshape := Polygon{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+	tests := []struct {
+		p       Polygon
+		area    float64
+		winding int
+		C       Point
+	}{
+		{square, 4, 1, Point{1, 1}},
+		{square.Reverse(), -4, -1, Point{1, 1}},
+		{lshape, 3, 1, Point{5.0 / 6, 5.0 / 6}},
+		{Polygon{{1e8, 1e8}, {1e8 + 1, 1e8}, {1e8, 1e8 + 1}}, 0.5, 1, Point{1e8 + 1.0/3, 1e8 + 1.0/3}},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Area(); math.Abs(got-tt.area) > 1e-9 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, tt.area)
+		}
+		if got := tt.p.Winding(); got != tt.winding {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, tt.winding)
+		}
+		if got, err := tt.p.Centroid(); err != nil || got.Dist(tt.C) > 1e-6 {
+			t.Errorf("error:\ngot=%v (%v)\nwant=%v", got, err, tt.C)
+		}
+	}
+	if _, err := (Polygon{{0, 0}, {1, 1}, {2, 2}}).Centroid(); err == nil {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, "ErrDegenerate")
+	}
+}
+
+func TestPolygonLocate(t *testing.T) {
+	lshape := Polygon{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}
+	tests := []struct {
+		P    Point
+		want Location
+	}{
+		{Point{0.5, 0.5}, Inside},
+		{Point{0.5, 1.5}, Inside},
+		{Point{1.5, 1.5}, Outside},
+		{Point{3, 0.5}, Outside},
+		{Point{1, 1.5}, Boundary},
+		{Point{2, 0}, Boundary},
+		{Point{1.5, 1}, Boundary},
+		// on the horizontal line through a reflex vertex
+		{Point{-1, 1}, Outside},
+		{Point{0.5, 1}, Inside},
+	}
+	for _, tt := range tests {
+		for _, p := range []Polygon{lshape, lshape.Reverse()} {
+			if got := p.Locate(tt.P); got != tt.want {
+				t.Errorf("error: %v\ngot=%v\nwant=%v", tt.P, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestPolygonSimple(t *testing.T) {
+	tests := []struct {
+		p              Polygon
+		simple, convex bool
+	}{
+		{Polygon{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, true, true},
+		{Polygon{{0, 0}, {1, 0}, {2, 0}, {1, 1}}, true, true},
+		{Polygon{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}}, true, false},
+		// bow tie
+		{Polygon{{0, 0}, {1, 1}, {1, 0}, {0, 1}}, false, false},
+		// a vertex touching a non-adjacent edge
+		{Polygon{{0, 0}, {2, 0}, {2, 2}, {1, 0}, {0, 2}}, false, false},
+		// a spike that doubles back on itself
+		{Polygon{{0, 0}, {2, 0}, {1, 0}, {1, 1}}, false, false},
+		{Polygon{{0, 0}, {1, 0}, {1, 0}, {0, 1}}, false, false},
+		{Polygon{{0, 0}, {1, 1}}, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.IsSimple(); got != tt.simple {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", tt.p, got, tt.simple)
+		}
+		if got := tt.p.IsConvex(); got != tt.convex {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", tt.p, got, tt.convex)
+		}
+	}
+}
+
+func TestSegmentIntersect(t *testing.T) {
+	tests := []struct {
+		s, q   Segment
+		P1, P2 Point
+		hit    bool
+	}{
+		{Segment{Point{0, 0}, Point{2, 2}}, Segment{Point{0, 2}, Point{2, 0}}, Point{1, 1}, Point{1, 1}, true},
+		{Segment{Point{0, 0}, Point{2, 0}}, Segment{Point{1, 0}, Point{1, 1}}, Point{1, 0}, Point{1, 0}, true},
+		{Segment{Point{0, 0}, Point{1, 0}}, Segment{Point{1, 0}, Point{2, 0}}, Point{1, 0}, Point{1, 0}, true},
+		{Segment{Point{0, 0}, Point{3, 0}}, Segment{Point{4, 0}, Point{1, 0}}, Point{1, 0}, Point{3, 0}, true},
+		{Segment{Point{0, 0}, Point{1, 0}}, Segment{Point{2, 0}, Point{3, 0}}, Point{}, Point{}, false},
+		{Segment{Point{0, 0}, Point{1, 0}}, Segment{Point{0, 1}, Point{1, 1}}, Point{}, Point{}, false},
+		{Segment{Point{0, 0}, Point{2, 2}}, Segment{Point{3, 0}, Point{2, 1.5}}, Point{}, Point{}, false},
+	}
+	for _, tt := range tests {
+		P1, P2, err := tt.s.Intersect(tt.q)
+		if (err == nil) != tt.hit || tt.hit && (P1.Dist(tt.P1) > 1e-12 || P2.Dist(tt.P2) > 1e-12) {
+			t.Errorf("error: %v %v\ngot=%v %v (%v)\nwant=%v %v", tt.s, tt.q, P1, P2, err, tt.P1, tt.P2)
+		}
+		if got := tt.q.Intersects(tt.s); got != tt.hit {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, tt.hit)
+		}
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	a, b, c := Point{0, 0}, Point{1, 0}, Point{0, 1}
+	tests := []struct {
+		d    Point
+		want int
+	}{
+		{Point{0.5, 0.5}, 1},
+		{Point{1, 1}, 0},
+		{Point{2, 2}, -1},
+		{Point{1, math.Nextafter(1, 2)}, -1},
+		{Point{1, math.Nextafter(1, 0)}, 1},
+	}
+	for _, tt := range tests {
+		if got := InCircle(a, b, c, tt.d); got != tt.want {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", tt.d, got, tt.want)
+		}
+		if got := InCircle(a, c, b, tt.d); got != -tt.want {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", tt.d, got, -tt.want)
+		}
+		// the same configuration scaled exactly into the range where products underflow
+		scale := func(P Point) Point { return Point{math.Ldexp(P.X, -700), math.Ldexp(P.Y, -700)} }
+		if got := InCircle(scale(a), scale(b), scale(c), scale(tt.d)); got != tt.want {
+			t.Errorf("error: %v\ngot=%v\nwant=%v", scale(tt.d), got, tt.want)
+		}
+	}
+}
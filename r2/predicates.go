@@ -0,0 +1,69 @@
+package r2
+
+import "github.com/add1609/lin/robust"
+
+// Orient returns the orientation of the triangle (a, b, c)
+//
+// The decision is exact for every finite input, see robust.Orient2D.
+//
+// Parameters:
+//
+//	a Point - The first point
+//	b Point - The second point
+//	c Point - The third point
+//
+// Returns:
+//
+//	s int - 1 if c lies left of the directed line ab (counterclockwise),
+//	-1 if it lies to the right (clockwise), 0 if the points are collinear
+func Orient(a, b, c Point) (s int) {
+	s = sign(robust.Orient2D([2]float64{a.X, a.Y}, [2]float64{b.X, b.Y}, [2]float64{c.X, c.Y}))
+	return
+}
+
+// Area2 returns twice the signed area of the triangle (a, b, c)
+//
+// Parameters:
+//
+//	a Point - The first point
+//	b Point - The second point
+//	c Point - The third point
+//
+// Returns:
+//
+//	area float64 - Positive if (a, b, c) is counterclockwise
+func Area2(a, b, c Point) (area float64) {
+	u, v := b.Sub(a), c.Sub(a)
+	area = u.Cross(v)
+	return
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// InCircle returns where d lies relative to the circle through a, b and c
+//
+// The decision is exact for every finite input, see robust.InCircle.
+//
+// Parameters:
+//
+//	a Point - The first point on the circle
+//	b Point - The second point on the circle
+//	c Point - The third point on the circle, (a, b, c) counterclockwise
+//	d Point - The query point
+//
+// Returns:
+//
+//	s int - 1 if d lies inside the circle, -1 if outside, 0 if on it;
+//	the signs are reversed if (a, b, c) is clockwise
+func InCircle(a, b, c, d Point) (s int) {
+	s = sign(robust.InCircle([2]float64{a.X, a.Y}, [2]float64{b.X, b.Y}, [2]float64{c.X, c.Y}, [2]float64{d.X, d.Y}))
+	return
+}
@@ -0,0 +1,94 @@
+package r2
+
+import "fmt"
+
+// Region is an area of the plane bounded by rings that do not cross each other
+//
+// Outer boundaries run counterclockwise and holes clockwise, so that the
+// interior always lies to the left of an edge. A point is inside if the
+// winding numbers of all rings around it sum to a positive value.
+type Region []Polygon
+
+func (o Region) String() (str string) {
+	str += fmt.Sprintf("%v", []Polygon(o))
+	return
+}
+
+// MakeRegion returns the Region bounded by an outer polygon and optional holes
+//
+// Parameters:
+//
+//	outer Polygon - The outer boundary, in either winding
+//	holes ...Polygon - Holes inside outer, in either winding
+//
+// Returns:
+//
+//	region Region - The region with outer oriented counterclockwise and the holes clockwise
+func MakeRegion(outer Polygon, holes ...Polygon) (region Region) {
+	if outer.Winding() < 0 {
+		outer = outer.Reverse()
+	}
+	region = Region{outer}
+	for _, h := range holes {
+		if h.Winding() > 0 {
+			h = h.Reverse()
+		}
+		region = append(region, h)
+	}
+	return
+}
+
+// Area returns the area of the region
+//
+// Parameters:
+//
+//	o Region - The region
+//
+// Returns:
+//
+//	area float64 - The sum of the signed ring areas, holes count negatively
+func (o Region) Area() (area float64) {
+	for _, ring := range o {
+		area += ring.Area()
+	}
+	return
+}
+
+// Locate returns whether P lies inside, outside or on the boundary of the region
+//
+// Parameters:
+//
+//	P Point - The query point
+//
+// Returns:
+//
+//	loc Location - Inside, Outside or Boundary
+func (o Region) Locate(P Point) (loc Location) {
+	total := 0
+	for _, ring := range o {
+		wn, onBoundary := ring.winding(P)
+		if onBoundary {
+			loc = Boundary
+			return
+		}
+		total += wn
+	}
+	loc = Outside
+	if total > 0 {
+		loc = Inside
+	}
+	return
+}
+
+// Contains returns true if P lies inside or on the boundary of the region
+//
+// Parameters:
+//
+//	P Point - The query point
+//
+// Returns:
+//
+//	bool - true if Locate(P) is not Outside
+func (o Region) Contains(P Point) bool {
+	return o.Locate(P) != Outside
+}
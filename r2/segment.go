@@ -0,0 +1,169 @@
+package r2
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+)
+
+// Segment is the closed line segment between A and B
+type Segment struct {
+	A, B Point
+}
+
+func (o Segment) String() (str string) {
+	str += fmt.Sprintf("[%v, %v]", o.A, o.B)
+	return
+}
+
+// Equal returns true if o and q have the same endpoints in the same order
+//
+// Parameters:
+//
+//	q Segment - segment to compare to o
+//
+// Returns:
+//
+//	bool - true if o and q are equal
+func (o Segment) Equal(q Segment) bool {
+	return o.A.Equal(q.A) && o.B.Equal(q.B)
+}
+
+// Length returns the length of the segment
+//
+// Parameters:
+//
+//	o Segment - The segment
+//
+// Returns:
+//
+//	l float64 - |B - A|
+func (o Segment) Length() (l float64) {
+	l = o.A.Dist(o.B)
+	return
+}
+
+// At returns the point at parameter t
+//
+// Parameters:
+//
+//	t float64 - The parameter, 0 at A and 1 at B
+//
+// Returns:
+//
+//	P Point - A + t * (B - A)
+func (o Segment) At(t float64) (P Point) {
+	d := o.B.Sub(o.A)
+	P = o.A.Add(d.Scale(t))
+	return
+}
+
+// Contains returns true if P lies on the segment, decided exactly
+//
+// Parameters:
+//
+//	P Point - The query point
+//
+// Returns:
+//
+//	bool - true if P is collinear with A and B and lies between them
+func (o Segment) Contains(P Point) bool {
+	return Orient(o.A, o.B, P) == 0 && o.inBox(P)
+}
+
+// inBox returns true if P lies in the bounding box of the segment
+func (o Segment) inBox(P Point) bool {
+	return math.Min(o.A.X, o.B.X) <= P.X && P.X <= math.Max(o.A.X, o.B.X) &&
+		math.Min(o.A.Y, o.B.Y) <= P.Y && P.Y <= math.Max(o.A.Y, o.B.Y)
+}
+
+// Intersects returns true if o and q share at least one point, decided exactly
+//
+// Parameters:
+//
+//	q Segment - The second segment
+//
+// Returns:
+//
+//	bool - true if the segments cross, touch or overlap
+func (o Segment) Intersects(q Segment) bool {
+	d1, d2 := Orient(q.A, q.B, o.A), Orient(q.A, q.B, o.B)
+	d3, d4 := Orient(o.A, o.B, q.A), Orient(o.A, o.B, q.B)
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	return d1 == 0 && q.inBox(o.A) || d2 == 0 && q.inBox(o.B) ||
+		d3 == 0 && o.inBox(q.A) || d4 == 0 && o.inBox(q.B)
+}
+
+// Intersect returns the points that o and q have in common
+//
+// Whether the segments meet and whether they are collinear is decided
+// exactly; only the coordinates of a crossing point are rounded.
+//
+// Parameters:
+//
+//	q Segment - The second segment
+//
+// Returns:
+//
+//	P1 Point - The intersection point, or the first end of the shared part if the segments overlap
+//	P2 Point - Equal to P1 for a single intersection point, the second end of the shared part otherwise
+//	err error - ErrNoIntersection if the segments are disjoint
+func (o Segment) Intersect(q Segment) (P1, P2 Point, err error) {
+	if !o.Intersects(q) {
+		err = errors.ErrNoIntersection
+		return
+	}
+	if Orient(o.A, o.B, q.A) == 0 && Orient(o.A, o.B, q.B) == 0 {
+		P1, P2 = o.overlap(q)
+		return
+	}
+	// the endpoints cases are exact, a proper crossing is computed from the parameter on o
+	for _, P := range []Point{q.A, q.B} {
+		if o.Contains(P) {
+			P1, P2 = P, P
+			return
+		}
+	}
+	for _, P := range []Point{o.A, o.B} {
+		if q.Contains(P) {
+			P1, P2 = P, P
+			return
+		}
+	}
+	d, e := o.B.Sub(o.A), q.B.Sub(q.A)
+	w := q.A.Sub(o.A)
+	t := w.Cross(e) / d.Cross(e)
+	P1 = o.At(t)
+	P2 = P1
+	return
+}
+
+// overlap returns the shared part of two collinear, intersecting segments, ordered along o
+func (o Segment) overlap(q Segment) (P1, P2 Point) {
+	d := o.B.Sub(o.A)
+	if d.X == 0 && d.Y == 0 {
+		return o.A, o.A
+	}
+	param := func(P Point) float64 {
+		w := P.Sub(o.A)
+		return w.Dot(d)
+	}
+	lo, hi := o.A, o.B
+	if q.A.Equal(q.B) {
+		return q.A, q.A
+	}
+	qa, qb := q.A, q.B
+	if param(qa) > param(qb) {
+		qa, qb = qb, qa
+	}
+	if param(qa) > param(lo) {
+		lo = qa
+	}
+	if param(qb) < param(hi) {
+		hi = qb
+	}
+	return lo, hi
+}
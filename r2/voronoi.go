@@ -0,0 +1,127 @@
+package r2
+
+import "math"
+
+// Voronoi is the Voronoi diagram of a point set, the dual of its Delaunay triangulation
+type Voronoi struct {
+	Sites    []Point
+	Vertices []Point       // Vertices[t] is the circumcenter of triangle t of the triangulation
+	Cells    []VoronoiCell // Cells[i] is the region of the plane closest to Sites[i]
+}
+
+// VoronoiCell is the boundary of the region closest to one site
+//
+// An unbounded cell of a site on the convex hull is closed by two rays: the
+// boundary comes in from infinity along -First to Vertices[0] and leaves
+// from the last vertex along Last.
+type VoronoiCell struct {
+	Vertices    []int // indices into Voronoi.Vertices, counterclockwise
+	Bounded     bool
+	First, Last Point // unit directions of the rays of an unbounded cell
+}
+
+// Voronoi returns the Voronoi diagram dual to the triangulation
+//
+// For a triangulation with constrained edges the result is the dual of the
+// constrained triangulation, which is not a Voronoi diagram where constraints
+// removed Delaunay edges.
+//
+// Parameters:
+//
+//	o *Triangulation - The triangulation
+//
+// Returns:
+//
+//	vor Voronoi - The diagram, cells of duplicate points are empty
+func (o *Triangulation) Voronoi() (vor Voronoi) {
+	vor.Sites = o.Points
+	vor.Vertices = make([]Point, len(o.Triangles))
+	for t, v := range o.Triangles {
+		vor.Vertices[t] = circumcenter(o.Points[v[0]], o.Points[v[1]], o.Points[v[2]])
+	}
+	vor.Cells = make([]VoronoiCell, len(o.Points))
+	for i := range o.Points {
+		ts := o.TrianglesAround(i)
+		if len(ts) == 0 {
+			continue
+		}
+		cell := &vor.Cells[i]
+		cell.Vertices = ts
+		first, last := o.Triangles[ts[0]], o.Triangles[ts[len(ts)-1]]
+		k, l := 0, 0
+		for m := 0; m < 3; m++ {
+			if first[m] == i {
+				k = m
+			}
+			if last[m] == i {
+				l = m
+			}
+		}
+		// the cell is closed if the walk around i came back to its start
+		if o.Neighbors[ts[0]][(k+2)%3] >= 0 {
+			cell.Bounded = true
+			continue
+		}
+		P := o.Points[i]
+		s, p := o.Points[first[(k+1)%3]], o.Points[last[(l+2)%3]]
+		cell.First = outwardNormal(P, s)
+		cell.Last = outwardNormal(p, P)
+	}
+	return
+}
+
+// outwardNormal returns the unit normal to the right of the hull edge a→b
+func outwardNormal(a, b Point) Point {
+	d := b.Sub(a)
+	n := Point{d.Y, -d.X}
+	return n.Scale(1 / n.Norm())
+}
+
+// circumcenter returns the center of the circle through a, b and c
+func circumcenter(a, b, c Point) Point {
+	u, v := b.Sub(a), c.Sub(a)
+	d := 2 * u.Cross(v)
+	uu, vv := u.Dot(u), v.Dot(v)
+	return Point{a.X + (v.Y*uu-u.Y*vv)/d, a.Y + (u.X*vv-v.X*uu)/d}
+}
+
+// Cell returns the cell of a site clipped to a convex polygon
+//
+// Parameters:
+//
+//	i int - The index of the site
+//	bounds Polygon - A convex polygon that bounds unbounded cells
+//
+// Returns:
+//
+//	cell Polygon - The counterclockwise cell polygon, empty if it lies outside bounds
+func (o *Voronoi) Cell(i int, bounds Polygon) (cell Polygon) {
+	c := o.Cells[i]
+	if len(c.Vertices) == 0 {
+		return
+	}
+	for _, t := range c.Vertices {
+		cell = append(cell, o.Vertices[t])
+	}
+	if !c.Bounded {
+		// extend the rays far enough that the chord between them lies outside bounds
+		lo, hi := o.Sites[i], o.Sites[i]
+		for _, P := range append(append(Polygon{}, cell...), bounds...) {
+			lo = Point{math.Min(lo.X, P.X), math.Min(lo.Y, P.Y)}
+			hi = Point{math.Max(hi.X, P.X), math.Max(hi.Y, P.Y)}
+		}
+		diag := hi.Sub(lo)
+		L := 4 * diag.Norm()
+		first, last := cell[0], cell[len(cell)-1]
+		mid := c.First.Add(c.Last)
+		if n := mid.Norm(); n > 0 {
+			mid = mid.Scale(L / n)
+		}
+		far := Polygon{first.Add(c.First.Scale(L))}
+		far = append(far, cell...)
+		far = append(far, last.Add(c.Last.Scale(L)), o.Sites[i].Add(mid))
+		cell = far
+	}
+	cell = cell.ClipConvex(bounds)
+	return
+}
@@ -0,0 +1,485 @@
+package rn
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/scalar"
+)
+
+// Band implements the LAPACK band storage of a square matrix with KL sub-diagonals and
+// KU super-diagonals
+//
+//	Example (N = 4, KL = 1, KU = 1):
+//	           _            _                 _            _
+//	          |  a  d  .  .  |               |  *  d  e  f  |
+//	      A = |  g  b  e  .  |   is stored as |  a  b  c  h  |
+//	          |  .  i  c  f  |               |_ g  i  j  * _|(KL+KU+1 x N)
+//	          |_ .  .  j  h _|(N x N)
+//
+//	Data[KU+i-j + j*(KL+KU+1)] = A[i][j] for j-KU <= i <= j+KL
+type Band struct {
+	N, KL, KU int       // N => size, KL, KU => number of sub- and super-diagonals
+	Data      []float64 // column-major band array, entries marked * are unused
+}
+
+func (o Band) String() (str string) {
+	for i := 0; i < o.N; i++ {
+		if i > 0 {
+			str += "\n"
+		}
+		for j := 0; j < o.N; j++ {
+			str += fmt.Sprintf("%9g ", scalar.RoundTo(o.Get(i, j), 3))
+		}
+	}
+	return
+}
+
+// MakeBand returns a new n x n band matrix with all elements set to zero
+//
+// Parameters:
+//
+//	n int - number of rows and columns
+//	kl int - number of sub-diagonals
+//	ku int - number of super-diagonals
+//
+// Returns:
+//
+//	band Band - a new band matrix
+func MakeBand(n, kl, ku int) (band Band) {
+	if n < 1 || kl < 0 || ku < 0 {
+		panic(errors.ErrNegativeDimension)
+	}
+	band = Band{N: n, KL: kl, KU: ku, Data: make([]float64, (kl+ku+1)*n)}
+	return
+}
+
+// MakeTridiagonal returns the tridiagonal matrix with the given diagonals
+//
+// Parameters:
+//
+//	a []float64 - the n - 1 sub-diagonal elements A[i+1][i]
+//	b []float64 - the n diagonal elements A[i][i]
+//	c []float64 - the n - 1 super-diagonal elements A[i][i+1]
+//
+// Returns:
+//
+//	band Band - the n x n band matrix with KL = KU = 1
+func MakeTridiagonal(a, b, c []float64) (band Band) {
+	n := len(b)
+	if len(a) != n-1 || len(c) != n-1 {
+		panic(errors.ErrSliceLengthMismatch)
+	}
+	band = MakeBand(n, 1, 1)
+	for i := 0; i < n; i++ {
+		band.Data[1+3*i] = b[i]
+		if i < n-1 {
+			band.Data[2+3*i] = a[i]
+			band.Data[3*(i+1)] = c[i]
+		}
+	}
+	return
+}
+
+// MakeBandFromMat returns the band storage of a square matrix
+//
+// Parameters:
+//
+//	A Mat - a square matrix
+//
+// Returns:
+//
+//	band Band - the band matrix with the smallest KL and KU that hold every non-zero element of A
+func MakeBandFromMat(A Mat) (band Band) {
+	if A.M != A.N {
+		panic(errors.ErrSquare)
+	}
+	var kl, ku int
+	for j := 0; j < A.N; j++ {
+		for i := 0; i < A.M; i++ {
+			if A.Data[i+j*A.M] == 0 {
+				continue
+			}
+			if i-j > kl {
+				kl = i - j
+			}
+			if j-i > ku {
+				ku = j - i
+			}
+		}
+	}
+	band = MakeBand(A.N, kl, ku)
+	for j := 0; j < A.N; j++ {
+		for i := imax(0, j-ku); i <= imin(A.N-1, j+kl); i++ {
+			band.Data[ku+i-j+j*(kl+ku+1)] = A.Data[i+j*A.M]
+		}
+	}
+	return
+}
+
+// imin returns the smaller of a and b
+func imin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// imax returns the larger of a and b
+func imax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// ToMat returns the dense copy of a band matrix
+//
+// Parameters:
+//
+//	o *Band - band matrix to convert
+//
+// Returns:
+//
+//	mat Mat - the n x n matrix with the same elements
+func (o *Band) ToMat() (mat Mat) {
+	mat = MakeMat(o.N, o.N, 0)
+	for j := 0; j < o.N; j++ {
+		for i := imax(0, j-o.KU); i <= imin(o.N-1, j+o.KL); i++ {
+			mat.Data[i+j*o.N] = o.Data[o.KU+i-j+j*(o.KL+o.KU+1)]
+		}
+	}
+	return
+}
+
+// Get returns the value at A[i][j]
+//
+// Parameters:
+//
+//	o *Band - band matrix to get value at A[i][j] of
+//	i int - row index
+//	j int - column index
+//
+// Returns:
+//
+//	val float64 - the value at A[i][j], zero outside the band
+func (o *Band) Get(i, j int) (val float64) {
+	if o.N <= i {
+		panic(errors.ErrRowAccess)
+	}
+	if o.N <= j {
+		panic(errors.ErrColAccess)
+	}
+	if i-j > o.KL || j-i > o.KU {
+		return 0
+	}
+	return o.Data[o.KU+i-j+j*(o.KL+o.KU+1)]
+}
+
+// Set sets the value at A[i][j]
+//
+// Parameters:
+//
+//	o *Band - band matrix to set value at A[i][j] of
+//	i int - row index
+//	j int - column index, with -KL <= i - j <= KU
+//	val float64 - the value to set at A[i][j]
+//
+// Returns:
+//
+//	none
+func (o *Band) Set(i, j int, val float64) {
+	if o.N <= i {
+		panic(errors.ErrRowAccess)
+	}
+	if o.N <= j {
+		panic(errors.ErrColAccess)
+	}
+	if i-j > o.KL || j-i > o.KU {
+		panic(errors.ErrIndexOutOfRange)
+	}
+	o.Data[o.KU+i-j+j*(o.KL+o.KU+1)] = val
+}
+
+// MulVec returns the product of a band matrix and a vector
+//
+// Parameters:
+//
+//	o *Band - the band matrix
+//	v Vec - the vector
+//
+// Returns:
+//
+//	u Vec - the matrix-vector product o * v in O(n * (KL + KU)) operations
+func (o *Band) MulVec(v Vec) (u Vec) {
+	if o.N != v.N {
+		panic(errors.ErrShape)
+	}
+	u = MakeVec(o.N, 0)
+	ld := o.KL + o.KU + 1
+	for j := 0; j < o.N; j++ {
+		vJ := v.X[j]
+		if vJ == 0 {
+			continue
+		}
+		for i := imax(0, j-o.KU); i <= imin(o.N-1, j+o.KL); i++ {
+			u.X[i] += o.Data[o.KU+i-j+j*ld] * vJ
+		}
+	}
+	return
+}
+
+// Thomas returns the solution of a tridiagonal system using the Thomas algorithm
+//
+// The elimination does not pivot, so it is stable for diagonally dominant
+// or symmetric positive definite matrices. Use LU otherwise.
+//
+// Parameters:
+//
+//	o *Band - a band matrix with KL = KU = 1
+//	d Vec - the right-hand side
+//
+// Returns:
+//
+//	x Vec - the solution of o * x = d in O(n) operations
+//	err error - ErrSingular if a pivot is zero
+func (o *Band) Thomas(d Vec) (x Vec, err error) {
+	if o.KL != 1 || o.KU != 1 {
+		panic(errors.ErrOrder)
+	}
+	if o.N != d.N {
+		panic(errors.ErrShape)
+	}
+	n := o.N
+	cp := make([]float64, n)
+	x = MakeVec(n, 0)
+	var prev float64
+	for i := 0; i < n; i++ {
+		a, b := 0.0, o.Data[1+3*i]
+		if i > 0 {
+			a = o.Data[2+3*(i-1)]
+		}
+		m := b - a*prev
+		if m == 0 {
+			err = errors.ErrSingular
+			return
+		}
+		if i < n-1 {
+			cp[i] = o.Data[3*(i+1)] / m
+		}
+		x.X[i] = d.X[i]
+		if i > 0 {
+			x.X[i] -= a * x.X[i-1]
+		}
+		x.X[i] /= m
+		prev = cp[i]
+	}
+	for i := n - 2; i >= 0; i-- {
+		x.X[i] -= cp[i] * x.X[i+1]
+	}
+	return
+}
+
+// BandLU is the LU factorization with partial pivoting of a band matrix
+//
+// U has KL + KU super-diagonals because of the row interchanges and is
+// stored in rows 0 to KL + KU of Data, the multipliers of L are stored
+// below it as in LAPACK's dgbtrf.
+type BandLU struct {
+	N, KL, KU int
+	Data      []float64 // column-major array with 2*KL+KU+1 rows
+	Piv       []int     // row k was interchanged with row Piv[k]
+}
+
+// at returns the index of A[i][j] in the factorization storage
+func (o *BandLU) at(i, j int) int {
+	return o.KL + o.KU + i - j + j*(2*o.KL+o.KU+1)
+}
+
+// LU returns the LU factorization with partial pivoting of a band matrix
+//
+// Parameters:
+//
+//	o *Band - the band matrix
+//
+// Returns:
+//
+//	lu BandLU - the factorization P * o = L * U in O(n * KL * (KL + KU)) operations
+//	err error - ErrSingular if o is singular
+func (o *Band) LU() (lu BandLU, err error) {
+	n, kl, ku := o.N, o.KL, o.KU
+	kv := kl + ku
+	lu = BandLU{N: n, KL: kl, KU: ku, Data: make([]float64, (2*kl+ku+1)*n), Piv: make([]int, n)}
+	for j := 0; j < n; j++ {
+		for i := imax(0, j-ku); i <= imin(n-1, j+kl); i++ {
+			lu.Data[lu.at(i, j)] = o.Data[ku+i-j+j*(kl+ku+1)]
+		}
+	}
+	for k := 0; k < n; k++ {
+		last, end := imin(n-1, k+kl), imin(n-1, k+kv)
+		p := k
+		for i := k + 1; i <= last; i++ {
+			if math.Abs(lu.Data[lu.at(i, k)]) > math.Abs(lu.Data[lu.at(p, k)]) {
+				p = i
+			}
+		}
+		lu.Piv[k] = p
+		if lu.Data[lu.at(p, k)] == 0 {
+			err = errors.ErrSingular
+			return
+		}
+		if p != k {
+			for j := k; j <= end; j++ {
+				a, b := lu.at(k, j), lu.at(p, j)
+				lu.Data[a], lu.Data[b] = lu.Data[b], lu.Data[a]
+			}
+		}
+		pivot := lu.Data[lu.at(k, k)]
+		for i := k + 1; i <= last; i++ {
+			f := lu.Data[lu.at(i, k)] / pivot
+			lu.Data[lu.at(i, k)] = f
+			if f == 0 {
+				continue
+			}
+			for j := k + 1; j <= end; j++ {
+				lu.Data[lu.at(i, j)] -= f * lu.Data[lu.at(k, j)]
+			}
+		}
+	}
+	return
+}
+
+// Solve returns the solution of the factorized system
+//
+// Parameters:
+//
+//	o *BandLU - the factorization of A
+//	b Vec - the right-hand side
+//
+// Returns:
+//
+//	x Vec - the solution of A * x = b
+func (o *BandLU) Solve(b Vec) (x Vec) {
+	if o.N != b.N {
+		panic(errors.ErrShape)
+	}
+	n, kv := o.N, o.KL+o.KU
+	x = MakeVec(n, 0)
+	copy(x.X, b.X)
+	for k := 0; k < n; k++ {
+		if p := o.Piv[k]; p != k {
+			x.X[k], x.X[p] = x.X[p], x.X[k]
+		}
+		for i := k + 1; i <= imin(n-1, k+o.KL); i++ {
+			x.X[i] -= o.Data[o.at(i, k)] * x.X[k]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j <= imin(n-1, i+kv); j++ {
+			x.X[i] -= o.Data[o.at(i, j)] * x.X[j]
+		}
+		x.X[i] /= o.Data[o.at(i, i)]
+	}
+	return
+}
+
+// Det returns the determinant of the factorized matrix
+//
+// Parameters:
+//
+//	o *BandLU - the factorization of A
+//
+// Returns:
+//
+//	det float64 - the determinant of A
+func (o *BandLU) Det() (det float64) {
+	det = 1
+	for k := 0; k < o.N; k++ {
+		det *= o.Data[o.at(k, k)]
+		if o.Piv[k] != k {
+			det = -det
+		}
+	}
+	return
+}
+
+// BandCholesky is the Cholesky factor of a symmetric positive definite band matrix
+//
+// The lower triangular factor L has K sub-diagonals and is stored in lower
+// band storage, Data[i-j + j*(K+1)] = L[i][j] for j <= i <= j+K.
+type BandCholesky struct {
+	N, K int
+	Data []float64
+}
+
+// Cholesky returns the Cholesky factorization of a symmetric positive definite band matrix
+//
+// Only the lower band of o is read, the upper band is assumed to mirror it.
+//
+// Parameters:
+//
+//	o *Band - a symmetric band matrix with KL = KU
+//
+// Returns:
+//
+//	ch BandCholesky - the factor L with o = L * Lᵀ, computed in O(n * KL²) operations
+//	err error - ErrNotPosDef if o is not positive definite
+func (o *Band) Cholesky() (ch BandCholesky, err error) {
+	if o.KL != o.KU {
+		panic(errors.ErrShape)
+	}
+	n, k := o.N, o.KL
+	ld := k + 1
+	ch = BandCholesky{N: n, K: k, Data: make([]float64, ld*n)}
+	for j := 0; j < n; j++ {
+		for i := j; i <= imin(n-1, j+k); i++ {
+			s := o.Data[k+i-j+j*(2*k+1)]
+			for p := imax(0, i-k); p < j; p++ {
+				s -= ch.Data[i-p+p*ld] * ch.Data[j-p+p*ld]
+			}
+			if i == j {
+				if !(s > 0) {
+					err = errors.ErrNotPosDef
+					return
+				}
+				ch.Data[j*ld] = math.Sqrt(s)
+				continue
+			}
+			ch.Data[i-j+j*ld] = s / ch.Data[j*ld]
+		}
+	}
+	return
+}
+
+// Solve returns the solution of the factorized system
+//
+// Parameters:
+//
+//	o *BandCholesky - the factorization of A
+//	b Vec - the right-hand side
+//
+// Returns:
+//
+//	x Vec - the solution of A * x = b
+func (o *BandCholesky) Solve(b Vec) (x Vec) {
+	if o.N != b.N {
+		panic(errors.ErrShape)
+	}
+	n, ld := o.N, o.K+1
+	x = MakeVec(n, 0)
+	copy(x.X, b.X)
+	for j := 0; j < n; j++ {
+		x.X[j] /= o.Data[j*ld]
+		for i := j + 1; i <= imin(n-1, j+o.K); i++ {
+			x.X[i] -= o.Data[i-j+j*ld] * x.X[j]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j <= imin(n-1, i+o.K); j++ {
+			x.X[i] -= o.Data[j-i+i*ld] * x.X[j]
+		}
+		x.X[i] /= o.Data[i*ld]
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package rn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/add1609/lin/errors"
+)
+
+// pentadiagonal returns the n x n matrix with diagonal d, first off-diagonals -4 and second off-diagonals 1
+func pentadiagonal(n int, d float64) (A Mat) {
+	A = MakeMat(n, n, 0)
+	for i := 0; i < n; i++ {
+		A.Set(i, i, d)
+		for k, v := range []float64{-4, 1} {
+			if i+k+1 < n {
+				A.Set(i, i+k+1, v)
+				A.Set(i+k+1, i, v)
+			}
+		}
+	}
+	return
+}
+
+func TestBandConversion(t *testing.T) {
+	A := Mat{M: 4, N: 4, Data: []float64{
+		1, 5, 0, 0,
+		2, 6, 9, 0,
+		0, 7, 10, 12,
+		0, 0, 0, 13}}
+	band := MakeBandFromMat(A)
+	if band.KL != 1 || band.KU != 1 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", band.KL, band.KU, 1, 1)
+	}
+	tri := MakeTridiagonal([]float64{5, 9, 12}, []float64{1, 6, 10, 13}, []float64{2, 7, 0})
+	if got, want := tri.Data, band.Data; !vecNear(Vec{N: len(got), X: got}, Vec{N: len(want), X: want}) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	if got := band.ToMat(); !vecNear(Vec{N: 16, X: got.Data}, Vec{N: 16, X: A.Data}) {
+		t.Errorf("error:\ngot=\n%v\nwant=\n%v", got, A)
+	}
+	if got := band.Get(3, 0); got != 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 0)
+	}
+	band.Set(2, 3, -1)
+	if got := band.Get(2, 3); got != -1 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, -1)
+	}
+
+	A = pentadiagonal(6, 6)
+	A.Set(5, 2, 3)
+	band = MakeBandFromMat(A)
+	if band.KL != 3 || band.KU != 2 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v, %v", band.KL, band.KU, 3, 2)
+	}
+	v := Vec{N: 6, X: []float64{1, -2, 0, 3, 0.5, -1}}
+	if got, want := band.MulVec(v), A.MulVec(v); !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+}
+
+func TestBandSolve(t *testing.T) {
+	want := Vec{N: 6, X: []float64{1, -2, 0, 3, 0.5, -1}}
+	// diagonally dominant tridiagonal, banded with a zero diagonal element and SPD pentadiagonal
+	tri := MakeTridiagonal([]float64{1, -1, 2, 1, 1}, []float64{4, 5, -4, 6, 3, 4}, []float64{2, 1, 1, -2, 1})
+	pivot := MakeBandFromMat(Mat{M: 6, N: 6, Data: []float64{
+		0, 2, 1, 0, 0, 0,
+		3, 1, 0, 4, 0, 0,
+		0, 1, 2, 1, 5, 0,
+		0, 0, 6, 0, 2, 1,
+		0, 0, 0, 2, 1, 3,
+		0, 0, 0, 0, 1, 7}})
+	spd := MakeBandFromMat(pentadiagonal(6, 7))
+
+	x, err := tri.Thomas(tri.MulVec(want))
+	if err != nil || !vecNear(x, want) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", x, err, want)
+	}
+	for _, band := range []Band{tri, pivot, spd} {
+		lu, err := band.LU()
+		if err != nil {
+			t.Errorf("error:\n%v\n", err)
+			continue
+		}
+		if got := lu.Solve(band.MulVec(want)); !vecNear(got, want) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+		A := band.ToMat()
+		if got, det := lu.Det(), A.Det(); math.Abs(got-det) > 1e-9*math.Abs(det) {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, det)
+		}
+	}
+	ch, err := spd.Cholesky()
+	if err != nil {
+		t.Errorf("error:\n%v\n", err)
+	}
+	if got := ch.Solve(spd.MulVec(want)); !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+
+	// the first pivot is zero without interchanges
+	swap := MakeTridiagonal([]float64{1}, []float64{0, 1}, []float64{1})
+	if _, err := swap.Thomas(Vec{N: 2, X: []float64{1, 2}}); err != errors.ErrSingular {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrSingular)
+	}
+	lu, err := swap.LU()
+	if got, want := lu.Solve(Vec{N: 2, X: []float64{1, 2}}), (Vec{N: 2, X: []float64{1, 1}}); err != nil || !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", got, err, want)
+	}
+	singular := MakeTridiagonal([]float64{2, 0}, []float64{1, 2, 0}, []float64{1, 0})
+	if _, err := singular.LU(); err != errors.ErrSingular {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrSingular)
+	}
+	if _, err := swap.Cholesky(); err != errors.ErrNotPosDef {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrNotPosDef)
+	}
+	indefinite := MakeBandFromMat(pentadiagonal(6, 5))
+	if _, err := indefinite.Cholesky(); err != errors.ErrNotPosDef {
+		t.Errorf("error:\ngot=%v\nwant=%v", err, errors.ErrNotPosDef)
+	}
+}
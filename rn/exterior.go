@@ -0,0 +1,318 @@
+package rn
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/add1609/lin/errors"
+)
+
+// KVec is a k-vector of Rⁿ, a linear combination of the basis blades e_I = e_i1 ∧ ... ∧ e_ik
+// with i1 < ... < ik
+//
+// The coefficients are stored in lexicographic order of the index sets I,
+// so a 1-vector has the coordinates of a Vec, the bivector e_0 ∧ e_1 comes
+// before e_0 ∧ e_2, and an n-vector has the single coefficient of
+// e_0 ∧ ... ∧ e_(n-1).
+type KVec struct {
+	N, K int
+	X    []float64
+}
+
+func (o KVec) String() (str string) {
+	str += fmt.Sprintf("%d-vector %v", o.K, Vec{N: len(o.X), X: o.X})
+	return
+}
+
+// MakeKVec returns the zero k-vector of Rⁿ
+//
+// Parameters:
+//
+//	n int - dimension of the space
+//	k int - grade, between 0 and n
+//
+// Returns:
+//
+//	kv KVec - k-vector with binomial(n, k) coefficients set to 0
+func MakeKVec(n, k int) (kv KVec) {
+	if n < 1 || k < 0 {
+		panic(errors.ErrNegativeDimension)
+	}
+	if k > n {
+		panic(errors.ErrOrder)
+	}
+	kv = KVec{N: n, K: k, X: make([]float64, len(blades(n, k)))}
+	return
+}
+
+// blades returns the index sets of the basis k-blades of Rⁿ as bit masks in lexicographic order
+func blades(n, k int) (masks []uint64) {
+	if n > 64 {
+		panic(errors.ErrOrder)
+	}
+	var rec func(start int, mask uint64, left int)
+	rec = func(start int, mask uint64, left int) {
+		if left == 0 {
+			masks = append(masks, mask)
+			return
+		}
+		for i := start; i <= n-left; i++ {
+			rec(i+1, mask|1<<uint(i), left-1)
+		}
+	}
+	rec(0, 0, k)
+	return
+}
+
+// bladeIndex returns the position of every basis k-blade of Rⁿ in KVec.X
+func bladeIndex(n, k int) (index map[uint64]int) {
+	index = map[uint64]int{}
+	for i, mask := range blades(n, k) {
+		index[mask] = i
+	}
+	return
+}
+
+// wedgeSign returns the sign of e_A ∧ e_B relative to e_(A ∪ B), 0 if A and B intersect
+func wedgeSign(a, b uint64) float64 {
+	if a&b != 0 {
+		return 0
+	}
+	// count the transpositions needed to move every index of b behind the larger indices of a
+	swaps := 0
+	for rest := b; rest != 0; rest &= rest - 1 {
+		i := bits.TrailingZeros64(rest)
+		swaps += bits.OnesCount64(a >> uint(i+1))
+	}
+	if swaps%2 == 1 {
+		return -1
+	}
+	return 1
+}
+
+// KVec returns o as a 1-vector
+//
+// Parameters:
+//
+//	o *Vec - vector to convert
+//
+// Returns:
+//
+//	kv KVec - 1-vector with the coordinates of o
+func (o *Vec) KVec() (kv KVec) {
+	kv = KVec{N: o.N, K: 1, X: append([]float64{}, o.X...)}
+	return
+}
+
+// Vec returns a 1-vector as a vector
+//
+// Parameters:
+//
+//	o *KVec - 1-vector to convert
+//
+// Returns:
+//
+//	v Vec - vector with the coefficients of o
+func (o *KVec) Vec() (v Vec) {
+	if o.K != 1 {
+		panic(errors.ErrOrder)
+	}
+	v = Vec{N: o.N, X: append([]float64{}, o.X...)}
+	return
+}
+
+// Get returns the coefficient of e_i1 ∧ ... ∧ e_ik
+//
+// Parameters:
+//
+//	o *KVec - k-vector to read from
+//	idx ...int - k distinct indices in any order
+//
+// Returns:
+//
+//	val float64 - the coefficient, negated for an odd permutation of the indices
+func (o *KVec) Get(idx ...int) (val float64) {
+	if len(idx) != o.K {
+		panic(errors.ErrShape)
+	}
+	var mask uint64
+	sign := 1.0
+	for _, i := range idx {
+		if i < 0 || i >= o.N {
+			panic(errors.ErrIndexOutOfRange)
+		}
+		s := wedgeSign(mask, 1<<uint(i))
+		if s == 0 {
+			return 0
+		}
+		sign *= s
+		mask |= 1 << uint(i)
+	}
+	val = sign * o.X[bladeIndex(o.N, o.K)[mask]]
+	return
+}
+
+// Wedge returns the exterior product of vectors
+//
+// Parameters:
+//
+//	vs ...Vec - k vectors of dimension n
+//
+// Returns:
+//
+//	kv KVec - the k-vector v_0 ∧ ... ∧ v_(k-1), its coefficient for I is the
+//	determinant of the rows I of the matrix with columns vs
+func Wedge(vs ...Vec) (kv KVec) {
+	if len(vs) == 0 {
+		panic(errors.ErrZeroLengthVec)
+	}
+	n, k := vs[0].N, len(vs)
+	for _, v := range vs {
+		if v.N != n {
+			panic(errors.ErrShape)
+		}
+	}
+	kv = MakeKVec(n, k)
+	minor := MakeMat(k, k, 0)
+	for b, mask := range blades(n, k) {
+		row := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<uint(i)) == 0 {
+				continue
+			}
+			for j, v := range vs {
+				minor.Data[row+j*k] = v.X[i]
+			}
+			row++
+		}
+		kv.X[b] = minor.Det()
+	}
+	return
+}
+
+// Wedge returns the exterior product of o and q
+//
+// Parameters:
+//
+//	o *Vec - first vector
+//	q Vec - second vector
+//
+// Returns:
+//
+//	kv KVec - the bivector o ∧ q
+func (o *Vec) Wedge(q Vec) (kv KVec) {
+	kv = Wedge(*o, q)
+	return
+}
+
+// Wedge returns the exterior product of a k-vector and an l-vector
+//
+// Parameters:
+//
+//	o *KVec - k-vector
+//	q KVec - l-vector of the same space
+//
+// Returns:
+//
+//	kv KVec - the (k + l)-vector o ∧ q
+func (o *KVec) Wedge(q KVec) (kv KVec) {
+	if o.N != q.N {
+		panic(errors.ErrShape)
+	}
+	kv = MakeKVec(o.N, o.K+q.K)
+	index := bladeIndex(o.N, o.K+q.K)
+	bq := blades(q.N, q.K)
+	for i, a := range blades(o.N, o.K) {
+		if o.X[i] == 0 {
+			continue
+		}
+		for j, b := range bq {
+			if s := wedgeSign(a, b); s != 0 {
+				kv.X[index[a|b]] += s * o.X[i] * q.X[j]
+			}
+		}
+	}
+	return
+}
+
+// Hodge returns the Hodge dual of a k-vector with respect to the Euclidean metric
+//
+// Parameters:
+//
+//	o *KVec - k-vector
+//
+// Returns:
+//
+//	kv KVec - the (n - k)-vector ⋆o defined by e_I ∧ ⋆e_I = e_0 ∧ ... ∧ e_(n-1)
+func (o *KVec) Hodge() (kv KVec) {
+	kv = MakeKVec(o.N, o.N-o.K)
+	index := bladeIndex(o.N, o.N-o.K)
+	all := uint64(1)<<uint(o.N) - 1
+	for i, a := range blades(o.N, o.K) {
+		c := all &^ a
+		kv.X[index[c]] = wedgeSign(a, c) * o.X[i]
+	}
+	return
+}
+
+// Dot returns the inner product of two k-vectors
+//
+// Parameters:
+//
+//	o *KVec - k-vector
+//	q KVec - k-vector of the same grade and space
+//
+// Returns:
+//
+//	d float64 - the sum of the products of the coefficients
+func (o *KVec) Dot(q KVec) (d float64) {
+	if o.N != q.N || o.K != q.K {
+		panic(errors.ErrShape)
+	}
+	for i := range o.X {
+		d += o.X[i] * q.X[i]
+	}
+	return
+}
+
+// Norm returns the norm of a k-vector
+//
+// Parameters:
+//
+//	o *KVec - k-vector
+//
+// Returns:
+//
+//	nrm float64 - the k-volume of the parallelotope spanned by vectors whose wedge is o
+func (o *KVec) Norm() (nrm float64) {
+	nrm = math.Sqrt(o.Dot(*o))
+	return
+}
+
+// CrossN returns the generalized cross product of n - 1 vectors of Rⁿ
+//
+// The result is orthogonal to all vectors, its length is the volume of the
+// parallelotope they span, and (vs..., u) is positively oriented. For n = 3
+// it is the ordinary cross product, for n = 2 it rotates its single argument
+// by 90 degrees.
+//
+// Parameters:
+//
+//	vs ...Vec - n - 1 vectors of dimension n
+//
+// Returns:
+//
+//	u Vec - the Hodge dual ⋆(v_0 ∧ ... ∧ v_(n-2))
+func CrossN(vs ...Vec) (u Vec) {
+	if len(vs) == 0 {
+		panic(errors.ErrZeroLengthVec)
+	}
+	if vs[0].N != len(vs)+1 {
+		panic(errors.ErrShape)
+	}
+	w := Wedge(vs...)
+	dual := w.Hodge()
+	u = dual.Vec()
+	return
+}
@@ -0,0 +1,117 @@
+package rn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func vecNear(a, b Vec) bool {
+	d := a.Sub(b)
+	return a.N == b.N && d.Norm() < 1e-12
+}
+
+func TestKVecWedge(t *testing.T) {
+	e := func(n, i int) Vec {
+		v := MakeVec(n, 0)
+		v.X[i] = 1
+		return v
+	}
+	a, b := Vec{3, []float64{8, 2, 5}}, Vec{3, []float64{5, 1, 3}}
+	ab := a.Wedge(b)
+	// e01, e02, e12 are the duals of e2, -e1, e0
+	if got, want := (Vec{3, ab.X}), (Vec{3, []float64{-2, -1, 1}}); !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	if got := ab.Get(1, 0); got != 2 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 2)
+	}
+	dual := ab.Hodge()
+	if got, want := dual.Vec(), a.Cross(b); !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+
+	// associativity and anticommutativity of vectors
+	x, y, z := e(4, 0), e(4, 2), e(4, 3)
+	xk, yk, zk := x.KVec(), y.KVec(), z.KVec()
+	xy, yx := xk.Wedge(yk), yk.Wedge(xk)
+	if xy.Get(0, 2) != 1 || yx.Get(0, 2) != -1 {
+		t.Errorf("error:\ngot=%v, %v\nwant=%v", xy, yx, "opposite signs")
+	}
+	left, yz := xy.Wedge(zk), yk.Wedge(zk)
+	right := xk.Wedge(yz)
+	if got := Wedge(x, y, z); got.Dot(left) != 1 || got.Dot(right) != 1 || got.Norm() != 1 {
+		t.Errorf("error:\ngot=%v\nwant=%v, %v", got, left, right)
+	}
+	if xx := xk.Wedge(xk); xx.Norm() != 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", xx, 0)
+	}
+
+	// the Hodge dual applied twice is (-1)^(k(n-k))
+	rng := rand.New(rand.NewSource(1))
+	for n := 1; n <= 5; n++ {
+		for k := 0; k <= n; k++ {
+			kv := MakeKVec(n, k)
+			for i := range kv.X {
+				kv.X[i] = rng.Float64()
+			}
+			dual := kv.Hodge()
+			twice := dual.Hodge()
+			sign := 1.0
+			if k*(n-k)%2 == 1 {
+				sign = -1
+			}
+			for i := range kv.X {
+				if twice.X[i] != sign*kv.X[i] {
+					t.Errorf("error:\nn=%v, k=%v\ngot=%v\nwant=%v", n, k, twice.X, kv.X)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestCrossN(t *testing.T) {
+	if got, want := CrossN(Vec{2, []float64{3, 1}}), (Vec{2, []float64{-1, 3}}); !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+	a, b := Vec{3, []float64{8, 2, 5}}, Vec{3, []float64{5, 1, 3}}
+	if got, want := CrossN(a, b), a.Cross(b); !vecNear(got, want) {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) Vec {
+		v := MakeVec(n, 0)
+		for i := range v.X {
+			v.X[i] = 2*rng.Float64() - 1
+		}
+		return v
+	}
+	// orthogonal to every argument, with the volume of the parallelotope as length
+	vs := []Vec{random(5), random(5), random(5), random(5)}
+	u := CrossN(vs...)
+	m := MakeMat(5, 5, 0)
+	for j, v := range vs {
+		m.SetCol(j, v)
+		if d := u.Dot(v); math.Abs(d) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", d, 0)
+		}
+	}
+	m.SetCol(4, u)
+	if det := m.Det(); math.Abs(det-u.Dot(u)) > 1e-12 {
+		t.Errorf("error:\ngot=%v\nwant=%v", det, u.Dot(u))
+	}
+
+	// the 7D cross product is orthogonal to its factors and satisfies |a × b|² = |a|²|b|² - (a · b)²
+	for k := 0; k < 10; k++ {
+		a, b := random(7), random(7)
+		c := a.Cross(b)
+		if math.Abs(c.Dot(a)) > 1e-12 || math.Abs(c.Dot(b)) > 1e-12 {
+			t.Errorf("error:\n%v is not orthogonal to %v and %v", c, a, b)
+		}
+		if got, want := c.Dot(c), a.Dot(a)*b.Dot(b)-a.Dot(b)*a.Dot(b); math.Abs(got-want) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, want)
+		}
+	}
+}
@@ -58,6 +58,23 @@ func MakeMat(m, n int, val float64) (mat Mat) {
 	return
 }
 
+// MakeIdentity returns the n x n identity matrix
+//
+// Parameters:
+//
+//	n int - number of rows and columns
+//
+// Returns:
+//
+//	mat Mat - the n x n identity matrix
+func MakeIdentity(n int) (mat Mat) {
+	mat = MakeMat(n, n, 0)
+	for i := 0; i < n; i++ {
+		mat.Data[i+i*n] = 1
+	}
+	return
+}
+
 // GetCopy returns a copy of this matrix
 //
 // Parameters:
@@ -1,6 +1,8 @@
 package rn
 
 import (
+	"math"
+
 	"github.com/add1609/lin/errors"
 	"github.com/add1609/lin/scalar"
 )
@@ -84,3 +86,358 @@ func (o *Mat) GaussSolve() (mat Mat, x Vec, err error) {
 		}
 	}
 }
+
+// Transpose returns the transpose of o
+//
+// Parameters:
+//
+//	o *Mat - matrix to transpose
+//
+// Returns:
+//
+//	mat Mat - the transpose of o
+func (o *Mat) Transpose() (mat Mat) {
+	mat = MakeMat(o.N, o.M, 0)
+	for i := 0; i < o.M; i++ {
+		for j := 0; j < o.N; j++ {
+			mat.Data[j+i*o.N] = o.Data[i+j*o.M]
+		}
+	}
+	return
+}
+
+// Mul returns the matrix product o * q
+//
+// Parameters:
+//
+//	o *Mat - left factor
+//	q Mat - right factor
+//
+// Returns:
+//
+//	mat Mat - the matrix product o * q
+func (o *Mat) Mul(q Mat) (mat Mat) {
+	if o.N != q.M {
+		panic(errors.ErrShape)
+	}
+	mat = MakeMat(o.M, q.N, 0)
+	for j := 0; j < q.N; j++ {
+		for k := 0; k < o.N; k++ {
+			qKJ := q.Data[k+j*q.M]
+			if qKJ == 0 {
+				continue
+			}
+			for i := 0; i < o.M; i++ {
+				mat.Data[i+j*o.M] += o.Data[i+k*o.M] * qKJ
+			}
+		}
+	}
+	return
+}
+
+// MulVec returns the matrix-vector product o * v
+//
+// Parameters:
+//
+//	o *Mat - the matrix
+//	v Vec - the vector
+//
+// Returns:
+//
+//	u Vec - the matrix-vector product o * v
+func (o *Mat) MulVec(v Vec) (u Vec) {
+	if o.N != v.N {
+		panic(errors.ErrShape)
+	}
+	u = MakeVec(o.M, 0)
+	for j := 0; j < o.N; j++ {
+		vJ := v.X[j]
+		if vJ == 0 {
+			continue
+		}
+		for i := 0; i < o.M; i++ {
+			u.X[i] += o.Data[i+j*o.M] * vJ
+		}
+	}
+	return
+}
+
+// Det returns the determinant of a square matrix
+//
+// Parameters:
+//
+//	o *Mat - a square matrix
+//
+// Returns:
+//
+//	det float64 - the determinant of o
+func (o *Mat) Det() (det float64) {
+	if o.M != o.N {
+		panic(errors.ErrSquare)
+	}
+	lu := o.GetCopy()
+	det = 1
+	for k := 0; k < lu.N; k++ {
+		col := lu.GetCol(k)
+		colMax, colMaxIdx := col.Largest(k, col.N)
+		if colMax == 0 {
+			return 0
+		}
+		if colMaxIdx != k {
+			lu.SwapRows(k, colMaxIdx)
+			det = -det
+		}
+		pivot := lu.Get(k, k)
+		det *= pivot
+		for i := k + 1; i < lu.M; i++ {
+			f := lu.Get(i, k) / pivot
+			for j := k + 1; j < lu.N; j++ {
+				lu.Set(i, j, lu.Get(i, j)-lu.Get(k, j)*f)
+			}
+		}
+	}
+	return
+}
+
+// Inverse returns the inverse of a square matrix using Gauss-Jordan elimination
+//
+// Parameters:
+//
+//	o *Mat - a square matrix
+//
+// Returns:
+//
+//	inv Mat - the inverse of o
+//	err error - ErrSquare if o is not square, ErrSingular if o is singular
+func (o *Mat) Inverse() (inv Mat, err error) {
+	if o.M != o.N {
+		err = errors.ErrSquare
+		return
+	}
+	n := o.N
+	aug := MakeMat(n, 2*n, 0)
+	copy(aug.Data, o.Data)
+	for i := 0; i < n; i++ {
+		aug.Set(i, n+i, 1)
+	}
+	for k := 0; k < n; k++ {
+		col := aug.GetCol(k)
+		colMax, colMaxIdx := col.Largest(k, col.N)
+		if colMax == 0 {
+			err = errors.ErrSingular
+			return
+		}
+		aug.SwapRows(k, colMaxIdx)
+		pivot := aug.Get(k, k)
+		for j := k; j < aug.N; j++ {
+			aug.Set(k, j, aug.Get(k, j)/pivot)
+		}
+		for i := 0; i < n; i++ {
+			if f := aug.Get(i, k); i == k || f == 0 {
+				continue
+			} else {
+				for j := k; j < aug.N; j++ {
+					aug.Set(i, j, aug.Get(i, j)-aug.Get(k, j)*f)
+				}
+			}
+		}
+	}
+	inv = MakeMat(n, n, 0)
+	copy(inv.Data, aug.Data[n*n:])
+	return
+}
+
+// SymEigen returns the eigenvalues and eigenvectors of a symmetric matrix using cyclic Jacobi rotations
+//
+// Parameters:
+//
+//	o *Mat - a symmetric matrix
+//
+// Returns:
+//
+//	vals Vec - the eigenvalues in ascending order
+//	vecs Mat - the orthonormal eigenvectors, column j belongs to vals[j]
+//	err error - ErrSquare if o is not square
+func (o *Mat) SymEigen() (vals Vec, vecs Mat, err error) {
+	if o.M != o.N {
+		err = errors.ErrSquare
+		return
+	}
+	n := o.N
+	a := o.GetCopy()
+	vecs = MakeIdentity(n)
+	for sweep := 0; sweep < 64; sweep++ {
+		var off, diag float64
+		for p := 0; p < n; p++ {
+			diag += a.Data[p+p*n] * a.Data[p+p*n]
+			for q := p + 1; q < n; q++ {
+				off += a.Data[p+q*n] * a.Data[p+q*n]
+			}
+		}
+		if off <= 1e-32*diag || off == 0 {
+			break
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				aPQ := a.Data[p+q*n]
+				if aPQ == 0 {
+					continue
+				}
+				theta := (a.Data[q+q*n] - a.Data[p+p*n]) / (2 * aPQ)
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					aKP, aKQ := a.Data[k+p*n], a.Data[k+q*n]
+					a.Data[k+p*n] = c*aKP - s*aKQ
+					a.Data[k+q*n] = s*aKP + c*aKQ
+				}
+				for k := 0; k < n; k++ {
+					aPK, aQK := a.Data[p+k*n], a.Data[q+k*n]
+					a.Data[p+k*n] = c*aPK - s*aQK
+					a.Data[q+k*n] = s*aPK + c*aQK
+				}
+				for k := 0; k < n; k++ {
+					vKP, vKQ := vecs.Data[k+p*n], vecs.Data[k+q*n]
+					vecs.Data[k+p*n] = c*vKP - s*vKQ
+					vecs.Data[k+q*n] = s*vKP + c*vKQ
+				}
+			}
+		}
+	}
+	vals = MakeVec(n, 0)
+	for i := 0; i < n; i++ {
+		vals.X[i] = a.Data[i+i*n]
+	}
+	// selection sort keeps eigenvalues and eigenvector columns paired
+	for i := 0; i < n-1; i++ {
+		k := i
+		for j := i + 1; j < n; j++ {
+			if vals.X[j] < vals.X[k] {
+				k = j
+			}
+		}
+		if k != i {
+			vals.X[i], vals.X[k] = vals.X[k], vals.X[i]
+			colI, colK := vecs.GetCol(i), vecs.GetCol(k)
+			vecs.SetCol(i, colK)
+			vecs.SetCol(k, colI)
+		}
+	}
+	return
+}
+
+// SVD returns the thin singular value decomposition o = U * diag(S) * Vᵀ using one-sided Jacobi rotations
+//
+// Parameters:
+//
+//	o *Mat - an m x n matrix
+//
+// Returns:
+//
+//	U Mat - an m x k matrix with orthonormal columns, k = min(m, n)
+//	S Vec - the k singular values in descending order
+//	V Mat - an n x k matrix with orthonormal columns
+func (o *Mat) SVD() (U Mat, S Vec, V Mat) {
+	if o.M < o.N {
+		t := o.Transpose()
+		V, S, U = t.SVD()
+		return
+	}
+	m, n := o.M, o.N
+	U = o.GetCopy()
+	V = MakeIdentity(n)
+	for sweep := 0; sweep < 64; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				var alpha, beta, gamma float64
+				for k := 0; k < m; k++ {
+					uP, uQ := U.Data[k+p*m], U.Data[k+q*m]
+					alpha += uP * uP
+					beta += uQ * uQ
+					gamma += uP * uQ
+				}
+				// columns p and q are already orthogonal
+				if math.Abs(gamma) <= 1e-15*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := c * t
+				for k := 0; k < m; k++ {
+					uP, uQ := U.Data[k+p*m], U.Data[k+q*m]
+					U.Data[k+p*m] = c*uP - s*uQ
+					U.Data[k+q*m] = s*uP + c*uQ
+				}
+				for k := 0; k < n; k++ {
+					vP, vQ := V.Data[k+p*n], V.Data[k+q*n]
+					V.Data[k+p*n] = c*vP - s*vQ
+					V.Data[k+q*n] = s*vP + c*vQ
+				}
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+	S = MakeVec(n, 0)
+	for j := 0; j < n; j++ {
+		col := U.GetCol(j)
+		S.X[j] = col.Norm()
+	}
+	// selection sort keeps singular values and singular vector columns paired
+	for i := 0; i < n-1; i++ {
+		k := i
+		for j := i + 1; j < n; j++ {
+			if S.X[j] > S.X[k] {
+				k = j
+			}
+		}
+		if k != i {
+			S.X[i], S.X[k] = S.X[k], S.X[i]
+			uI, uK := U.GetCol(i), U.GetCol(k)
+			U.SetCol(i, uK)
+			U.SetCol(k, uI)
+			vI, vK := V.GetCol(i), V.GetCol(k)
+			V.SetCol(i, vK)
+			V.SetCol(k, vI)
+		}
+	}
+	for j := 0; j < n; j++ {
+		// columns of negligible singular values are too inaccurate to normalize
+		if S.X[j] > 1e-14*S.X[0] {
+			col := U.GetCol(j)
+			U.SetCol(j, col.Scale(1/S.X[j]))
+			continue
+		}
+		// complete U with the unit vector that keeps the largest residual after
+		// orthogonalizing against the previous columns; the squared residuals of
+		// all m unit vectors sum to m - j, so the largest is at least 1/√m
+		var best Vec
+		bestNrm := -1.0
+		for i := 0; i < m; i++ {
+			e := MakeVec(m, 0)
+			e.X[i] = 1
+			for pass := 0; pass < 2; pass++ {
+				for l := 0; l < j; l++ {
+					col := U.GetCol(l)
+					e = e.Sub(col.Scale(col.Dot(e)))
+				}
+			}
+			if nrm := e.Norm(); nrm > bestNrm {
+				best, bestNrm = e, nrm
+			}
+		}
+		U.SetCol(j, best.Scale(1/bestNrm))
+	}
+	return
+}
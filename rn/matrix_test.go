@@ -157,3 +157,189 @@ func TestMatGaussSolve(t *testing.T) {
 		}
 	}
 }
+
+func TestMatTranspose(t *testing.T) {
+	for _, test := range []struct {
+		m, want Mat
+	}{
+		{Mat{M: 1, N: 2, Data: []float64{1, 2}}, Mat{M: 2, N: 1, Data: []float64{1, 2}}},
+		{Mat{M: 2, N: 2, Data: []float64{1, 2, 3, 4}}, Mat{M: 2, N: 2, Data: []float64{1, 3, 2, 4}}},
+		{Mat{M: 2, N: 3, Data: []float64{1, 2, 3, 4, 5, 6}}, Mat{M: 3, N: 2, Data: []float64{1, 3, 5, 2, 4, 6}}},
+	} {
+		got := test.m.Transpose()
+		if got.M != test.want.M || got.N != test.want.N {
+			t.Errorf("error:\ngot=%vx%v\nwant=%vx%v", got.M, got.N, test.want.M, test.want.N)
+		}
+		for k := range got.Data {
+			if got.Data[k] != test.want.Data[k] {
+				t.Errorf("error:\ngot=%v\nwant=%v", got.Data, test.want.Data)
+				break
+			}
+		}
+	}
+}
+
+func TestMatMul(t *testing.T) {
+	for _, test := range []struct {
+		a, b, want Mat
+	}{
+		{
+			Mat{M: 2, N: 2, Data: []float64{1, 3, 2, 4}},
+			Mat{M: 2, N: 2, Data: []float64{5, 7, 6, 8}},
+			Mat{M: 2, N: 2, Data: []float64{19, 43, 22, 50}},
+		},
+		{
+			Mat{M: 2, N: 3, Data: []float64{1, 4, 2, 5, 3, 6}},
+			Mat{M: 3, N: 1, Data: []float64{1, 0, -1}},
+			Mat{M: 2, N: 1, Data: []float64{-2, -2}},
+		},
+		{
+			MakeIdentity(3),
+			Mat{M: 3, N: 3, Data: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+			Mat{M: 3, N: 3, Data: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		},
+	} {
+		got := test.a.Mul(test.b)
+		for k := range got.Data {
+			if got.Data[k] != test.want.Data[k] {
+				t.Errorf("error:\ngot=%v\nwant=%v", got.Data, test.want.Data)
+				break
+			}
+		}
+		gotV := test.a.MulVec(test.b.GetCol(0))
+		if wantV := test.want.GetCol(0); !gotV.Equal(wantV) {
+			t.Errorf("error:\ngot=%v\nwant=%v", gotV.X, wantV.X)
+		}
+	}
+}
+
+func TestMatDetInverse(t *testing.T) {
+	for _, test := range []struct {
+		m    Mat
+		det  float64
+		sing bool
+	}{
+		{Mat{M: 2, N: 2, Data: []float64{4, 2, 7, 6}}, 10, false},
+		{Mat{M: 3, N: 3, Data: []float64{2, 0, 1, 1, 3, 2, 1, 1, 2}}, 6, false},
+		{Mat{M: 3, N: 3, Data: []float64{0, 1, 0, 1, 0, 0, 0, 0, 1}}, -1, false},
+		{Mat{M: 3, N: 3, Data: []float64{1, 2, 3, 2, 4, 6, 0, 1, 1}}, 0, true},
+	} {
+		got := test.m.Det()
+		if math.Abs(got-test.det) > 1e-12 {
+			t.Errorf("error:\ngot=%v\nwant=%v", got, test.det)
+		}
+		inv, err := test.m.Inverse()
+		if test.sing {
+			if err == nil {
+				t.Errorf("error:\nexpected singular matrix error")
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("error:\n%v\n", err)
+			continue
+		}
+		prod := test.m.Mul(inv)
+		id := MakeIdentity(test.m.N)
+		for k := range prod.Data {
+			if math.Abs(prod.Data[k]-id.Data[k]) > 1e-12 {
+				t.Errorf("error:\ngot=%v\nwant=%v", prod.Data, id.Data)
+				break
+			}
+		}
+	}
+}
+
+func TestMatSymEigen(t *testing.T) {
+	for _, test := range []struct {
+		m    Mat
+		want Vec
+	}{
+		{Mat{M: 2, N: 2, Data: []float64{2, 1, 1, 2}}, Vec{N: 2, X: []float64{1, 3}}},
+		{Mat{M: 3, N: 3, Data: []float64{3, 0, 0, 0, 1, 0, 0, 0, 2}}, Vec{N: 3, X: []float64{1, 2, 3}}},
+		{Mat{M: 3, N: 3, Data: []float64{4, 1, -2, 1, 2, 0, -2, 0, 3}}, Vec{N: 3, X: []float64{1, 4 - math.Sqrt(3), 4 + math.Sqrt(3)}}},
+	} {
+		vals, vecs, err := test.m.SymEigen()
+		if err != nil {
+			t.Errorf("error:\n%v\n", err)
+			continue
+		}
+		for i := range vals.X {
+			if math.Abs(vals.X[i]-test.want.X[i]) > 1e-12 {
+				t.Errorf("error:\ngot=%v\nwant=%v", vals.X, test.want.X)
+				break
+			}
+		}
+		for j := 0; j < vecs.N; j++ {
+			v := vecs.GetCol(j)
+			av := test.m.MulVec(v)
+			lv := v.Scale(vals.X[j])
+			if d := av.Dist(lv); d > 1e-12 {
+				t.Errorf("error:\nA*v != λ*v for λ=%v (|diff|=%v)", vals.X[j], d)
+			}
+		}
+	}
+}
+
+// centering returns the n x n projection I - ones/n onto the vectors with zero sum
+func centering(n int) (mat Mat) {
+	mat = MakeIdentity(n)
+	for k := range mat.Data {
+		mat.Data[k] -= 1 / float64(n)
+	}
+	return
+}
+
+func TestMatSVD(t *testing.T) {
+	for _, test := range []struct {
+		m    Mat
+		want []float64
+	}{
+		{Mat{M: 2, N: 2, Data: []float64{3, 0, 0, -4}}, []float64{4, 3}},
+		{Mat{M: 3, N: 2, Data: []float64{1, 0, 1, 0, 1, 1}}, []float64{math.Sqrt(3), 1}},
+		{Mat{M: 2, N: 3, Data: []float64{1, 0, 0, 1, 1, 1}}, []float64{math.Sqrt(3), 1}},
+		{Mat{M: 3, N: 3, Data: []float64{1, 2, 3, 2, 4, 6, 0, 1, 1}}, nil},
+		{Mat{M: 3, N: 3, Data: []float64{1, 1, 0, 1, 1, 0, 0, 0, 0}}, []float64{2, 0, 0}},
+		// rank deficient with m >= 5, where no unit vector keeps a residual above 1/2
+		{centering(5), []float64{1, 1, 1, 1, 0}},
+		{centering(6), []float64{1, 1, 1, 1, 1, 0}},
+	} {
+		U, S, V := test.m.SVD()
+		for i := range test.want {
+			if math.Abs(S.X[i]-test.want[i]) > 1e-12 {
+				t.Errorf("error:\ngot=%v\nwant=%v", S.X, test.want)
+				break
+			}
+		}
+		for i := 1; i < S.N; i++ {
+			if S.X[i] > S.X[i-1] {
+				t.Errorf("error:\nsingular values %v are not descending", S.X)
+			}
+		}
+		// U and V have orthonormal columns and U * diag(S) * Vᵀ reproduces the matrix
+		for _, Q := range []Mat{U, V} {
+			QT := Q.Transpose()
+			prod := QT.Mul(Q)
+			id := MakeIdentity(Q.N)
+			for k := range prod.Data {
+				if math.Abs(prod.Data[k]-id.Data[k]) > 1e-12 {
+					t.Errorf("error:\ngot=%v\nwant=%v", prod.Data, id.Data)
+					break
+				}
+			}
+		}
+		US := U.GetCopy()
+		for j := 0; j < US.N; j++ {
+			col := US.GetCol(j)
+			US.SetCol(j, col.Scale(S.X[j]))
+		}
+		VT := V.Transpose()
+		prod := US.Mul(VT)
+		for k := range prod.Data {
+			if math.Abs(prod.Data[k]-test.m.Data[k]) > 1e-12 {
+				t.Errorf("error:\ngot=%v\nwant=%v", prod.Data, test.m.Data)
+				break
+			}
+		}
+	}
+}
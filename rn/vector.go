@@ -239,6 +239,10 @@ func (o *Vec) Dot(q Vec) (d float64) {
 
 // Cross returns the cross product of o and q
 //
+// The binary cross product exists in 3 and 7 dimensions. In 2 dimensions the
+// result is the scalar o.X[0]*q.X[1] - o.X[1]*q.X[0] as a vector of dimension
+// 1. See CrossN for the cross product of n - 1 vectors in any dimension.
+//
 // Parameters:
 //
 //	o *Vec - vector to take the cross product of
@@ -257,6 +261,19 @@ func (o *Vec) Cross(q Vec) (u Vec) {
 	switch o.N {
 	default:
 		panic(errors.ErrOrder)
+	case 2:
+		u = Vec{N: 1, X: []float64{o.X[0]*q.X[1] - o.X[1]*q.X[0]}}
+		return
+	case 7:
+		// e_i × e_(i+1) = e_(i+3) with indices modulo 7, and cyclic permutations of each triple
+		u = Vec{N: 7, X: make([]float64, 7)}
+		for i := 0; i < 7; i++ {
+			a, b, c := i, (i+1)%7, (i+3)%7
+			u.X[c] += o.X[a]*q.X[b] - o.X[b]*q.X[a]
+			u.X[a] += o.X[b]*q.X[c] - o.X[c]*q.X[b]
+			u.X[b] += o.X[c]*q.X[a] - o.X[a]*q.X[c]
+		}
+		return
 	case 3:
 		u = Vec{N: 3, X: make([]float64, 3)}
 		u.X[0] = (o.X[1] * q.X[2]) - (o.X[2] * q.X[1])
@@ -231,6 +231,10 @@ func TestVecCross(t *testing.T) {
 		{Vec{3, []float64{1, 0, 0}}, Vec{3, []float64{0, 1, 0}}, Vec{3, []float64{0, 0, 1}}},
 		{Vec{3, []float64{8, 2, 5}}, Vec{3, []float64{5, 1, 3}}, Vec{3, []float64{1, 1, -2}}},
 		{Vec{3, []float64{5, 1, 3}}, Vec{3, []float64{8, 2, 5}}, Vec{3, []float64{-1, -1, 2}}},
+		{Vec{2, []float64{1, 0}}, Vec{2, []float64{0, 1}}, Vec{1, []float64{1}}},
+		{Vec{2, []float64{3, 1}}, Vec{2, []float64{2, 4}}, Vec{1, []float64{10}}},
+		{Vec{7, []float64{1, 0, 0, 0, 0, 0, 0}}, Vec{7, []float64{0, 1, 0, 0, 0, 0, 0}}, Vec{7, []float64{0, 0, 0, 1, 0, 0, 0}}},
+		{Vec{7, []float64{0, 0, 0, 0, 0, 0, 1}}, Vec{7, []float64{1, 0, 0, 0, 0, 0, 0}}, Vec{7, []float64{0, 0, 1, 0, 0, 0, 0}}},
 	} {
 		got := test.v1.Cross(test.v2)
 		if !got.Equal(test.want) {
@@ -0,0 +1,139 @@
+package robust
+
+import "math"
+
+// expansion is an exact sum of nonoverlapping float64 components in order of increasing magnitude
+//
+// The arithmetic follows Shewchuk, Adaptive Precision Floating-Point
+// Arithmetic and Fast Robust Geometric Predicates, 1997, but the predicates
+// use only a floating point filter followed by a full exact evaluation, not
+// the paper's adaptive stages. The sign of an expansion is the sign of its
+// last component, and the float64 sum of its components has the same sign.
+type expansion []float64
+
+// twoSum returns x = fl(a + b) and the rounding error y, so that a + b = x + y exactly
+func twoSum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	y = (a - av) + (b - bv)
+	return
+}
+
+// twoProd returns x = fl(a * b) and the rounding error y, so that a * b = x + y exactly
+func twoProd(a, b float64) (x, y float64) {
+	x = a * b
+	y = math.FMA(a, b, -x)
+	return
+}
+
+// diff returns the exact difference a - b
+func diff(a, b float64) expansion {
+	x, y := twoSum(a, -b)
+	if y == 0 {
+		return expansion{x}
+	}
+	return expansion{y, x}
+}
+
+// grow returns the exact sum of e and b
+func grow(e expansion, b float64) (h expansion) {
+	q := b
+	for _, x := range e {
+		var t float64
+		if q, t = twoSum(q, x); t != 0 {
+			h = append(h, t)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return
+}
+
+// sum returns the exact sum of e and f
+func sum(e, f expansion) (h expansion) {
+	h = e
+	for _, x := range f {
+		h = grow(h, x)
+	}
+	return
+}
+
+// scale returns the exact product of e and b
+func scale(e expansion, b float64) (h expansion) {
+	q, t := twoProd(e[0], b)
+	if t != 0 {
+		h = append(h, t)
+	}
+	for _, x := range e[1:] {
+		p1, p0 := twoProd(x, b)
+		var s float64
+		if s, t = twoSum(q, p0); t != 0 {
+			h = append(h, t)
+		}
+		if q, t = twoSum(p1, s); t != 0 {
+			h = append(h, t)
+		}
+	}
+	if q != 0 || len(h) == 0 {
+		h = append(h, q)
+	}
+	return
+}
+
+// mul returns the exact product of e and f
+func mul(e, f expansion) (h expansion) {
+	h = expansion{0}
+	for _, x := range f {
+		h = sum(h, scale(e, x))
+	}
+	return
+}
+
+// neg returns -e
+func neg(e expansion) (h expansion) {
+	h = make(expansion, len(e))
+	for i, x := range e {
+		h[i] = -x
+	}
+	return
+}
+
+// estimate returns the float64 sum of the components, it has the sign of e
+func (e expansion) estimate() (x float64) {
+	for _, c := range e {
+		x += c
+	}
+	return
+}
+
+// det returns the exact determinant of a square matrix of expansions (Laplace expansion along the first row)
+func det(m [][]expansion) (d expansion) {
+	n := len(m)
+	if n == 1 {
+		return m[0][0]
+	}
+	d = expansion{0}
+	for j := 0; j < n; j++ {
+		minor := make([][]expansion, n-1)
+		for i := 1; i < n; i++ {
+			minor[i-1] = append(append([]expansion{}, m[i][:j]...), m[i][j+1:]...)
+		}
+		term := mul(m[0][j], det(minor))
+		if j%2 == 1 {
+			term = neg(term)
+		}
+		d = sum(d, term)
+	}
+	return
+}
+
+// lift returns the exact squared length of the vector with components xs
+func lift(xs ...expansion) (h expansion) {
+	h = expansion{0}
+	for _, x := range xs {
+		h = sum(h, mul(x, x))
+	}
+	return
+}
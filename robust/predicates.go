@@ -0,0 +1,200 @@
+package robust
+
+import "math"
+
+// epsilon is half the distance between 1 and the next float64
+const epsilon = 1.0 / (1 << 53)
+
+// error bounds of the floating point determinants relative to their permanents
+const (
+	orient2DErrBound = (3 + 16*epsilon) * epsilon
+	orient3DErrBound = (7 + 56*epsilon) * epsilon
+	inCircleErrBound = (10 + 96*epsilon) * epsilon
+	inSphereErrBound = (16 + 224*epsilon) * epsilon
+)
+
+// Orient2D returns the orientation of the triangle (a, b, c)
+//
+// The determinant is first evaluated in floating point; only when its
+// magnitude is within the rounding error bound is it recomputed exactly,
+// so the sign of the result is always correct. Like all predicates in this
+// package it is exact for every finite input: coordinates so small or
+// differences so large that products of them could underflow or overflow
+// are evaluated in rational arithmetic instead.
+//
+// Parameters:
+//
+//	a [2]float64 - The first point
+//	b [2]float64 - The second point
+//	c [2]float64 - The third point
+//
+// Returns:
+//
+//	det float64 - An approximation of twice the signed area of (a, b, c) with the exact sign:
+//	positive if the points are counterclockwise, negative if clockwise, 0 if collinear
+func Orient2D(a, b, c [2]float64) (det float64) {
+	if !inRange(c[:], a[:], b[:]) {
+		det = rational(c[:], false, a[:], b[:])
+		return
+	}
+	l := (a[0] - c[0]) * (b[1] - c[1])
+	r := (a[1] - c[1]) * (b[0] - c[0])
+	det = l - r
+	if math.Abs(det) > orient2DErrBound*(math.Abs(l)+math.Abs(r)) {
+		return
+	}
+	m := [][]expansion{
+		{diff(a[0], c[0]), diff(a[1], c[1])},
+		{diff(b[0], c[0]), diff(b[1], c[1])},
+	}
+	det = exact(m)
+	return
+}
+
+// exact evaluates the determinant of m exactly and returns an approximation with its sign
+func exact(m [][]expansion) float64 {
+	d := det(m)
+	return d.estimate()
+}
+
+// Orient3D returns the orientation of the tetrahedron (a, b, c, d)
+//
+// Parameters:
+//
+//	a [3]float64 - The first point
+//	b [3]float64 - The second point
+//	c [3]float64 - The third point
+//	d [3]float64 - The fourth point
+//
+// Returns:
+//
+//	det float64 - An approximation of six times the signed volume with the exact sign:
+//	positive if d lies below the plane through a, b and c, where below means that
+//	a, b and c appear counterclockwise when viewed from above; 0 if the points are coplanar
+func Orient3D(a, b, c, d [3]float64) (det float64) {
+	if !inRange(d[:], a[:], b[:], c[:]) {
+		det = rational(d[:], false, a[:], b[:], c[:])
+		return
+	}
+	adx, ady, adz := a[0]-d[0], a[1]-d[1], a[2]-d[2]
+	bdx, bdy, bdz := b[0]-d[0], b[1]-d[1], b[2]-d[2]
+	cdx, cdy, cdz := c[0]-d[0], c[1]-d[1], c[2]-d[2]
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	det = adz*(bdxcdy-cdxbdy) + bdz*(cdxady-adxcdy) + cdz*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz) +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bdz) +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cdz)
+	if math.Abs(det) > orient3DErrBound*permanent {
+		return
+	}
+	m := make([][]expansion, 3)
+	for i, P := range [][3]float64{a, b, c} {
+		m[i] = []expansion{diff(P[0], d[0]), diff(P[1], d[1]), diff(P[2], d[2])}
+	}
+	det = exact(m)
+	return
+}
+
+// InCircle returns where d lies relative to the circle through a, b and c
+//
+// Parameters:
+//
+//	a [2]float64 - The first point on the circle
+//	b [2]float64 - The second point on the circle
+//	c [2]float64 - The third point on the circle
+//	d [2]float64 - The query point
+//
+// Returns:
+//
+//	det float64 - An approximation of the incircle determinant with the exact sign:
+//	positive if d lies inside the circle, negative if outside, 0 if on it,
+//	provided that (a, b, c) is counterclockwise; the signs flip otherwise
+func InCircle(a, b, c, d [2]float64) (det float64) {
+	if !inRange(d[:], a[:], b[:], c[:]) {
+		det = rational(d[:], true, a[:], b[:], c[:])
+		return
+	}
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+	det = alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if math.Abs(det) > inCircleErrBound*permanent {
+		return
+	}
+	m := make([][]expansion, 3)
+	for i, P := range [][2]float64{a, b, c} {
+		x, y := diff(P[0], d[0]), diff(P[1], d[1])
+		m[i] = []expansion{x, y, lift(x, y)}
+	}
+	det = exact(m)
+	return
+}
+
+// InSphere returns where e lies relative to the sphere through a, b, c and d
+//
+// Parameters:
+//
+//	a [3]float64 - The first point on the sphere
+//	b [3]float64 - The second point on the sphere
+//	c [3]float64 - The third point on the sphere
+//	d [3]float64 - The fourth point on the sphere
+//	e [3]float64 - The query point
+//
+// Returns:
+//
+//	det float64 - An approximation of the insphere determinant with the exact sign:
+//	positive if e lies inside the sphere, negative if outside, 0 if on it,
+//	provided that Orient3D(a, b, c, d) is positive; the signs flip otherwise
+func InSphere(a, b, c, d, e [3]float64) (det float64) {
+	if !inRange(e[:], a[:], b[:], c[:], d[:]) {
+		det = rational(e[:], true, a[:], b[:], c[:], d[:])
+		return
+	}
+	aex, aey, aez := a[0]-e[0], a[1]-e[1], a[2]-e[2]
+	bex, bey, bez := b[0]-e[0], b[1]-e[1], b[2]-e[2]
+	cex, cey, cez := c[0]-e[0], c[1]-e[1], c[2]-e[2]
+	dex, dey, dez := d[0]-e[0], d[1]-e[1], d[2]-e[2]
+	aexbey, bexaey := aex*bey, bex*aey
+	bexcey, cexbey := bex*cey, cex*bey
+	cexdey, dexcey := cex*dey, dex*cey
+	dexaey, aexdey := dex*aey, aex*dey
+	aexcey, cexaey := aex*cey, cex*aey
+	bexdey, dexbey := bex*dey, dex*bey
+	ab, bc, cd, da := aexbey-bexaey, bexcey-cexbey, cexdey-dexcey, dexaey-aexdey
+	ac, bd := aexcey-cexaey, bexdey-dexbey
+	abc := aez*bc - bez*ac + cez*ab
+	bcd := bez*cd - cez*bd + dez*bc
+	cda := cez*da + dez*ac + aez*cd
+	dab := dez*ab + aez*bd + bez*da
+	alift := aex*aex + aey*aey + aez*aez
+	blift := bex*bex + bey*bey + bez*bez
+	clift := cex*cex + cey*cey + cez*cez
+	dlift := dex*dex + dey*dey + dez*dez
+	det = (dlift*abc - clift*dab) + (blift*cda - alift*bcd)
+	abs := math.Abs
+	permanent := ((abs(cexdey)+abs(dexcey))*abs(bez)+(abs(dexbey)+abs(bexdey))*abs(cez)+(abs(bexcey)+abs(cexbey))*abs(dez))*alift +
+		((abs(dexaey)+abs(aexdey))*abs(cez)+(abs(aexcey)+abs(cexaey))*abs(dez)+(abs(cexdey)+abs(dexcey))*abs(aez))*blift +
+		((abs(aexbey)+abs(bexaey))*abs(dez)+(abs(bexdey)+abs(dexbey))*abs(aez)+(abs(dexaey)+abs(aexdey))*abs(bez))*clift +
+		((abs(bexcey)+abs(cexbey))*abs(aez)+(abs(cexaey)+abs(aexcey))*abs(bez)+(abs(aexbey)+abs(bexaey))*abs(cez))*dlift
+	if abs(det) > inSphereErrBound*permanent {
+		return
+	}
+	m := make([][]expansion, 4)
+	for i, P := range [][3]float64{a, b, c, d} {
+		x, y, z := diff(P[0], e[0]), diff(P[1], e[1]), diff(P[2], e[2])
+		m[i] = []expansion{x, y, z, lift(x, y, z)}
+	}
+	det = exact(m)
+	return
+}
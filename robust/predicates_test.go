@@ -0,0 +1,164 @@
+package robust
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func sgn(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// nearly returns x moved by a few units in the last place
+func nearly(rng *rand.Rand, x float64) float64 {
+	for k := rng.Intn(5) - 2; k != 0; {
+		if k > 0 {
+			x, k = math.Nextafter(x, math.Inf(1)), k-1
+		} else {
+			x, k = math.Nextafter(x, math.Inf(-1)), k+1
+		}
+	}
+	return x
+}
+
+func TestOrient2D(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for k := 0; k < 2000; k++ {
+		// c lies close to the line through a and b
+		a := [2]float64{rng.Float64(), rng.Float64()}
+		b := [2]float64{rng.Float64() * 100, rng.Float64() * 100}
+		s := rng.Float64()
+		c := [2]float64{nearly(rng, a[0]+s*(b[0]-a[0])), nearly(rng, a[1]+s*(b[1]-a[1]))}
+		want := ratDet(ratRows(c[:], false, a[:], b[:])).Sign()
+		if got := sgn(Orient2D(a, b, c)); got != want {
+			t.Errorf("error: %v %v %v\ngot=%v\nwant=%v", a, b, c, got, want)
+		}
+	}
+	if got := Orient2D([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{0, 1}); got != 1 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 1)
+	}
+}
+
+func TestOrient3D(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for k := 0; k < 2000; k++ {
+		// d lies close to the plane through a, b and c
+		var a, b, c, d [3]float64
+		s, u := rng.Float64(), rng.Float64()
+		for i := 0; i < 3; i++ {
+			a[i], b[i], c[i] = rng.Float64(), 10*rng.Float64(), 100*rng.Float64()
+			d[i] = nearly(rng, a[i]+s*(b[i]-a[i])+u*(c[i]-a[i]))
+		}
+		want := ratDet(ratRows(d[:], false, a[:], b[:], c[:])).Sign()
+		if got := sgn(Orient3D(a, b, c, d)); got != want {
+			t.Errorf("error: %v %v %v %v\ngot=%v\nwant=%v", a, b, c, d, got, want)
+		}
+	}
+	a, b, c := [3]float64{0, 0, 0}, [3]float64{1, 0, 0}, [3]float64{0, 1, 0}
+	if got := Orient3D(a, b, c, [3]float64{0, 0, -1}); got <= 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "positive below the plane")
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for k := 0; k < 2000; k++ {
+		// d lies close to the unit circle through a, b and c
+		var Ps [4][2]float64
+		for i := range Ps {
+			phi := 2 * math.Pi * rng.Float64()
+			Ps[i] = [2]float64{nearly(rng, math.Cos(phi)), nearly(rng, math.Sin(phi))}
+		}
+		a, b, c, d := Ps[0], Ps[1], Ps[2], Ps[3]
+		want := ratDet(ratRows(d[:], true, a[:], b[:], c[:])).Sign()
+		if got := sgn(InCircle(a, b, c, d)); got != want {
+			t.Errorf("error: %v %v %v %v\ngot=%v\nwant=%v", a, b, c, d, got, want)
+		}
+	}
+	// exactly cocircular points on a grid
+	if got := InCircle([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}, [2]float64{0, 1}); got != 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 0)
+	}
+	if got := InCircle([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{0, 1}, [2]float64{0.5, 0.5}); got <= 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "positive inside")
+	}
+}
+
+func TestInSphere(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for k := 0; k < 500; k++ {
+		// e lies close to the unit sphere through a, b, c and d
+		var Ps [5][3]float64
+		for i := range Ps {
+			z, phi := 2*rng.Float64()-1, 2*math.Pi*rng.Float64()
+			r := math.Sqrt(1 - z*z)
+			Ps[i] = [3]float64{nearly(rng, r*math.Cos(phi)), nearly(rng, r*math.Sin(phi)), nearly(rng, z)}
+		}
+		a, b, c, d, e := Ps[0], Ps[1], Ps[2], Ps[3], Ps[4]
+		want := ratDet(ratRows(e[:], true, a[:], b[:], c[:], d[:])).Sign()
+		if got := sgn(InSphere(a, b, c, d, e)); got != want {
+			t.Errorf("error: %v %v %v %v %v\ngot=%v\nwant=%v", a, b, c, d, e, got, want)
+		}
+	}
+	a, b, c, d := [3]float64{1, 0, 0}, [3]float64{0, 1, 0}, [3]float64{0, 0, 1}, [3]float64{-1, 0, 0}
+	if Orient3D(a, b, c, d) < 0 {
+		a, b = b, a
+	}
+	if got := InSphere(a, b, c, d, [3]float64{0, 0, 0}); got <= 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "positive inside")
+	}
+	if got := InSphere(a, b, c, d, [3]float64{0, -1, 0}); got != 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, 0)
+	}
+}
+
+func TestExtremeRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	// scales exactly by a power of two, so the configuration keeps its exact signs
+	scale := func(P []float64, k int) {
+		for i := range P {
+			P[i] = math.Ldexp(P[i], k)
+		}
+	}
+	for _, k := range []int{-1000, -700, -200, 200, 700, 1000} {
+		for n := 0; n < 50; n++ {
+			var Ps [5][3]float64
+			for i := range Ps {
+				for j := range Ps[i] {
+					Ps[i][j] = float64(rng.Intn(5) - 2)
+				}
+				scale(Ps[i][:], k)
+			}
+			a, b, c, d, e := Ps[0], Ps[1], Ps[2], Ps[3], Ps[4]
+			a2, b2, c2, d2 := [2]float64{a[0], a[1]}, [2]float64{b[0], b[1]}, [2]float64{c[0], c[1]}, [2]float64{d[0], d[1]}
+			tests := []struct {
+				got  float64
+				want int
+			}{
+				{Orient2D(a2, b2, c2), ratDet(ratRows(c2[:], false, a2[:], b2[:])).Sign()},
+				{Orient3D(a, b, c, d), ratDet(ratRows(d[:], false, a[:], b[:], c[:])).Sign()},
+				{InCircle(a2, b2, c2, d2), ratDet(ratRows(d2[:], true, a2[:], b2[:], c2[:])).Sign()},
+				{InSphere(a, b, c, d, e), ratDet(ratRows(e[:], true, a[:], b[:], c[:], d[:])).Sign()},
+			}
+			for i, test := range tests {
+				if got := sgn(test.got); got != test.want {
+					t.Errorf("error: predicate %d, scale 2^%d\ngot=%v\nwant=%v", i, k, got, test.want)
+				}
+			}
+		}
+	}
+	// points near 1 whose offsets are far below the rounding error of their coordinates
+	if got := Orient2D([2]float64{1, 1e-300}, [2]float64{2, 0}, [2]float64{1, 0}); got >= 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "negative")
+	}
+	if got := Orient2D([2]float64{1e-200, 0}, [2]float64{0, 1e-200}, [2]float64{}); got <= 0 {
+		t.Errorf("error:\ngot=%v\nwant=%v", got, "positive")
+	}
+}
@@ -0,0 +1,83 @@
+package robust
+
+import (
+	"math"
+	"math/big"
+)
+
+// the expansion arithmetic is exact if every nonzero coordinate is at least minCoord and every
+// coordinate difference at most maxDiff in magnitude: the components of a difference are then
+// multiples of minCoord * 2⁻⁵², so no product of up to five of them underflows, and no product
+// of up to five differences overflows
+const (
+	minCoord = 0x1p-140
+	maxDiff  = 0x1p140
+)
+
+// inRange returns true if the expansion arithmetic is exact for the rows P - Q of a predicate
+func inRange(Q []float64, Ps ...[]float64) bool {
+	for _, x := range Q {
+		if x != 0 && !(math.Abs(x) >= minCoord) {
+			return false
+		}
+	}
+	for _, P := range Ps {
+		for i, x := range P {
+			if x != 0 && !(math.Abs(x) >= minCoord) || !(math.Abs(x-Q[i]) <= maxDiff) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ratRows returns the rows P - Q of a predicate matrix in rational arithmetic, with the
+// squared length appended if lifted
+func ratRows(Q []float64, lifted bool, Ps ...[]float64) (m [][]*big.Rat) {
+	for _, P := range Ps {
+		var row []*big.Rat
+		l := new(big.Rat)
+		for i := range P {
+			x := new(big.Rat).SetFloat64(P[i])
+			x.Sub(x, new(big.Rat).SetFloat64(Q[i]))
+			row = append(row, x)
+			l.Add(l, new(big.Rat).Mul(x, x))
+		}
+		if lifted {
+			row = append(row, l)
+		}
+		m = append(m, row)
+	}
+	return
+}
+
+// ratDet returns the determinant of a square rational matrix (Laplace expansion along the first row)
+func ratDet(m [][]*big.Rat) (d *big.Rat) {
+	if len(m) == 1 {
+		return m[0][0]
+	}
+	d = new(big.Rat)
+	for j := range m {
+		var minor [][]*big.Rat
+		for _, row := range m[1:] {
+			minor = append(minor, append(append([]*big.Rat{}, row[:j]...), row[j+1:]...))
+		}
+		term := new(big.Rat).Mul(m[0][j], ratDet(minor))
+		if j%2 == 1 {
+			term.Neg(term)
+		}
+		d.Add(d, term)
+	}
+	return
+}
+
+// rational evaluates the determinant of the rows P - Q in rational arithmetic and returns the
+// nearest float64, or the smallest float64 of the same sign if that underflows to zero
+func rational(Q []float64, lifted bool, Ps ...[]float64) (det float64) {
+	d := ratDet(ratRows(Q, lifted, Ps...))
+	det, _ = d.Float64()
+	if det == 0 && d.Sign() != 0 {
+		det = math.Copysign(math.SmallestNonzeroFloat64, float64(d.Sign()))
+	}
+	return
+}
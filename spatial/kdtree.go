@@ -0,0 +1,270 @@
+package spatial
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// KDTree is a k-d tree over a set of points of equal dimension
+//
+// The tree is stored implicitly: the median of every index range is the
+// node of that range and the halves on either side are its subtrees.
+type KDTree struct {
+	Points []rn.Vec
+	Metric Metric
+	perm   []int // perm[k] is the index into Points of the node at position k
+	axes   []int // axes[k] is the splitting axis of the node at position k
+}
+
+// MakeKDTree builds a balanced k-d tree over points
+//
+// Parameters:
+//
+//	points []rn.Vec - The points, all of the same dimension
+//	metric Metric - The distance used by the queries
+//
+// Returns:
+//
+//	tree KDTree - The tree, it references but does not modify points
+func MakeKDTree(points []rn.Vec, metric Metric) (tree KDTree) {
+	tree.Points = points
+	tree.Metric = metric
+	tree.perm = make([]int, len(points))
+	tree.axes = make([]int, len(points))
+	for i, P := range points {
+		if P.N != points[0].N {
+			panic(errors.ErrShape)
+		}
+		tree.perm[i] = i
+	}
+	tree.build(0, len(points))
+	return
+}
+
+// Len returns the number of points in the tree
+//
+// Parameters:
+//
+//	o *KDTree - The tree
+//
+// Returns:
+//
+//	n int - The number of points
+func (o *KDTree) Len() (n int) {
+	n = len(o.perm)
+	return
+}
+
+func (o *KDTree) build(lo, hi int) {
+	if hi-lo < 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	axis := o.widestAxis(lo, hi)
+	o.selectNth(lo, hi, mid, axis)
+	o.axes[mid] = axis
+	o.build(lo, mid)
+	o.build(mid+1, hi)
+}
+
+// widestAxis returns the coordinate with the largest spread in perm[lo:hi]
+func (o *KDTree) widestAxis(lo, hi int) (axis int) {
+	best := -1.0
+	for i := 0; i < o.Points[o.perm[lo]].N; i++ {
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, p := range o.perm[lo:hi] {
+			min = math.Min(min, o.Points[p].X[i])
+			max = math.Max(max, o.Points[p].X[i])
+		}
+		if max-min > best {
+			best, axis = max-min, i
+		}
+	}
+	return
+}
+
+// selectNth reorders perm[lo:hi] so that perm[n] holds the point that would be there
+// if the range were sorted along axis, with no larger values before and no smaller after it
+func (o *KDTree) selectNth(lo, hi, n, axis int) {
+	at := func(k int) float64 { return o.Points[o.perm[k]].X[axis] }
+	for hi-lo > 1 {
+		// median of three pivot
+		a, b, c := at(lo), at((lo+hi)/2), at(hi-1)
+		pivot := math.Max(math.Min(a, b), math.Min(math.Max(a, b), c))
+		// three-way partition into perm[lo:lt] < pivot, perm[lt:gt] == pivot and
+		// perm[gt:hi] > pivot, so many equal coordinates do not make it quadratic
+		lt, gt := lo, hi
+		for k := lo; k < gt; {
+			switch x := at(k); {
+			case x < pivot:
+				o.perm[k], o.perm[lt] = o.perm[lt], o.perm[k]
+				lt++
+				k++
+			case x > pivot:
+				gt--
+				o.perm[k], o.perm[gt] = o.perm[gt], o.perm[k]
+			default:
+				k++
+			}
+		}
+		switch {
+		case n < lt:
+			hi = lt
+		case n >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// Nearest returns the point closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//
+// Returns:
+//
+//	idx int - The index into Points of the nearest point, -1 if the tree is empty
+//	dist float64 - The distance between P and the nearest point
+func (o *KDTree) Nearest(P rn.Vec) (idx int, dist float64) {
+	idx, dist = -1, math.Inf(1)
+	if ids, dists := o.KNearest(P, 1); len(ids) == 1 {
+		idx, dist = ids[0], dists[0]
+	}
+	return
+}
+
+// KNearest returns the k points closest to P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//	k int - The number of neighbors
+//
+// Returns:
+//
+//	idx []int - The indices into Points of the min(k, Len()) nearest points, nearest first
+//	dist []float64 - Their distances to P
+func (o *KDTree) KNearest(P rn.Vec, k int) (idx []int, dist []float64) {
+	if k < 1 || len(o.perm) == 0 {
+		return
+	}
+	if P.N != o.Points[0].N {
+		panic(errors.ErrShape)
+	}
+	h := &maxHeap{}
+	o.kNearest(P, k, 0, len(o.perm), h)
+	n := h.Len()
+	idx, dist = make([]int, n), make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		e := heap.Pop(h).(neighbor)
+		idx[i], dist[i] = e.idx, e.dist
+	}
+	return
+}
+
+func (o *KDTree) kNearest(P rn.Vec, k, lo, hi int, h *maxHeap) {
+	if hi-lo < 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := o.perm[mid]
+	if d := o.Metric.Dist(P, o.Points[p]); h.Len() < k {
+		heap.Push(h, neighbor{p, d})
+	} else if d < (*h)[0].dist {
+		(*h)[0] = neighbor{p, d}
+		heap.Fix(h, 0)
+	}
+	// the coordinate difference along the splitting axis is a lower
+	// bound of the distance to every point on the far side in L1, L2 and L∞
+	diff := P.X[o.axes[mid]] - o.Points[p].X[o.axes[mid]]
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+	if diff > 0 {
+		nearLo, nearHi, farLo, farHi = mid+1, hi, lo, mid
+	}
+	o.kNearest(P, k, nearLo, nearHi, h)
+	if h.Len() < k || math.Abs(diff) < (*h)[0].dist {
+		o.kNearest(P, k, farLo, farHi, h)
+	}
+}
+
+// Radius returns all points within distance r of P
+//
+// Parameters:
+//
+//	P rn.Vec - The query point
+//	r float64 - The search radius, points at exactly distance r are included
+//
+// Returns:
+//
+//	idx []int - The indices into Points of the points found, nearest first
+//	dist []float64 - Their distances to P
+func (o *KDTree) Radius(P rn.Vec, r float64) (idx []int, dist []float64) {
+	if len(o.perm) == 0 {
+		return
+	}
+	if P.N != o.Points[0].N {
+		panic(errors.ErrShape)
+	}
+	var found []neighbor
+	o.radius(P, r, 0, len(o.perm), &found)
+	sort.Slice(found, func(a, b int) bool {
+		if found[a].dist != found[b].dist {
+			return found[a].dist < found[b].dist
+		}
+		return found[a].idx < found[b].idx
+	})
+	idx, dist = make([]int, len(found)), make([]float64, len(found))
+	for i, e := range found {
+		idx[i], dist[i] = e.idx, e.dist
+	}
+	return
+}
+
+func (o *KDTree) radius(P rn.Vec, r float64, lo, hi int, found *[]neighbor) {
+	if hi-lo < 1 {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := o.perm[mid]
+	if d := o.Metric.Dist(P, o.Points[p]); d <= r {
+		*found = append(*found, neighbor{p, d})
+	}
+	diff := P.X[o.axes[mid]] - o.Points[p].X[o.axes[mid]]
+	if diff <= r {
+		o.radius(P, r, lo, mid, found)
+	}
+	if -diff <= r {
+		o.radius(P, r, mid+1, hi, found)
+	}
+}
+
+type neighbor struct {
+	idx  int
+	dist float64
+}
+
+// maxHeap keeps the k best candidates with the worst one on top
+type maxHeap []neighbor
+
+func (h maxHeap) Len() int { return len(h) }
+func (h maxHeap) Less(a, b int) bool {
+	if h[a].dist != h[b].dist {
+		return h[a].dist > h[b].dist
+	}
+	return h[a].idx > h[b].idx
+}
+func (h maxHeap) Swap(a, b int)       { h[a], h[b] = h[b], h[a] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(neighbor)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
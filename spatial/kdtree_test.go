@@ -0,0 +1,137 @@
+package spatial
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/add1609/lin/rn"
+)
+
+func randomPoints(rng *rand.Rand, n, dim int) (points []rn.Vec) {
+	for k := 0; k < n; k++ {
+		P := rn.MakeVec(dim, 0)
+		for i := range P.X {
+			P.X[i] = rng.Float64()*10 - 5
+		}
+		points = append(points, P)
+	}
+	return
+}
+
+func bruteForce(points []rn.Vec, P rn.Vec, metric Metric) (idx []int, dist []float64) {
+	idx = make([]int, len(points))
+	dist = make([]float64, len(points))
+	for i := range points {
+		idx[i] = i
+		dist[i] = metric.Dist(P, points[i])
+	}
+	sort.Slice(idx, func(a, b int) bool { return dist[idx[a]] < dist[idx[b]] })
+	sorted := make([]float64, len(idx))
+	for i, k := range idx {
+		sorted[i] = dist[k]
+	}
+	dist = sorted
+	return
+}
+
+func TestMetricDist(t *testing.T) {
+	p, q := rn.Vec{N: 3, X: []float64{1, 2, 3}}, rn.Vec{N: 3, X: []float64{4, -2, 3}}
+	for _, test := range []struct {
+		metric Metric
+		want   float64
+	}{
+		{L2, 5},
+		{L1, 7},
+		{LInf, 4},
+	} {
+		if got := test.metric.Dist(p, q); got != test.want {
+			t.Errorf("error:\n%v:\ngot=%v\nwant=%v", test.metric, got, test.want)
+		}
+	}
+}
+
+func TestKDTreeKNearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, dim := range []int{1, 2, 3, 7} {
+		points := randomPoints(rng, 1000, dim)
+		for _, metric := range []Metric{L2, L1, LInf} {
+			tree := MakeKDTree(points, metric)
+			for k := 0; k < 50; k++ {
+				P := randomPoints(rng, 1, dim)[0]
+				wantIdx, wantDist := bruteForce(points, P, metric)
+				idx, dist := tree.KNearest(P, 10)
+				for i := range idx {
+					if idx[i] != wantIdx[i] || dist[i] != wantDist[i] {
+						t.Fatalf("error:\ndim=%v %v:\ngot=%v %v\nwant=%v %v", dim, metric, idx, dist, wantIdx[:10], wantDist[:10])
+					}
+				}
+				if i, d := tree.Nearest(P); i != wantIdx[0] || d != wantDist[0] {
+					t.Errorf("error:\ngot=%v %v\nwant=%v %v", i, d, wantIdx[0], wantDist[0])
+				}
+			}
+		}
+	}
+}
+
+func TestKDTreeRadius(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	points := randomPoints(rng, 2000, 3)
+	for _, metric := range []Metric{L2, L1, LInf} {
+		tree := MakeKDTree(points, metric)
+		for k := 0; k < 50; k++ {
+			P := randomPoints(rng, 1, 3)[0]
+			wantIdx, wantDist := bruteForce(points, P, metric)
+			n := 0
+			for n < len(wantDist) && wantDist[n] <= 1.5 {
+				n++
+			}
+			idx, dist := tree.Radius(P, 1.5)
+			if len(idx) != n {
+				t.Fatalf("error:\n%v:\ngot=%v points\nwant=%v points", metric, len(idx), n)
+			}
+			for i := range idx {
+				if idx[i] != wantIdx[i] || dist[i] != wantDist[i] {
+					t.Fatalf("error:\ngot=%v\nwant=%v", idx, wantIdx[:n])
+				}
+			}
+		}
+	}
+}
+
+func TestKDTreeDuplicates(t *testing.T) {
+	// 100000 points on two values of x and one of y build in n log n
+	var points []rn.Vec
+	for k := 0; k < 100000; k++ {
+		points = append(points, rn.Vec{N: 2, X: []float64{float64(k % 2), 0}})
+	}
+	tree := MakeKDTree(points, L2)
+	for _, test := range []struct {
+		P    rn.Vec
+		want float64
+	}{
+		{rn.Vec{N: 2, X: []float64{-1, 0}}, 0},
+		{rn.Vec{N: 2, X: []float64{2, 0}}, 1},
+	} {
+		if i, d := tree.Nearest(test.P); points[i].X[0] != test.want || d != 1 {
+			t.Errorf("error:\ngot=%v %v\nwant=%v %v", points[i], d, test.want, 1)
+		}
+	}
+	if idx, _ := tree.Radius(rn.Vec{N: 2, X: []float64{0, 0}}, 0.5); len(idx) != 50000 {
+		t.Errorf("error:\ngot=%v points\nwant=%v points", len(idx), 50000)
+	}
+}
+
+func TestKDTreeSmall(t *testing.T) {
+	var tree KDTree
+	tree = MakeKDTree(nil, L2)
+	if i, _ := tree.Nearest(rn.Vec{N: 2, X: []float64{0, 0}}); i != -1 {
+		t.Errorf("error:\ngot=%v\nwant=%v", i, -1)
+	}
+	points := []rn.Vec{{N: 2, X: []float64{1, 1}}, {N: 2, X: []float64{1, 1}}, {N: 2, X: []float64{3, 0}}}
+	tree = MakeKDTree(points, L2)
+	idx, _ := tree.KNearest(rn.Vec{N: 2, X: []float64{0, 0}}, 5)
+	if len(idx) != 3 || idx[2] != 2 {
+		t.Errorf("error:\ngot=%v", idx)
+	}
+}
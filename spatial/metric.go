@@ -0,0 +1,62 @@
+package spatial
+
+import (
+	"math"
+
+	"github.com/add1609/lin/errors"
+	"github.com/add1609/lin/rn"
+)
+
+// Metric selects the distance function used by spatial queries
+type Metric int
+
+const (
+	L2   Metric = iota // Euclidean distance, the same as rn.Vec.Dist
+	L1                 // Manhattan distance, the sum of absolute coordinate differences
+	LInf               // Chebyshev distance, the largest absolute coordinate difference
+)
+
+func (m Metric) String() string {
+	switch m {
+	case L2:
+		return "L2"
+	case L1:
+		return "L1"
+	case LInf:
+		return "L∞"
+	}
+	return "Metric(?)"
+}
+
+// Dist returns the distance between p and q under the metric m
+//
+// Parameters:
+//
+//	p rn.Vec - The first point
+//	q rn.Vec - The second point
+//
+// Returns:
+//
+//	dist float64 - The distance between p and q
+func (m Metric) Dist(p, q rn.Vec) (dist float64) {
+	if p.N != q.N {
+		panic(errors.ErrShape)
+	}
+	switch m {
+	case L1:
+		for i := range p.X {
+			dist += math.Abs(p.X[i] - q.X[i])
+		}
+	case LInf:
+		for i := range p.X {
+			dist = math.Max(dist, math.Abs(p.X[i]-q.X[i]))
+		}
+	default:
+		for i := range p.X {
+			d := p.X[i] - q.X[i]
+			dist += d * d
+		}
+		dist = math.Sqrt(dist)
+	}
+	return
+}